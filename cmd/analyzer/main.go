@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/np-guard/vpc-network-config-analyzer/cmd/analyzer/subcmds"
@@ -26,5 +27,10 @@ func main() {
 	if err != nil {
 		logging.Init(logging.MediumVerbosity) // just in case it wasn't initialized earlier
 		logging.Errorf("%v. exiting...", err)
+		// e.g. lint findings, whose severity determines the exit code
+		var exitCodeErr interface{ ExitCode() int }
+		if errors.As(err, &exitCodeErr) {
+			os.Exit(exitCodeErr.ExitCode())
+		}
 	}
 }
@@ -34,6 +34,10 @@ func TestMain(t *testing.T) {
 			name: "aws_all_subnets",
 			args: "report subnets -f aws.txt -c ../../pkg/awsvpc/examples/input/input_aws_acl_1.json -o txt",
 		},
+		{
+			name: "aws_terraform_all_endpoints",
+			args: "report endpoints -c ../../pkg/awsvpc/examples/input/input_tf_aws.json -o txt",
+		},
 		// drawio
 		{
 			name: "drawio_multi_vpc_all_subnets",
@@ -43,11 +47,51 @@ func TestMain(t *testing.T) {
 			name: "drawio_multi_vpc_all_subnets_grouped",
 			args: "report subnets -f multi_vpc_grouped.drawio -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o=drawio --grouping",
 		},
+		{
+			name: "drawio_multi_vpc_all_endpoints_zone_view",
+			args: "report endpoints -f multi_vpc_zone_view.drawio -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o drawio --view-level zone",
+		},
+		{
+			name: "drawio_multi_vpc_all_subnets_vpc_view",
+			args: "report subnets -f multi_vpc_vpc_view.drawio -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o drawio --view-level vpc",
+		},
+		{
+			// seeded by the drawio file generated by the test above
+			name: "drawio_multi_vpc_all_subnets_layout_seed",
+			args: "report subnets -f multi_vpc_seeded.drawio -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o drawio --layout-seed multi_vpc_vpc_view.drawio",
+		},
 		{
 			name: "txt_multi_vpc",
 			args: "report subnets -f multi_vpc.txt --config ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o txt",
 		},
 
+		// grouping by user-defined labels
+		{
+			name: "txt_multi_vpc_grouping_labels_file",
+			args: "report subnets -f multi_vpc_labels.txt -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o txt --grouping --group-by-file ../../pkg/ibmvpc/examples/input/grouping_labels_multiple_vpcs.json",
+		},
+		{
+			name: "synthesis_multi_vpc_grouping_labels_file",
+			args: "report subnets -f multi_vpc_labels.json -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o synthesis --grouping --group-by-file ../../pkg/ibmvpc/examples/input/grouping_labels_multiple_vpcs.json",
+		},
+		{
+			// groups of vsis in several subnets are split to their subnets on the map
+			name: "drawio_grouping_labels_regex",
+			args: "report endpoints -f sg_testing1_labels.drawio -c ../../pkg/ibmvpc/examples/input/input_sg_testing1_new_grouping.json -o drawio --grouping --group-by-name ^[a-z]*",
+		},
+		{
+			name: "json_multi_vpc_grouping_labels_file",
+			args: "report subnets -f multi_vpc_labels.json -c ../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json -o json --grouping --group-by-file ../../pkg/ibmvpc/examples/input/grouping_labels_multiple_vpcs.json",
+		},
+		{
+			name: "json_endpoints_grouping",
+			args: "report endpoints -f sg_testing1_grouped.json -c ../../pkg/ibmvpc/examples/input/input_sg_testing1_new_grouping.json -o json --grouping",
+		},
+		{
+			name: "aws_md_grouping_labels_tag",
+			args: "report endpoints -f aws_labels.md -c ../../pkg/awsvpc/examples/input/input_aws_mixed.json -o md --grouping --group-by-tag Name",
+		},
+
 		// diff analysis_type
 		{
 			name: "txt_diff_acl_testing5",
@@ -65,6 +109,10 @@ func TestMain(t *testing.T) {
 			name: "md_diff_acl_testing3",
 			args: "diff endpoints -f acl_testing3_diff.md --config ../../pkg/ibmvpc/examples/input/input_acl_testing3.json --config-second ../../pkg/ibmvpc/examples/input/input_acl_testing3_2nd.json -o md",
 		},
+		{
+			name: "txt_diff_terraform_state_plan",
+			args: "diff endpoints --config ../../pkg/ibmvpc/examples/input/input_tf_ibm.json --config-second ../../pkg/ibmvpc/examples/input/input_tf_ibm_2nd.json",
+		},
 
 		// all_subnets analysis_type
 		{
@@ -103,6 +151,66 @@ func TestMain(t *testing.T) {
 			name: "txt_single_subnet_acl_testing5",
 			args: "report single-subnet -f acl_testing5_single_subnet.txt -c ../../pkg/ibmvpc/examples/input/input_acl_testing5.json -o txt",
 		},
+		{
+			name: "md_single_subnet_grouping_acl_testing5",
+			args: "report single-subnet -f acl_testing5_single_subnet.md -c ../../pkg/ibmvpc/examples/input/input_acl_testing5.json -o md -g",
+		},
+		{
+			name: "json_single_subnet_acl_testing5",
+			args: "report single-subnet -f acl_testing5_single_subnet.json -c ../../pkg/ibmvpc/examples/input/input_acl_testing5.json -o json",
+		},
+
+		// filters analysis_type
+		{
+			name: "txt_filters_sg_testing1_new",
+			args: "report filters -f sg_testing1_new_filters.txt -c ../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json -o txt",
+		},
+		{
+			name: "json_filters_multi_vpc",
+			args: "report filters -f tgw_larger_example_filters.json -c ../../pkg/ibmvpc/examples/input/input_tgw_larger_example.json -o json",
+		},
+
+		// rule-usage analysis_type
+		{
+			name: "md_rule_usage_acl_testing5",
+			args: "report rule-usage -f acl_testing5_rule_usage.md -c ../../pkg/ibmvpc/examples/input/input_acl_testing5.json -o md",
+		},
+		{
+			name: "txt_rule_usage_multi_vpc",
+			args: "report rule-usage -f tgw_larger_example_rule_usage.txt -c ../../pkg/ibmvpc/examples/input/input_tgw_larger_example.json",
+		},
+
+		// lint
+		{
+			name: "json_lint_tgw_larger_example",
+			args: "lint -f tgw_larger_example_lint.json -c ../../pkg/ibmvpc/examples/input/input_tgw_larger_example.json -o json",
+		},
+
+		// exposure analysis_type
+		{
+			name: "txt_exposure_acl_testing3",
+			args: "report exposure -f acl_testing3_exposure.txt -c ../../pkg/ibmvpc/examples/input/input_acl_testing3.json -o txt",
+		},
+		{
+			name: "json_exposure_acl_testing3",
+			args: "report exposure -f acl_testing3_exposure.json -c ../../pkg/ibmvpc/examples/input/input_acl_testing3.json -o json",
+		},
+
+		// blast-radius analysis_type
+		{
+			name: "txt_blast_radius_sg_testing1_new",
+			args: "report blast-radius -f sg_testing1_new_blast_radius.txt -c ../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json --src vsi2-ky --protocol tcp --dst-min-port 22 --dst-max-port 22",
+		},
+
+		// segmentation analysis_type
+		{
+			name: "md_segmentation_sg_testing1_new",
+			args: "report segmentation -f sg_testing1_new_segmentation.md -c ../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json -o md --zoning-file ../../pkg/ibmvpc/examples/input/zoning_sg_testing1_new.json",
+		},
+		{
+			name: "json_segmentation_tgw_larger_example",
+			args: "report segmentation -f tgw_larger_example_segmentation.json -c ../../pkg/ibmvpc/examples/input/input_tgw_larger_example.json -o json --zoning-file ../../pkg/ibmvpc/examples/input/zoning_tgw_larger_example.json",
+		},
 
 		// explain_mode analysis_type
 		{
@@ -117,6 +225,10 @@ func TestMain(t *testing.T) {
 			name: "txt_explain_acl_testing3_3rd",
 			args: "explain -f acl_testing3_3rd_explain.txt -c ../../pkg/ibmvpc/examples/input/input_acl_testing3_3rd.json -o txt --src vsi1-ky --dst 161.26.0.0/16 --protocol tcp --src-min-port 5 --src-max-port 4398",
 		},
+		{
+			name: "remediation_explain_acl_testing3",
+			args: "explain -f acl_testing3_explain_remediation.txt -c ../../pkg/ibmvpc/examples/input/input_acl_testing3.json --src vsi3a-ky --dst vsi1-ky --protocol tcp --dst-min-port 443 --dst-max-port 443 --remediation",
+		},
 
 		// specific vpc
 		{
@@ -156,10 +268,23 @@ func TestMain(t *testing.T) {
 			name: "diff_with_different_uid",
 			args: "diff endpoints --quiet --config ../../pkg/ibmvpc/examples/input/input_sg_testing_default.json --config-second ../../pkg/ibmvpc/examples/input/input_sg_testing_3.json",
 		},
+		{
+			name: "diff_with_patch",
+			args: "diff endpoints --quiet --config ../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json --patch ../../pkg/ibmvpc/examples/input/patch_sg_testing1_new.yaml",
+		},
 		{
 			name: "test_routing_cmd",
 			args: "report routing --config ../../pkg/ibmvpc/examples/input/input_hub_n_spoke_1.json",
 		},
+		{
+			name: "drawio_routing_cmd",
+			args: "report routing -f hub_n_spoke_routing.drawio --config ../../pkg/ibmvpc/examples/input/input_hub_n_spoke_1.json -o drawio --src 192.168.2.4 --dst 10.1.0.4",
+		},
+		{
+			// seeded by the drawio file generated by the test above
+			name: "drawio_routing_cmd_layout_seed",
+			args: "report routing -f hub_n_spoke_routing_seeded.drawio --config ../../pkg/ibmvpc/examples/input/input_hub_n_spoke_1.json -o drawio --src 192.168.2.4 --dst 10.1.0.4 --layout-seed hub_n_spoke_routing.drawio",
+		},
 
 		// read from account // need to export api-key first
 		/*{
@@ -205,6 +330,12 @@ func TestMainWithExpectedOut(t *testing.T) {
 			args:    "explain -f acl_testing3_detailed_explain.txt -c ../../pkg/ibmvpc/examples/input/input_acl_testing3.json -o txt --src 10.240.10.4 --dst vsi2-ky --detail",
 			outFile: "acl_testing3_detailed_explain.txt",
 		},
+		// grouping endpoints by user-defined labels in synthesis format
+		{
+			name:    "synthesis_endpoints_grouping_labels_tag",
+			args:    "report endpoints -f acl_testing3_labels_synthesis.json -c ../../pkg/ibmvpc/examples/input/input_acl_testing3_tags.json -o synthesis --grouping --group-by-tag tier",
+			outFile: "acl_testing3_labels_synthesis.json",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -272,8 +403,115 @@ func TestCommandsFailExecute(t *testing.T) {
 		},
 		{
 			name:                  "wrong_analysis_type_format",
-			args:                  []string{"report", "single-subnet", "--config", "../../pkg/ibmvpc/examples/input/input_multi_resource_groups.json", "-o", "md"},
-			expectedErrorContains: "output format for single-subnet must be one of [txt]",
+			args:                  []string{"report", "single-subnet", "--config", "../../pkg/ibmvpc/examples/input/input_multi_resource_groups.json", "-o", "drawio"},
+			expectedErrorContains: "output format for single-subnet must be one of [txt, md, json]",
+		},
+		{
+			name:                  "single_subnet_grouping_by_labels",
+			args:                  []string{"report", "single-subnet", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json", "-g", "--group-by-name", "(.*)-ky"},
+			expectedErrorContains: "single-subnet analysis type groups remote cidrs, thus does not support grouping by labels",
+		},
+		{
+			name:                  "wrong_routing_format",
+			args:                  []string{"report", "routing", "--config", "../../pkg/ibmvpc/examples/input/input_hub_n_spoke_1.json", "-o", "md"},
+			expectedErrorContains: "output format for routing must be one of [txt, drawio, svg, html]",
+		},
+		{
+			name:                  "view_level_with_routing",
+			args:                  []string{"report", "routing", "--config", "../../pkg/ibmvpc/examples/input/input_hub_n_spoke_1.json", "-o", "drawio", "--view-level", "zone"},
+			expectedErrorContains: "--view-level is not supported by routing analysis type",
+		},
+		{
+			name:                  "wrong_filters_format",
+			args:                  []string{"report", "filters", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json", "-o", "drawio"},
+			expectedErrorContains: "output format for filters must be one of [txt, md, json]",
+		},
+		{
+			name:                  "filters_with_grouping",
+			args:                  []string{"report", "filters", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json", "-g"},
+			expectedErrorContains: "currently filters analysis type does not support grouping",
+		},
+		{
+			name: "lint_config_fail_on_warning",
+			args: []string{"lint", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3_with_redundant_rules.json",
+				"--lint-config", "../../pkg/ibmvpc/examples/input/lint_config_acl_testing3_with_redundant_rules.yaml"},
+			expectedErrorContains: "lint found issues of severity error",
+		},
+		{
+			name: "lint_config_no_justification",
+			args: []string{"lint", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json",
+				"--lint-config", "../../pkg/ibmvpc/examples/input/lint_config_no_justification.yaml"},
+			expectedErrorContains: "has no justification",
+		},
+		{
+			name: "lint_config_illegal_sensitive_ports",
+			args: []string{"lint", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json",
+				"--lint-config", "../../pkg/ibmvpc/examples/input/lint_config_illegal_sensitive_ports.yaml"},
+			expectedErrorContains: "parameter sensitive-ports: illegal port range \"ssh\"",
+		},
+		{
+			name: "lint_config_illegal_large_cidr_prefix",
+			args: []string{"lint", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json",
+				"--lint-config", "../../pkg/ibmvpc/examples/input/lint_config_illegal_large_cidr_prefix.yaml",
+				"--enable", "sg-rule-overly-permissive"},
+			expectedErrorContains: "parameter large-cidr-prefix: illegal prefix length \"33\"",
+		},
+		{
+			name:                  "wrong_lint_format",
+			args:                  []string{"lint", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json", "-o", "md"},
+			expectedErrorContains: "output format for lint must be one of [txt, json]",
+		},
+		{
+			name:                  "wrong_rule_usage_format",
+			args:                  []string{"report", "rule-usage", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json", "-o", "svg"},
+			expectedErrorContains: "output format for rule-usage must be one of [txt, md, json]",
+		},
+		{
+			name:                  "rule_usage_with_grouping",
+			args:                  []string{"report", "rule-usage", "--config", "../../pkg/ibmvpc/examples/input/input_acl_testing3.json", "-g"},
+			expectedErrorContains: "currently rule-usage analysis type does not support grouping",
+		},
+		{
+			name:                  "segmentation_without_zoning_file",
+			args:                  []string{"report", "segmentation", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json", "-o", "md"},
+			expectedErrorContains: "required flag(s) \"zoning-file\" not set",
+		},
+		{
+			name: "wrong_segmentation_format",
+			args: []string{"report", "segmentation", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json", "-o", "drawio",
+				"--zoning-file", "../../pkg/ibmvpc/examples/input/zoning_sg_testing1_new.json"},
+			expectedErrorContains: "output format for segmentation must be one of [txt, md, json, html]",
+		},
+		{
+			name: "segmentation_zoning_file_without_zones",
+			args: []string{"report", "segmentation", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json",
+				"--zoning-file", "../../pkg/ibmvpc/examples/input/grouping_labels_multiple_vpcs.json"},
+			expectedErrorContains: "has no zones",
+		},
+		{
+			name:                  "group_by_without_grouping",
+			args:                  []string{"report", "subnets", "--config", "../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json", "--group-by-tag", "tier"},
+			expectedErrorContains: "--group-by-tag is supported only with --grouping",
+		},
+		{
+			name:                  "group_by_invalid_regex",
+			args:                  []string{"report", "subnets", "--config", "../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json", "--grouping", "--group-by-name", "(tier"},
+			expectedErrorContains: "invalid grouping regex",
+		},
+		{
+			name:                  "view_level_with_text_format",
+			args:                  []string{"report", "endpoints", "--config", "../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json", "--view-level", "zone"},
+			expectedErrorContains: "--view-level is supported only with output formats",
+		},
+		{
+			name:                  "layout_seed_with_text_format",
+			args:                  []string{"report", "subnets", "--config", "../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json", "--layout-seed", "seed.drawio"},
+			expectedErrorContains: "--layout-seed is supported only with output formats",
+		},
+		{
+			name:                  "layout_seed_not_a_drawio_file",
+			args:                  []string{"report", "subnets", "--config", "../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json", "-o", "drawio", "--layout-seed", "../../pkg/ibmvpc/examples/input/input_multiple_vpcs.json"},
+			expectedErrorContains: "has no elements generated by the analyzer",
 		},
 		{
 			name:                  "src_and_dst_not_specified_for_explain_mode",
@@ -283,7 +521,17 @@ func TestCommandsFailExecute(t *testing.T) {
 		{
 			name:                  "missing_sec_vpc_config_for_diff_analysis",
 			args:                  []string{"diff", "subnets", "--config", "../../pkg/ibmvpc/examples/input/input_multi_resource_groups.json"},
-			expectedErrorContains: "required flag(s) \"config-second\" not set",
+			expectedErrorContains: "at least one of the flags in the group [config-second patch] is required",
+		},
+		{
+			name:                  "diff_patch_unknown_field",
+			args:                  []string{"diff", "endpoints", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json", "--patch", "../../pkg/ibmvpc/examples/input/patch_unknown_field.yaml"},
+			expectedErrorContains: "field rule_nmae not found",
+		},
+		{
+			name:                  "diff_patch_missing_field",
+			args:                  []string{"diff", "endpoints", "--config", "../../pkg/ibmvpc/examples/input/input_sg_testing1_new.json", "--patch", "../../pkg/ibmvpc/examples/input/patch_missing_field.yaml"},
+			expectedErrorContains: "is missing the field new_rule.remote",
 		},
 		{
 			name:                  "nacls_split_subnets",
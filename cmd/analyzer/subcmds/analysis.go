@@ -48,12 +48,36 @@ func routingAnalysis(inArgs *inArgs) error {
 	if err != nil {
 		return err
 	}
-	for _, pair := range srcDstPairs {
-		if err := pairRoutingAnalysis(pair.Src, pair.Dst, analyzer); err != nil {
-			return err
+	outFormat := inArgs.outputFormat.ToModelFormat()
+	if outFormat == vpcmodel.Text {
+		for _, pair := range srcDstPairs {
+			if err := pairRoutingAnalysis(pair.Src, pair.Dst, analyzer); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
 
+	paths := make([]*vpcmodel.RoutingPathInfo, len(srcDstPairs))
+	for i, pair := range srcDstPairs {
+		path, errPath := analyzer.GetRoutingPath(pair.Src.(vpcmodel.InternalNodeIntf), pair.Dst.IPBlock())
+		if errPath != nil {
+			return errPath
+		}
+		paths[i] = &vpcmodel.RoutingPathInfo{Src: pair.Src, Dst: pair.Dst, Path: path}
+	}
+	layoutSeed, err := readLayoutSeed(inArgs)
+	if err != nil {
+		return err
+	}
+	routingOut, err := vpcmodel.RoutingPathsDrawio(vpcConfigs, paths, outFormat, inArgs.outputFile, inArgs.lbAbstraction,
+		layoutSeed)
+	if err != nil {
+		return fmt.Errorf("output generation error: %w", err)
+	}
+	if inArgs.outputFile == "" {
+		fmt.Println(routingOut)
+	}
 	return nil
 }
 
@@ -79,6 +103,13 @@ func analysisVPCConfigs(cmd *cobra.Command, inArgs *inArgs, analysisType vpcmode
 	if err != nil {
 		return err
 	}
+	labels, err := groupingLabels(inArgs)
+	if err != nil {
+		return err
+	}
+	if labels != nil {
+		vpcConfigs.SetGroupingLabels(labels)
+	}
 	outFormat := inArgs.outputFormat.ToModelFormat()
 	consistencyEdgesExternal := slices.Contains([]vpcmodel.OutFormat{vpcmodel.DRAWIO, vpcmodel.SVG, vpcmodel.HTML},
 		outFormat)
@@ -93,14 +124,30 @@ func analysisVPCConfigs(cmd *cobra.Command, inArgs *inArgs, analysisType vpcmode
 	default:
 		groupingType = vpcmodel.GroupingWithConsistencyEdges
 	}
+	viewLevel := inArgs.viewLevel.ToModelViewLevel()
+	// on a collapsed map, the load balancers are collapsed with the subnets of their private IPs
+	lbAbstraction := inArgs.lbAbstraction && viewLevel == vpcmodel.EndpointViewLevel
 	og, err := vpcmodel.NewOutputGenerator(vpcConfigs,
 		groupingType,
 		analysisType,
 		false,
-		inArgs.explanationArgs, outFormat, inArgs.lbAbstraction)
+		inArgs.explanationArgs, outFormat, lbAbstraction)
 	if err != nil {
 		return err
 	}
+	og.SetViewLevel(viewLevel)
+	layoutSeed, err := readLayoutSeed(inArgs)
+	if err != nil {
+		return err
+	}
+	og.SetLayoutSeed(layoutSeed)
+	if inArgs.zoningFile != "" {
+		zoning, errZoning := vpcmodel.NewZoningModelFromFile(inArgs.zoningFile)
+		if errZoning != nil {
+			return errZoning
+		}
+		og.SetZoningModel(zoning)
+	}
 
 	analysisOut, err := og.Generate(outFormat, inArgs.outputFile)
 	if err != nil {
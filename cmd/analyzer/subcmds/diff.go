@@ -12,7 +12,10 @@ import (
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
 )
 
-const secondConfigFlag = "config-second"
+const (
+	secondConfigFlag = "config-second"
+	patchFlag        = "patch"
+)
 
 func NewDiffCommand(args *inArgs) *cobra.Command {
 	const diffCmd = "diff"
@@ -20,14 +23,23 @@ func NewDiffCommand(args *inArgs) *cobra.Command {
 		Use:   diffCmd,
 		Short: "Diff connectivity postures as implied by two VPC configs",
 		Long: `Report changes in connectivity (modified, added and removed connections)
-		between two VPC configurations`,
+		between two VPC configurations, or between a VPC configuration and a patched copy of it`,
 		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
 			return validateFormatForMode(diffCmd, []formatSetting{textFormat, mdFormat}, args)
 		},
 	}
 
 	cmd.PersistentFlags().StringVar(&args.inputSecondConfigFile, secondConfigFlag, "", "file path to the 2nd input config")
-	_ = cmd.MarkPersistentFlagRequired(secondConfigFlag)
+	cmd.PersistentFlags().StringVar(&args.patchFile, patchFlag, "",
+		"file path to a yaml/json patch of changes to apply on the input config; the patched config is the 2nd config.\n"+
+			"The patch holds a list of changes, each with an op and the names of the resources it refers to:\n"+
+			"add-sg-rule (sg, new_rule), remove-sg-rule (sg, rule_id), attach-sg/detach-sg (sg, instance),\n"+
+			"add-nacl-rule (nacl, new_rule, optional before), remove-nacl-rule (nacl, rule_name), set-subnet-nacl (subnet, nacl),\n"+
+			"attach-pgw (subnet, pgw), detach-pgw (subnet).\n"+
+			"A new_rule has direction, protocol and optional ports/icmp fields; an sg rule also has remote and optional local,\n"+
+			"a nacl rule also has source, destination and optional name and action")
+	cmd.MarkFlagsOneRequired(secondConfigFlag, patchFlag)
+	cmd.MarkFlagsMutuallyExclusive(secondConfigFlag, patchFlag)
 
 	cmd.AddCommand(newDiffEndpointsCommand(args))
 	cmd.AddCommand(newDiffSubnetsCommand(args))
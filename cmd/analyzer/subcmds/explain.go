@@ -25,6 +25,7 @@ const (
 	dstMinPortFlag = "dst-min-port"
 	dstMaxPortFlag = "dst-max-port"
 	detailFlag     = "detail"
+	remediateFlag  = "remediation"
 
 	srcDstUsage = "endpoint; can be specified as a VSI/subnet name/CRN or an internal/external IP-address/CIDR;\n" +
 		"VSI/subnet name can be specified as <vsi-name/subnet-name> or as <vpc-name>/<vsi-name/subnet-name>"
@@ -42,18 +43,17 @@ func NewExplainCommand(args *inArgs) *cobra.Command {
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			args.explanationArgs = vpcmodel.NewExplanationArgs(args.eSrc, args.eDst, args.eProtocol.String(),
 				args.eSrcMinPort, args.eSrcMaxPort, args.eDstMinPort, args.eDstMaxPort, args.detailExplain)
+			args.explanationArgs.Remediate = args.remediation
 			return analysisVPCConfigs(cmd, args, vpcmodel.Explain)
 		},
 	}
 
 	cmd.Flags().StringVar(&args.eSrc, srcFlag, "", "source "+srcDstUsage)
 	cmd.Flags().StringVar(&args.eDst, dstFlag, "", "destination "+srcDstUsage)
-	cmd.Flags().Var(&args.eProtocol, protocolFlag, "protocol for connection description")
-	cmd.Flags().Int64Var(&args.eSrcMinPort, srcMinPortFlag, netp.MinPort, "minimum source port for connection description")
-	cmd.Flags().Int64Var(&args.eSrcMaxPort, srcMaxPortFlag, netp.MaxPort, "maximum source port for connection description")
-	cmd.Flags().Int64Var(&args.eDstMinPort, dstMinPortFlag, netp.MinPort, "minimum destination port for connection description")
-	cmd.Flags().Int64Var(&args.eDstMaxPort, dstMaxPortFlag, netp.MaxPort, "maximum destination port for connection description")
+	addConnectionFlags(cmd, args)
 	cmd.Flags().BoolVar(&args.detailExplain, detailFlag, false, "adds a section with a list of all relevant allow/deny rules")
+	cmd.Flags().BoolVar(&args.remediation, remediateFlag, false,
+		"adds a section proposing the SG/NACL rules to add for enabling the queried connection, also as a patch")
 
 	_ = cmd.MarkFlagRequired(srcFlag)
 	_ = cmd.MarkFlagRequired(dstFlag)
@@ -62,6 +62,15 @@ func NewExplainCommand(args *inArgs) *cobra.Command {
 	return cmd
 }
 
+// addConnectionFlags adds to cmd the protocol and ports flags describing a connection
+func addConnectionFlags(cmd *cobra.Command, args *inArgs) {
+	cmd.Flags().Var(&args.eProtocol, protocolFlag, "protocol for connection description")
+	cmd.Flags().Int64Var(&args.eSrcMinPort, srcMinPortFlag, netp.MinPort, "minimum source port for connection description")
+	cmd.Flags().Int64Var(&args.eSrcMaxPort, srcMaxPortFlag, netp.MaxPort, "maximum source port for connection description")
+	cmd.Flags().Int64Var(&args.eDstMinPort, dstMinPortFlag, netp.MinPort, "minimum destination port for connection description")
+	cmd.Flags().Int64Var(&args.eDstMaxPort, dstMaxPortFlag, netp.MaxPort, "maximum destination port for connection description")
+}
+
 func portInRange(port int64) bool {
 	if port > netp.MaxPort || port < netp.MinPort {
 		return false
@@ -91,7 +100,11 @@ func validateExplainFlags(cmd *cobra.Command, args *inArgs) error {
 	if err != nil {
 		return err
 	}
+	return validateConnectionFlags(cmd, args)
+}
 
+// validateConnectionFlags validates the protocol and ports flags describing a connection
+func validateConnectionFlags(cmd *cobra.Command, args *inArgs) error {
 	if args.eProtocol == "" {
 		if FlagSet(cmd, srcMinPortFlag) || FlagSet(cmd, srcMaxPortFlag) ||
 			FlagSet(cmd, dstMinPortFlag) || FlagSet(cmd, dstMaxPortFlag) {
@@ -99,7 +112,7 @@ func validateExplainFlags(cmd *cobra.Command, args *inArgs) error {
 		}
 	}
 
-	err = minMaxValidity(args.eSrcMinPort, args.eSrcMaxPort, srcMinPortFlag, srcMaxPortFlag)
+	err := minMaxValidity(args.eSrcMinPort, args.eSrcMaxPort, srcMinPortFlag, srcMaxPortFlag)
 	if err != nil {
 		return err
 	}
@@ -19,6 +19,7 @@ const (
 	enable       = "enable"
 	disable      = "disable"
 	printAllFlag = "print-all"
+	lintConfig   = "lint-config"
 )
 
 func NewLintCommand(args *inArgs) *cobra.Command {
@@ -40,6 +41,8 @@ func NewLintCommand(args *inArgs) *cobra.Command {
 	cmd.Flags().StringSliceVar(&args.disableLinters, disable, []string{}, disable+usageStr)
 	cmd.Flags().BoolVar(&args.printAllLinters, printAllFlag, false, "print all findings (do not limit "+
 		"findings of each linter to 3)")
+	cmd.Flags().StringVar(&args.lintConfigFile, lintConfig, "", "file path to a yaml lint config, setting per linter "+
+		"its severity, parameters and suppressions of findings")
 	return cmd
 }
 
@@ -51,14 +54,22 @@ func lintVPCConfigs(cmd *cobra.Command, args *inArgs) error {
 	if err1 != nil {
 		return err1
 	}
+	var lintConfigContent *linter.LintConfig
+	if args.lintConfigFile != "" {
+		var err error
+		lintConfigContent, err = linter.ReadLintConfig(args.lintConfigFile)
+		if err != nil {
+			return err
+		}
+	}
 	// potential errors already handled
 	_, err2 := linter.LinterExecute(multiConfigs.Configs(), args.printAllLinters,
-		args.enableLinters, args.disableLinters)
+		args.enableLinters, args.disableLinters, lintConfigContent, args.outputFormat.ToModelFormat(), args.outputFile)
 	return err2
 }
 
 func validateLintFlags(cmd *cobra.Command, args *inArgs) error {
-	errFormat := validateFormatForMode(cmd.Use, []formatSetting{textFormat}, args)
+	errFormat := validateFormatForMode(cmd.Use, []formatSetting{textFormat, jsonFormat}, args)
 	if errFormat != nil {
 		return errFormat
 	}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subcmds
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/drawio"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+const (
+	viewLevelFlag  = "view-level"
+	layoutSeedFlag = "layout-seed"
+)
+
+type viewLevelSetting string
+
+const (
+	vpcViewLevel      viewLevelSetting = "vpc"
+	zoneViewLevel     viewLevelSetting = "zone"
+	subnetViewLevel   viewLevelSetting = "subnet"
+	endpointViewLevel viewLevelSetting = "endpoint"
+)
+
+var allViewLevels = []string{string(vpcViewLevel), string(zoneViewLevel), string(subnetViewLevel), string(endpointViewLevel)}
+
+func (vl *viewLevelSetting) String() string {
+	return string(*vl)
+}
+
+func (vl *viewLevelSetting) Set(v string) error {
+	v = strings.ToLower(v)
+	if slices.Contains(allViewLevels, v) {
+		*vl = viewLevelSetting(v)
+		return nil
+	}
+	return fmt.Errorf("%s", mustBeOneOf(allViewLevels))
+}
+
+func (vl *viewLevelSetting) Type() string {
+	return stringType
+}
+
+func (vl *viewLevelSetting) ToModelViewLevel() vpcmodel.ViewLevel {
+	switch *vl {
+	case vpcViewLevel:
+		return vpcmodel.VpcViewLevel
+	case zoneViewLevel:
+		return vpcmodel.ZoneViewLevel
+	case subnetViewLevel:
+		return vpcmodel.SubnetViewLevel
+	}
+	return vpcmodel.EndpointViewLevel
+}
+
+// addMapFlags adds the flags of the drawio, svg and html maps
+func addMapFlags(cmd *cobra.Command, args *inArgs) {
+	cmd.Flags().Var(&args.viewLevel, viewLevelFlag,
+		"for drawio, svg and html output formats, the lowest level shown on the map, lower levels are collapsed; "+
+			mustBeOneOf(allViewLevels))
+	cmd.Flags().StringVar(&args.layoutSeedFile, layoutSeedFlag, "",
+		"for drawio, svg and html output formats, file path of a drawio file generated by a previous run, whose layout is kept")
+}
+
+// validateMapFlags checks that the map flags are used only with a graphic output format
+func validateMapFlags(args *inArgs) error {
+	graphicFormats := []formatSetting{drawioFormat, archDrawioFormat, svgFormat, archSVGFormat, htmlFormat, archHTMLFormat}
+	if slices.Contains(graphicFormats, args.outputFormat) {
+		return nil
+	}
+	setFlags := []string{}
+	if args.viewLevel.ToModelViewLevel() != vpcmodel.EndpointViewLevel {
+		setFlags = append(setFlags, viewLevelFlag)
+	}
+	if args.layoutSeedFile != "" {
+		setFlags = append(setFlags, layoutSeedFlag)
+	}
+	if len(setFlags) > 0 {
+		return fmt.Errorf("--%s is supported only with output formats [%s]", setFlags[0],
+			strings.Join(toStringArray(graphicFormats), ", "))
+	}
+	return nil
+}
+
+// readLayoutSeed reads the layout seed file given by the user, if any
+func readLayoutSeed(args *inArgs) (drawio.LayoutSeed, error) {
+	if args.layoutSeedFile == "" {
+		return nil, nil
+	}
+	return drawio.ReadLayoutSeed(args.layoutSeedFile)
+}
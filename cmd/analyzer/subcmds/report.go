@@ -17,7 +17,11 @@ import (
 
 const (
 	groupingFlag                = "grouping"
+	groupByTagFlag              = "group-by-tag"
+	groupByNameFlag             = "group-by-name"
+	groupByFileFlag             = "group-by-file"
 	loadBalancerAbstractionFlag = "load-balancer-abstraction"
+	zoningFileFlag              = "zoning-file"
 )
 
 func NewReportCommand(args *inArgs) *cobra.Command {
@@ -26,15 +30,19 @@ func NewReportCommand(args *inArgs) *cobra.Command {
 		Short: "Report VPC connectivity as implied by the given cloud config",
 		Long:  `Report VPC connectivity as implied by the given cloud configuration`,
 		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
-			if args.grouping && args.outputFormat == jsonFormat {
-				return fmt.Errorf("json output format is not supported with grouping")
-			}
-			return nil
+			return validateGroupByFlags(args)
 		},
 	}
 
 	cmd.PersistentFlags().BoolVarP(&args.grouping, groupingFlag,
 		"g", false, "whether to group together endpoints sharing the same connectivity")
+	cmd.PersistentFlags().StringVar(&args.groupByTag, groupByTagFlag, "",
+		"with grouping, group together endpoints by the value of their cloud resource tag with this key")
+	cmd.PersistentFlags().StringVar(&args.groupByName, groupByNameFlag, "",
+		"with grouping, group together endpoints by the match of this regex on their names (or its first capturing group)")
+	cmd.PersistentFlags().StringVar(&args.groupByFile, groupByFileFlag, "",
+		"with grouping, group together endpoints by the labels of a json file mapping each label to resource names or uids")
+	cmd.MarkFlagsMutuallyExclusive(groupByTagFlag, groupByNameFlag, groupByFileFlag)
 	cmd.PersistentFlags().BoolVarP(&args.lbAbstraction, loadBalancerAbstractionFlag,
 		"", true, "whether to abstract a load balancer to one endpoint")
 	hideFlagsFromHelp(cmd, []string{loadBalancerAbstractionFlag})
@@ -43,32 +51,74 @@ func NewReportCommand(args *inArgs) *cobra.Command {
 	cmd.AddCommand(newReportSubnetsCommand(args))
 	cmd.AddCommand(newReportSingleSubnetCommand(args))
 	cmd.AddCommand(newReportRoutingCommand(args))
+	cmd.AddCommand(newReportExposureCommand(args))
+	cmd.AddCommand(newReportFiltersCommand(args))
+	cmd.AddCommand(newReportRuleUsageCommand(args))
+	cmd.AddCommand(newReportBlastRadiusCommand(args))
+	cmd.AddCommand(newReportSegmentationCommand(args))
 
 	return cmd
 }
 
+// validateGroupByFlags checks that endpoints are grouped by user-defined labels only with grouping
+func validateGroupByFlags(args *inArgs) error {
+	if args.grouping {
+		return nil
+	}
+	for flag, value := range map[string]string{groupByTagFlag: args.groupByTag, groupByNameFlag: args.groupByName,
+		groupByFileFlag: args.groupByFile} {
+		if value != "" {
+			return fmt.Errorf("--%s is supported only with --%s", flag, groupingFlag)
+		}
+	}
+	return nil
+}
+
+// groupingLabels returns the user-defined labels for grouping, or nil if there are none
+func groupingLabels(args *inArgs) (*vpcmodel.GroupingLabels, error) {
+	switch {
+	case args.groupByTag != "":
+		return vpcmodel.NewGroupingLabelsByTag(args.groupByTag), nil
+	case args.groupByName != "":
+		return vpcmodel.NewGroupingLabelsByRegex(args.groupByName)
+	case args.groupByFile != "":
+		return vpcmodel.NewGroupingLabelsFromFile(args.groupByFile)
+	}
+	return nil, nil
+}
+
 func newReportEndpointsCommand(args *inArgs) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "endpoints",
 		Short: "Report VPC connectivity between endpoints",
 		Long:  `reports VPC connectivity between endpoints as implied by the given cloud configuration`,
 		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateMapFlags(args)
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return analysisVPCConfigs(cmd, args, vpcmodel.AllEndpoints)
 		},
 	}
+	addMapFlags(cmd, args)
+	return cmd
 }
 
 func newReportSubnetsCommand(args *inArgs) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "subnets",
 		Short: "Report VPC connectivity between subnets",
 		Long:  `reports VPC connectivity between subnets as implied by the given cloud configuration`,
 		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateMapFlags(args)
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return analysisVPCConfigs(cmd, args, vpcmodel.AllSubnets)
 		},
 	}
+	addMapFlags(cmd, args)
+	return cmd
 }
 
 func newReportSingleSubnetCommand(args *inArgs) *cobra.Command {
@@ -76,13 +126,15 @@ func newReportSingleSubnetCommand(args *inArgs) *cobra.Command {
 	return &cobra.Command{
 		Use:   SingleSubnetCmd,
 		Short: "Report VPC connectivity per subnet",
-		Long:  `reports VPC connectivity per subnet as implied by the given cloud configuration`,
-		Args:  cobra.NoArgs,
+		Long: `reports VPC connectivity per subnet as implied by the nacl applied to it, separately per subnet,
+with the nacl rules contributing to the connectivity with each remote cidr;
+with grouping, remote cidrs sharing the same connectivity are grouped together`,
+		Args: cobra.NoArgs,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
-			if args.grouping {
-				return fmt.Errorf("currently single-subnet analysis type does not support grouping")
+			if args.groupByTag != "" || args.groupByName != "" || args.groupByFile != "" {
+				return fmt.Errorf("single-subnet analysis type groups remote cidrs, thus does not support grouping by labels")
 			}
-			return validateFormatForMode(SingleSubnetCmd, []formatSetting{textFormat}, args)
+			return validateFormatForMode(SingleSubnetCmd, []formatSetting{textFormat, mdFormat, jsonFormat}, args)
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return analysisVPCConfigs(cmd, args, vpcmodel.SingleSubnet)
@@ -94,14 +146,146 @@ func newReportRoutingCommand(args *inArgs) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "routing",
 		Short: "Report VPC routing paths between given endpoints",
-		Long:  `reports VPC routing paths between given endpoints as implied by the given cloud configuration`,
-		Args:  cobra.NoArgs,
+		Long: `reports VPC routing paths between given endpoints as implied by the given cloud configuration;
+with drawio, svg or html output format, the paths are drawn on the map through their routers and next-hop appliances`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateFormatForMode(cmd.Use, []formatSetting{textFormat, drawioFormat, svgFormat, htmlFormat}, args); err != nil {
+				return err
+			}
+			if args.viewLevel.ToModelViewLevel() != vpcmodel.EndpointViewLevel {
+				return fmt.Errorf("--%s is not supported by routing analysis type, the paths are drawn between endpoints",
+					viewLevelFlag)
+			}
+			return validateMapFlags(args)
+		},
 		RunE: func(_ *cobra.Command, _ []string) error {
 			return routingAnalysis(args)
 		},
 	}
 	cmd.Flags().StringVar(&args.eSrc, srcFlag, "", "source "+srcDstUsage)
 	cmd.Flags().StringVar(&args.eDst, dstFlag, "", "destination "+srcDstUsage)
+	addMapFlags(cmd, args)
+
+	return cmd
+}
+
+func newReportExposureCommand(args *inArgs) *cobra.Command {
+	const exposureCmd = "exposure"
+	return &cobra.Command{
+		Use:   exposureCmd,
+		Short: "Report VPC endpoints exposed to external networks",
+		Long: `reports VPC endpoints reachable from or reaching the Public Internet and the Service Network,
+with the floating IP, public gateway or service gateway enabling each connection and the rules allowing it`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if args.grouping {
+				return fmt.Errorf("currently exposure analysis type does not support grouping")
+			}
+			return validateFormatForMode(exposureCmd, []formatSetting{textFormat, mdFormat, jsonFormat}, args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return analysisVPCConfigs(cmd, args, vpcmodel.Exposure)
+		},
+	}
+}
+
+func newReportFiltersCommand(args *inArgs) *cobra.Command {
+	const filtersCmd = "filters"
+	return &cobra.Command{
+		Use:   filtersCmd,
+		Short: "Report the connectivity allowed by each NACL and security group",
+		Long: `reports, for each network ACL and each security group, the resources it is attached to and the ingress
+and egress connectivity it alone allows on them, with the rules contributing to each allowed connection`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if args.grouping {
+				return fmt.Errorf("currently filters analysis type does not support grouping")
+			}
+			return validateFormatForMode(filtersCmd, []formatSetting{textFormat, mdFormat, jsonFormat}, args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return analysisVPCConfigs(cmd, args, vpcmodel.Filters)
+		},
+	}
+}
+
+func newReportRuleUsageCommand(args *inArgs) *cobra.Command {
+	const ruleUsageCmd = "rule-usage"
+	return &cobra.Command{
+		Use:   ruleUsageCmd,
+		Short: "Report the connections each NACL and security group rule contributes to",
+		Long: `reports, for each network ACL and security group rule, the pairs of endpoints and the connections between
+them that the rule contributes to; rules that do not contribute to any connection are marked as unused`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if args.grouping {
+				return fmt.Errorf("currently rule-usage analysis type does not support grouping")
+			}
+			return validateFormatForMode(ruleUsageCmd, []formatSetting{textFormat, mdFormat, jsonFormat}, args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return analysisVPCConfigs(cmd, args, vpcmodel.RuleUsage)
+		},
+	}
+}
+
+func newReportBlastRadiusCommand(args *inArgs) *cobra.Command {
+	const blastRadiusCmd = "blast-radius"
+	cmd := &cobra.Command{
+		Use:   blastRadiusCmd,
+		Short: "Report VPC endpoints transitively reachable from a given endpoint",
+		Long: `reports VPC endpoints an attacker could pivot to from a given (compromised) endpoint,
+by multi-hop reachability over the connectivity implied by the given cloud configuration,
+along with a shortest hop chain to each reachable endpoint`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if args.grouping {
+				return fmt.Errorf("currently blast-radius analysis type does not support grouping")
+			}
+			if err := validateFormatForMode(blastRadiusCmd, []formatSetting{textFormat, mdFormat, jsonFormat}, args); err != nil {
+				return err
+			}
+			return validateConnectionFlags(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			args.explanationArgs = vpcmodel.NewExplanationArgs(args.eSrc, "", args.eProtocol.String(),
+				args.eSrcMinPort, args.eSrcMaxPort, args.eDstMinPort, args.eDstMaxPort, false)
+			return analysisVPCConfigs(cmd, args, vpcmodel.BlastRadius)
+		},
+	}
+
+	cmd.Flags().StringVar(&args.eSrc, srcFlag, "", "source "+srcDstUsage)
+	addConnectionFlags(cmd, args)
+	_ = cmd.MarkFlagRequired(srcFlag)
+	cmd.Flags().SortFlags = false
+
+	return cmd
+}
+
+func newReportSegmentationCommand(args *inArgs) *cobra.Command {
+	const segmentationCmd = "segmentation"
+	cmd := &cobra.Command{
+		Use:   segmentationCmd,
+		Short: "Report VPC connectivity between the security zones of a given zoning model",
+		Long: `reports a zone x zone connectivity matrix for a zoning model assigning endpoints and subnets to security zones,
+with the endpoint pairs and connections behind each cell; cells with connections not allowed by the
+zone-to-zone policy of the zoning model are flagged as violations`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if args.grouping {
+				return fmt.Errorf("currently segmentation analysis type does not support grouping")
+			}
+			return validateFormatForMode(segmentationCmd, []formatSetting{textFormat, mdFormat, jsonFormat, htmlFormat}, args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return analysisVPCConfigs(cmd, args, vpcmodel.Segmentation)
+		},
+	}
+
+	cmd.Flags().StringVar(&args.zoningFile, zoningFileFlag, "",
+		"json file assigning endpoints and subnets to security zones, with the allowed zone-to-zone policy")
+	_ = cmd.MarkFlagRequired(zoningFileFlag)
 
 	return cmd
 }
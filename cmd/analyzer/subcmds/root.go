@@ -45,9 +45,13 @@ const (
 type inArgs struct {
 	inputConfigFileList   []string
 	inputSecondConfigFile string
+	patchFile             string
 	outputFile            string
 	outputFormat          formatSetting
 	grouping              bool
+	groupByTag            string
+	groupByName           string
+	groupByFile           string
 	lbAbstraction         bool
 	vpcList               []string
 	eSrc                  string
@@ -58,6 +62,7 @@ type inArgs struct {
 	eDstMinPort           int64
 	eDstMaxPort           int64
 	detailExplain         bool
+	remediation           bool
 	provider              common.Provider
 	regionList            []string
 	resourceGroup         string
@@ -68,6 +73,10 @@ type inArgs struct {
 	enableLinters         []string
 	disableLinters        []string
 	printAllLinters       bool
+	lintConfigFile        string
+	viewLevel             viewLevelSetting
+	layoutSeedFile        string
+	zoningFile            string
 }
 
 func NewRootCommand() *cobra.Command {
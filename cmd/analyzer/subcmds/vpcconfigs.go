@@ -35,6 +35,13 @@ func parseProviderFromFile(fileName string) (common.Provider, error) {
 	if err != nil {
 		return "", err
 	}
+	if commonvpc.IsTerraformJSON(inputConfigContent) {
+		trs, err := commonvpc.NewTerraformResources(inputConfigContent)
+		if err != nil {
+			return "", err
+		}
+		return trs.Provider(), nil
+	}
 	asMap, err := jsonToMap(inputConfigContent)
 	if err != nil {
 		return "", err
@@ -128,18 +135,42 @@ func buildConfigs(inArgs *inArgs) (vpcConfigs *vpcmodel.MultipleVPCConfigs, err
 		}
 	}
 
-	if inArgs.inputSecondConfigFile != "" {
-		vpcConfigsToCompare, err := vpcConfigsFromFiles([]string{inArgs.inputSecondConfigFile}, inArgs)
+	if inArgs.inputSecondConfigFile != "" || inArgs.patchFile != "" {
+		vpcConfigsToCompare, err := vpcConfigsToCompareFromArgs(inArgs)
 		if err != nil {
 			return nil, err
 		}
 		// we are in diff mode, checking we have only one config per file:
 		if len(vpcConfigs.Configs()) != 1 || len(vpcConfigsToCompare.Configs()) != 1 {
 			return nil, fmt.Errorf("diff command only supports a single configuration " +
-				"for both --config and --config-second")
+				"for both --config and --config-second (or --patch)")
 		}
 		vpcConfigs.SetConfigsToCompare(vpcConfigsToCompare.Configs())
 	}
 
 	return vpcConfigs, nil
 }
+
+// vpcConfigsToCompareFromArgs returns the configs of the 2nd config file, or the patched configs of the input
+// config files if a patch file is given
+func vpcConfigsToCompareFromArgs(inArgs *inArgs) (*vpcmodel.MultipleVPCConfigs, error) {
+	if inArgs.patchFile == "" {
+		return vpcConfigsFromFiles([]string{inArgs.inputSecondConfigFile}, inArgs)
+	}
+	if len(inArgs.inputConfigFileList) == 0 {
+		return nil, fmt.Errorf("--%s is only supported with input config files", patchFlag)
+	}
+	provider, err := parseProviderFromFile(inArgs.inputConfigFileList[0])
+	if err != nil {
+		return nil, err
+	}
+	if provider != common.IBM {
+		return nil, fmt.Errorf("--%s is not supported yet for provider %s", patchFlag, provider)
+	}
+	patch, err := vpcmodel.ReadConfigPatch(inArgs.patchFile)
+	if err != nil {
+		return nil, err
+	}
+	return ibmvpc.NewIBMresourcesContainer().PatchedVpcConfigsFromFiles(inArgs.inputConfigFileList, patch,
+		inArgs.resourceGroup, inArgs.vpcList, inArgs.regionList)
+}
@@ -59,6 +59,13 @@ var tests = []*testfunc.VpcAnalysisTest{
 			Format:      vpcmodel.HTML,
 		},
 	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "tf_aws",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.AllEndpoints},
+			Format:      vpcmodel.Text,
+		},
+	},
 }
 
 // uncomment the function below to run for updating the expected output
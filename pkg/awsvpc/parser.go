@@ -50,12 +50,15 @@ func CopyAWSresourcesContainer(rc common.ResourcesContainerInf) (*AWSresourcesCo
 }
 
 // parseResourcesFromFile returns aws.ResourcesContainer object, containing the configured resources structs
-// from the input JSON file
+// from the input JSON file, either a collector output or the output of `terraform show -json` of a state or a plan
 func (rc *AWSresourcesContainer) ParseResourcesFromFile(fileName string) error {
 	inputConfigContent, err := os.ReadFile(fileName)
 	if err != nil {
 		return err
 	}
+	if commonvpc.IsTerraformJSON(inputConfigContent) {
+		return rc.parseTerraformResources(inputConfigContent)
+	}
 	err = json.Unmarshal(inputConfigContent, &rc)
 	if err != nil {
 		return err
@@ -183,6 +186,17 @@ func getResourceName(tags []types.Tag, alternateName *string) *string {
 	return alternateName
 }
 
+// resourceTags returns the tags of a resource as "key:value" strings
+func resourceTags(tags []types.Tag) []string {
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			res = append(res, *tag.Key+":"+*tag.Value)
+		}
+	}
+	return res
+}
+
 func (rc *AWSresourcesContainer) getVPCconfig(
 	res *vpcmodel.MultipleVPCConfigs,
 	skipByVPC map[string]bool,
@@ -230,6 +244,7 @@ func (rc *AWSresourcesContainer) getInstancesConfig(
 		if err != nil {
 			return err
 		}
+		vsiNode.ResourceTags = resourceTags(instance.Tags)
 		vpcConfig := res.Config(vpcUID)
 		vpcConfig.NodeSets = append(vpcConfig.NodeSets, vsiNode)
 		vpcConfig.UIDToResource[vsiNode.ResourceUID] = vsiNode
@@ -240,6 +255,8 @@ func (rc *AWSresourcesContainer) getInstancesConfig(
 			if err != nil {
 				return err
 			}
+			// the network interfaces are labeled by the tags of their instance
+			intfNode.ResourceTags = vsiNode.ResourceTags
 			netIntfToSGs[*netintf.NetworkInterfaceId] = netintf.Groups
 			vpcConfig.Nodes = append(vpcConfig.Nodes, intfNode)
 			vpcConfig.UIDToResource[intfNode.ResourceUID] = intfNode
@@ -275,6 +292,7 @@ func (rc *AWSresourcesContainer) getSubnetsConfig(
 			return nil, err
 		}
 		subnet.SetIsPrivate(!*subnetObj.MapPublicIpOnLaunch)
+		subnet.ResourceTags = resourceTags(subnetObj.Tags)
 	}
 	return vpcInternalAddressRange, nil
 }
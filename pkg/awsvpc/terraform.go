@@ -0,0 +1,510 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package awsvpc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/np-guard/cloud-resource-collector/pkg/aws"
+	"github.com/np-guard/cloud-resource-collector/pkg/common"
+	"github.com/np-guard/models/pkg/netset"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/logging"
+)
+
+// functionality for reading the resources from the output of `terraform show -json` of a state or a plan,
+// by mapping the aws_* resources onto the resources datamodel
+
+const (
+	tfVPC                       = "aws_vpc"
+	tfSubnet                    = "aws_subnet"
+	tfInstance                  = "aws_instance"
+	tfSecurityGroup             = "aws_security_group"
+	tfDefaultSecurityGroup      = "aws_default_security_group"
+	tfSecurityGroupRule         = "aws_security_group_rule"
+	tfSecurityGroupIngressRule  = "aws_vpc_security_group_ingress_rule"
+	tfSecurityGroupEgressRule   = "aws_vpc_security_group_egress_rule"
+	tfNetworkACL                = "aws_network_acl"
+	tfDefaultNetworkACL         = "aws_default_network_acl"
+	tfNetworkACLRule            = "aws_network_acl_rule"
+	tfNetworkACLAssociation     = "aws_network_acl_association"
+	tfInternetGateway           = "aws_internet_gateway"
+	tfInternetGatewayAttachment = "aws_internet_gateway_attachment"
+
+	tfVPCID               = "vpc_id"
+	tfSubnetID            = "subnet_id"
+	tfSubnetIDs           = "subnet_ids"
+	tfSecurityGroupID     = "security_group_id"
+	tfNetworkACLID        = "network_acl_id"
+	tfDefaultNACLID       = "default_network_acl_id"
+	tfDefaultSGID         = "default_security_group_id"
+	tfIngress             = "ingress"
+	tfEgress              = "egress"
+	tfProtocol            = "protocol"
+	tfFromPort            = "from_port"
+	tfToPort              = "to_port"
+	tfCidrBlock           = "cidr_block"
+	arnRegionIndex        = 3
+	lastRuleNumber        = 32767 // the number of the implicit last rule of a nacl, denying all traffic
+	defaultRuleNumber     = 100   // the number of the rule of a default nacl, allowing all traffic
+	defaultICMPTypeOrCode = -1
+	allProtocolsNumber    = "-1"
+)
+
+// resource types which are relevant to the connectivity, but are not supported yet as terraform input
+var tfUnsupportedTypes = []string{"aws_route_table", "aws_route", "aws_nat_gateway", "aws_network_interface",
+	"aws_network_interface_attachment", "aws_network_interface_sg_attachment", "aws_lb", "aws_vpc_peering_connection",
+	"aws_ec2_transit_gateway_vpc_attachment"}
+
+// terraformParser maps terraform resources onto the resources of an AWSresourcesContainer
+type terraformParser struct {
+	rc           *AWSresourcesContainer
+	trs          *commonvpc.TerraformResources
+	vpcResources map[string]*commonvpc.TerraformResource // map from vpc id to its terraform resource
+	vpcs         map[string]*aws.VPC                     // the following maps are from the resource id to the resource
+	subnets      map[string]*types.Subnet
+	sgs          map[string]*types.SecurityGroup
+	nacls        map[string]*types.NetworkAcl
+	defaultSGs   map[string]string // map from the id of the default sg of a vpc to the vpc id
+	defaultNACLs map[string]string // map from the id of the default nacl of a vpc to the vpc id
+	subnetNACL   map[string]string // map from subnet id to the id of its nacl
+	// sgs and nacls with rules defined by separate rule resources, whose inline rules are thus ignored
+	withRuleResources map[string]bool
+	takenAddresses    map[string][]string // map from subnet id to the addresses of its instances
+	pendingAddresses  []*types.InstanceNetworkInterface
+	pendingInstances  []string // the addresses of the terraform resources of the instances of pendingAddresses
+}
+
+// parseTerraformResources adds to rc the resources read from the output of `terraform show -json`
+func (rc *AWSresourcesContainer) parseTerraformResources(content []byte) error {
+	trs, err := commonvpc.NewTerraformResources(content)
+	if err != nil {
+		return err
+	}
+	if provider := trs.Provider(); provider != common.AWS {
+		return fmt.Errorf("terraform resources of provider %s are not supported for provider %s", provider, common.AWS)
+	}
+	// as with a collector output, the resources read replace any resources previously read into rc
+	rc.ResourcesContainer = aws.ResourcesContainer{}
+	p := &terraformParser{rc: rc, trs: trs, vpcResources: map[string]*commonvpc.TerraformResource{},
+		vpcs: map[string]*aws.VPC{}, subnets: map[string]*types.Subnet{}, sgs: map[string]*types.SecurityGroup{},
+		nacls: map[string]*types.NetworkAcl{}, defaultSGs: map[string]string{}, defaultNACLs: map[string]string{},
+		subnetNACL: map[string]string{}, withRuleResources: map[string]bool{}, takenAddresses: map[string][]string{}}
+	return p.parse()
+}
+
+func (p *terraformParser) parse() error {
+	p.warnUnsupported()
+	for _, parseFunc := range []func() error{p.parseVPCs, p.parseSubnets, p.parseSecurityGroups,
+		p.parseSecurityGroupRules, p.parseNetworkACLs, p.parseNetworkACLRules, p.parseNetworkACLAssociations,
+		p.parseInstances, p.allocatePendingAddresses, p.parseInternetGateways} {
+		if err := parseFunc(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *terraformParser) warnUnsupported() {
+	for _, r := range p.trs.Resources {
+		if slices.Contains(tfUnsupportedTypes, r.Type) {
+			logging.Warnf("ignoring %s - resource type %s is not supported yet for terraform input\n", r.Address, r.Type)
+		}
+	}
+}
+
+// tags returns the tags of a terraform resource, sorted by their keys
+func tags(r *commonvpc.TerraformResource) []types.Tag {
+	tagsMap, _ := r.Get("tags").(map[string]any)
+	res := []types.Tag{}
+	for key, value := range tagsMap {
+		if valueStr, ok := value.(string); ok {
+			res = append(res, types.Tag{Key: &key, Value: &valueStr})
+		}
+	}
+	slices.SortFunc(res, func(a, b types.Tag) int { return strings.Compare(*a.Key, *b.Key) })
+	return res
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func int32Ptr(i int64) *int32 {
+	res := int32(i)
+	return &res
+}
+
+// intOr returns the value of a number attribute as int32, or defaultValue if it is missing or unknown
+func intOr(r *commonvpc.TerraformResource, path string, defaultValue int64) *int32 {
+	if value := r.Int(path); value != nil {
+		return int32Ptr(*value)
+	}
+	return int32Ptr(defaultValue)
+}
+
+// regionFromARN returns the region of an arn of the form arn:aws:ec2:<region>:...
+func regionFromARN(arn string) string {
+	if parts := strings.Split(arn, ":"); len(parts) > arnRegionIndex {
+		return parts[arnRegionIndex]
+	}
+	return ""
+}
+
+// regionFromAvailabilityZone returns the region of an availability zone of the form <region><zone letter>
+func regionFromAvailabilityZone(zone string) string {
+	return strings.TrimRight(zone, "abcdefghijklmnopqrstuvwxyz")
+}
+
+func (p *terraformParser) vpcOf(r *commonvpc.TerraformResource) (*aws.VPC, error) {
+	vpcID := r.RefID(tfVPCID)
+	if vpc, ok := p.vpcs[vpcID]; ok {
+		return vpc, nil
+	}
+	return nil, fmt.Errorf("%s: could not find vpc %s", r.Address, vpcID)
+}
+
+// vpcDefault returns the id of the default sg or nacl of a vpc, given by attr
+func (p *terraformParser) vpcDefault(vpcID, attr string) string {
+	r := p.vpcResources[vpcID]
+	return r.StrOr(attr, r.Address+"."+attr)
+}
+
+func (p *terraformParser) parseVPCs() error {
+	for _, r := range p.trs.OfType(tfVPC) {
+		id := r.ID()
+		vpc := &aws.VPC{Vpc: types.Vpc{VpcId: &id, CidrBlock: strPtr(r.Str(tfCidrBlock)), Tags: tags(r)},
+			Region: regionFromARN(r.Str("arn"))}
+		p.rc.VpcsList = append(p.rc.VpcsList, vpc)
+		p.vpcs[id], p.vpcResources[id] = vpc, r
+		// the default nacl and sg of a vpc are not necessarily managed by terraform
+		p.defaultNACLs[p.vpcDefault(id, tfDefaultNACLID)] = id
+		p.defaultSGs[p.vpcDefault(id, tfDefaultSGID)] = id
+	}
+	return nil
+}
+
+func (p *terraformParser) parseSubnets() error {
+	for _, r := range p.trs.OfType(tfSubnet) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		id, cidr, zone := r.ID(), r.Str(tfCidrBlock), r.Str("availability_zone")
+		if cidr == "" {
+			return fmt.Errorf("%s: unknown cidr_block, subnets should have an explicit cidr", r.Address)
+		}
+		if vpc.Region == "" {
+			vpc.Region = regionFromAvailabilityZone(zone)
+		}
+		mapPublicIP := r.Bool("map_public_ip_on_launch")
+		subnet := &types.Subnet{SubnetId: &id, VpcId: vpc.VpcId, CidrBlock: &cidr, AvailabilityZone: &zone,
+			MapPublicIpOnLaunch: &mapPublicIP, Tags: tags(r)}
+		p.rc.SubnetsList = append(p.rc.SubnetsList, subnet)
+		p.subnets[id] = subnet
+	}
+	return nil
+}
+
+// ipPermission returns the sg rule with the given protocol and ports, and remotes: cidrs and sg ids
+func ipPermission(protocol string, fromPort, toPort *int32, cidrs, sgIDs []string) types.IpPermission {
+	res := types.IpPermission{IpProtocol: strPtr(strings.ToLower(protocol)), FromPort: fromPort, ToPort: toPort,
+		IpRanges: []types.IpRange{}, UserIdGroupPairs: []types.UserIdGroupPair{}}
+	for _, cidr := range cidrs {
+		res.IpRanges = append(res.IpRanges, types.IpRange{CidrIp: strPtr(cidr)})
+	}
+	for _, sgID := range sgIDs {
+		res.UserIdGroupPairs = append(res.UserIdGroupPairs, types.UserIdGroupPair{GroupId: strPtr(sgID)})
+	}
+	return res
+}
+
+// sgRuleRemotes returns the remote cidrs and sg ids of the sg rule of r, whose attributes are prefixed by prefix
+func sgRuleRemotes(r *commonvpc.TerraformResource, prefix, sgIDsAttr, sgID string) (cidrs, sgIDs []string) {
+	cidrs = r.Strs(prefix + "cidr_blocks")
+	if cidr := r.Str(prefix + "cidr_ipv4"); cidr != "" {
+		cidrs = append(cidrs, cidr)
+	}
+	sgIDs = r.RefIDs(prefix + sgIDsAttr)
+	if r.Bool(prefix + "self") {
+		sgIDs = append(sgIDs, sgID)
+	}
+	return cidrs, sgIDs
+}
+
+func (p *terraformParser) parseSecurityGroups() error {
+	for _, ruleType := range []string{tfSecurityGroupRule, tfSecurityGroupIngressRule, tfSecurityGroupEgressRule} {
+		for _, r := range p.trs.OfType(ruleType) {
+			p.withRuleResources[r.RefID(tfSecurityGroupID)] = true
+		}
+	}
+	for _, sgType := range []string{tfSecurityGroup, tfDefaultSecurityGroup} {
+		for _, r := range p.trs.OfType(sgType) {
+			vpc, err := p.vpcOf(r)
+			if err != nil {
+				return err
+			}
+			id := r.ID()
+			if sgType == tfDefaultSecurityGroup {
+				id = r.StrOr("id", p.vpcDefault(*vpc.VpcId, tfDefaultSGID))
+			}
+			sg := &types.SecurityGroup{GroupId: &id, GroupName: strPtr(r.StrOr("name", r.Name)), VpcId: vpc.VpcId,
+				Tags: tags(r), IpPermissions: []types.IpPermission{}, IpPermissionsEgress: []types.IpPermission{}}
+			if !p.withRuleResources[id] {
+				sg.IpPermissions = inlineIPPermissions(r, tfIngress, id)
+				sg.IpPermissionsEgress = inlineIPPermissions(r, tfEgress, id)
+			}
+			p.rc.SecurityGroupsList = append(p.rc.SecurityGroupsList, sg)
+			p.sgs[id] = sg
+		}
+	}
+	return nil
+}
+
+func inlineIPPermissions(r *commonvpc.TerraformResource, attr, sgID string) []types.IpPermission {
+	res := []types.IpPermission{}
+	for i := 0; i < r.Len(attr); i++ {
+		prefix := fmt.Sprintf("%s.%d.", attr, i)
+		cidrs, sgIDs := sgRuleRemotes(r, prefix, "security_groups", sgID)
+		res = append(res, ipPermission(r.Str(prefix+tfProtocol), intOr(r, prefix+tfFromPort, defaultICMPTypeOrCode),
+			intOr(r, prefix+tfToPort, defaultICMPTypeOrCode), cidrs, sgIDs))
+	}
+	return res
+}
+
+// securityGroup returns the sg with the given id; the default sg of a vpc is added on its first use if it is not
+// managed by terraform, with the rules of a default sg: inbound from its members, and outbound to anywhere
+func (p *terraformParser) securityGroup(id string) *types.SecurityGroup {
+	if sg, ok := p.sgs[id]; ok {
+		return sg
+	}
+	vpcID, ok := p.defaultSGs[id]
+	if !ok {
+		return nil
+	}
+	logging.Warnf("security group %s is not managed by terraform, assuming it has the rules of a default security group\n", id)
+	allPorts := int32Ptr(defaultICMPTypeOrCode)
+	sg := &types.SecurityGroup{GroupId: &id, GroupName: strPtr("default"), VpcId: &vpcID,
+		IpPermissions:       []types.IpPermission{ipPermission(allProtocolsNumber, allPorts, allPorts, nil, []string{id})},
+		IpPermissionsEgress: []types.IpPermission{ipPermission(allProtocolsNumber, allPorts, allPorts, []string{netset.CidrAll}, nil)}}
+	p.rc.SecurityGroupsList = append(p.rc.SecurityGroupsList, sg)
+	p.sgs[id] = sg
+	return sg
+}
+
+func (p *terraformParser) parseSecurityGroupRules() error {
+	for _, ruleType := range []string{tfSecurityGroupRule, tfSecurityGroupIngressRule, tfSecurityGroupEgressRule} {
+		for _, r := range p.trs.OfType(ruleType) {
+			sgID := r.RefID(tfSecurityGroupID)
+			sg := p.securityGroup(sgID)
+			if sg == nil {
+				return fmt.Errorf("%s: could not find security group %s", r.Address, sgID)
+			}
+			isIngress, protocol, sgIDsAttr := ruleType == tfSecurityGroupIngressRule, r.Str("ip_protocol"), "referenced_security_group_id"
+			if ruleType == tfSecurityGroupRule {
+				isIngress, protocol, sgIDsAttr = r.Str("type") == tfIngress, r.Str(tfProtocol), "source_security_group_id"
+			}
+			cidrs, sgIDs := sgRuleRemotes(r, "", sgIDsAttr, sgID)
+			permission := ipPermission(protocol, intOr(r, tfFromPort, defaultICMPTypeOrCode),
+				intOr(r, tfToPort, defaultICMPTypeOrCode), cidrs, sgIDs)
+			if isIngress {
+				sg.IpPermissions = append(sg.IpPermissions, permission)
+			} else {
+				sg.IpPermissionsEgress = append(sg.IpPermissionsEgress, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// naclEntry returns the nacl rule of r, whose attributes are prefixed by prefix
+func naclEntry(r *commonvpc.TerraformResource, prefix, ruleNumberAttr, actionAttr string, egress bool) types.NetworkAclEntry {
+	protocol := strings.ToLower(r.Str(prefix + tfProtocol))
+	entry := types.NetworkAclEntry{CidrBlock: strPtr(r.Str(prefix + tfCidrBlock)), Egress: &egress, Protocol: &protocol,
+		RuleAction: types.RuleAction(r.Str(prefix + actionAttr)), RuleNumber: intOr(r, prefix+ruleNumberAttr, 0)}
+	switch convertProtocol(protocol) {
+	case protocolTCP, protocolUDP:
+		entry.PortRange = &types.PortRange{From: intOr(r, prefix+tfFromPort, 0), To: intOr(r, prefix+tfToPort, 0)}
+	case protocolICMP:
+		entry.IcmpTypeCode = &types.IcmpTypeCode{Type: intOr(r, prefix+"icmp_type", defaultICMPTypeOrCode),
+			Code: intOr(r, prefix+"icmp_code", defaultICMPTypeOrCode)}
+	}
+	return entry
+}
+
+// lastNACLEntries returns the implicit last rules of a nacl, denying all traffic
+func lastNACLEntries() []types.NetworkAclEntry {
+	return []types.NetworkAclEntry{naclEntryAll(lastRuleNumber, types.RuleActionDeny, false),
+		naclEntryAll(lastRuleNumber, types.RuleActionDeny, true)}
+}
+
+func naclEntryAll(ruleNumber int64, action types.RuleAction, egress bool) types.NetworkAclEntry {
+	return types.NetworkAclEntry{CidrBlock: strPtr(netset.CidrAll), Egress: &egress, Protocol: strPtr(allProtocolsNumber),
+		RuleAction: action, RuleNumber: int32Ptr(ruleNumber)}
+}
+
+func (p *terraformParser) parseNetworkACLs() error {
+	for _, r := range p.trs.OfType(tfNetworkACLRule) {
+		p.withRuleResources[r.RefID(tfNetworkACLID)] = true
+	}
+	for _, naclType := range []string{tfNetworkACL, tfDefaultNetworkACL} {
+		for _, r := range p.trs.OfType(naclType) {
+			id := r.ID()
+			if naclType == tfDefaultNetworkACL {
+				id = r.StrOr("id", r.RefID(tfDefaultNACLID))
+			}
+			vpcID := r.RefID(tfVPCID)
+			if naclType == tfDefaultNetworkACL && vpcID == "" {
+				vpcID = p.defaultNACLs[id]
+			}
+			vpc, ok := p.vpcs[vpcID]
+			if !ok {
+				return fmt.Errorf("%s: could not find vpc %s", r.Address, vpcID)
+			}
+			nacl := &types.NetworkAcl{NetworkAclId: &id, VpcId: vpc.VpcId, Tags: tags(r), Entries: lastNACLEntries()}
+			if !p.withRuleResources[id] {
+				for _, egress := range []bool{false, true} {
+					attr := map[bool]string{false: tfIngress, true: tfEgress}[egress]
+					for i := 0; i < r.Len(attr); i++ {
+						nacl.Entries = append(nacl.Entries, naclEntry(r, fmt.Sprintf("%s.%d.", attr, i), "rule_no", "action", egress))
+					}
+				}
+			}
+			for _, subnetID := range r.RefIDs(tfSubnetIDs) {
+				p.subnetNACL[subnetID] = id
+			}
+			p.rc.NetworkACLsList = append(p.rc.NetworkACLsList, nacl)
+			p.nacls[id] = nacl
+		}
+	}
+	return nil
+}
+
+func (p *terraformParser) parseNetworkACLRules() error {
+	for _, r := range p.trs.OfType(tfNetworkACLRule) {
+		nacl, ok := p.nacls[r.RefID(tfNetworkACLID)]
+		if !ok {
+			return fmt.Errorf("%s: could not find network acl %s", r.Address, r.RefID(tfNetworkACLID))
+		}
+		nacl.Entries = append(nacl.Entries, naclEntry(r, "", "rule_number", "rule_action", r.Bool(tfEgress)))
+	}
+	return nil
+}
+
+// networkACL returns the nacl with the given id; the default nacl of a vpc is added on its first use if it is not
+// managed by terraform, with the rules of a default nacl: allowing all traffic
+func (p *terraformParser) networkACL(id, vpcID string) *types.NetworkAcl {
+	if nacl, ok := p.nacls[id]; ok {
+		return nacl
+	}
+	logging.Warnf("network acl %s is not managed by terraform, assuming it allows all traffic\n", id)
+	nacl := &types.NetworkAcl{NetworkAclId: &id, VpcId: &vpcID, IsDefault: boolPtr(true),
+		Entries: append(lastNACLEntries(), naclEntryAll(defaultRuleNumber, types.RuleActionAllow, false),
+			naclEntryAll(defaultRuleNumber, types.RuleActionAllow, true))}
+	p.rc.NetworkACLsList = append(p.rc.NetworkACLsList, nacl)
+	p.nacls[id] = nacl
+	return nacl
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// parseNetworkACLAssociations associates each subnet with its nacl, or with the default nacl of its vpc
+func (p *terraformParser) parseNetworkACLAssociations() error {
+	for _, r := range p.trs.OfType(tfNetworkACLAssociation) {
+		p.subnetNACL[r.RefID(tfSubnetID)] = r.RefID(tfNetworkACLID)
+	}
+	for _, subnet := range p.rc.SubnetsList {
+		naclID, ok := p.subnetNACL[*subnet.SubnetId]
+		if !ok {
+			naclID = p.vpcDefault(*subnet.VpcId, tfDefaultNACLID)
+		}
+		nacl := p.networkACL(naclID, *subnet.VpcId)
+		nacl.Associations = append(nacl.Associations, types.NetworkAclAssociation{NetworkAclId: &naclID,
+			SubnetId: subnet.SubnetId})
+	}
+	return nil
+}
+
+func (p *terraformParser) parseInstances() error {
+	for _, r := range p.trs.OfType(tfInstance) {
+		subnet, ok := p.subnets[r.RefID(tfSubnetID)]
+		if !ok {
+			return fmt.Errorf("%s: could not find subnet %s", r.Address, r.RefID(tfSubnetID))
+		}
+		id := r.ID()
+		state := types.InstanceStateName(r.StrOr("instance_state", string(types.InstanceStateNameRunning)))
+		networkInterface := types.InstanceNetworkInterface{SubnetId: subnet.SubnetId,
+			NetworkInterfaceId: strPtr(r.StrOr("primary_network_interface_id", r.Address+".primary_network_interface_id")),
+			PrivateIpAddress:   strPtr(r.Str("private_ip")), Groups: []types.GroupIdentifier{}}
+		sgIDs := r.RefIDs("vpc_security_group_ids")
+		if len(sgIDs) == 0 {
+			sgIDs = []string{p.vpcDefault(*subnet.VpcId, tfDefaultSGID)}
+		}
+		for _, sgID := range sgIDs {
+			if p.securityGroup(sgID) == nil {
+				logging.Warnf("%s: ignoring unknown security group %s\n", r.Address, sgID)
+				continue
+			}
+			networkInterface.Groups = append(networkInterface.Groups, types.GroupIdentifier{GroupId: strPtr(sgID)})
+		}
+		instance := &types.Instance{InstanceId: &id, VpcId: subnet.VpcId, Tags: tags(r),
+			Placement:         &types.Placement{AvailabilityZone: strPtr(r.StrOr("availability_zone", *subnet.AvailabilityZone))},
+			State:             &types.InstanceState{Name: state},
+			NetworkInterfaces: []types.InstanceNetworkInterface{networkInterface}}
+		if *networkInterface.PrivateIpAddress == "" {
+			p.pendingAddresses = append(p.pendingAddresses, &instance.NetworkInterfaces[0])
+			p.pendingInstances = append(p.pendingInstances, r.Address)
+		} else {
+			p.takenAddresses[*subnet.SubnetId] = append(p.takenAddresses[*subnet.SubnetId], *networkInterface.PrivateIpAddress)
+		}
+		p.rc.InstancesList = append(p.rc.InstancesList, instance)
+	}
+	return nil
+}
+
+// allocatePendingAddresses sets the addresses of instances which are known only after apply,
+// to the first free addresses of their subnets
+func (p *terraformParser) allocatePendingAddresses() error {
+	for i, networkInterface := range p.pendingAddresses {
+		subnet := p.subnets[*networkInterface.SubnetId]
+		address, err := commonvpc.AllocateAddress(*subnet.CidrBlock, p.takenAddresses[*subnet.SubnetId])
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.pendingInstances[i], err)
+		}
+		logging.Warnf("%s: the address of the instance is known only after apply, assuming it is %s\n", p.pendingInstances[i], address)
+		networkInterface.PrivateIpAddress = &address
+		p.takenAddresses[*subnet.SubnetId] = append(p.takenAddresses[*subnet.SubnetId], address)
+	}
+	return nil
+}
+
+func (p *terraformParser) parseInternetGateways() error {
+	igws := map[string]*types.InternetGateway{}
+	for _, r := range p.trs.OfType(tfInternetGateway) {
+		id := r.ID()
+		igw := &types.InternetGateway{InternetGatewayId: &id, Tags: tags(r), Attachments: []types.InternetGatewayAttachment{}}
+		if vpcID := r.RefID(tfVPCID); vpcID != "" {
+			igw.Attachments = append(igw.Attachments, types.InternetGatewayAttachment{VpcId: strPtr(vpcID)})
+		}
+		p.rc.InternetGWList = append(p.rc.InternetGWList, igw)
+		igws[id] = igw
+	}
+	for _, r := range p.trs.OfType(tfInternetGatewayAttachment) {
+		igw, ok := igws[r.RefID("internet_gateway_id")]
+		if !ok {
+			return fmt.Errorf("%s: could not find internet gateway %s", r.Address, r.RefID("internet_gateway_id"))
+		}
+		igw.Attachments = append(igw.Attachments, types.InternetGatewayAttachment{VpcId: strPtr(r.RefID(tfVPCID))})
+	}
+	return nil
+}
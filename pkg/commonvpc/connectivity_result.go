@@ -9,11 +9,13 @@ package commonvpc
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/np-guard/models/pkg/netset"
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
 )
 
 // ConnectivityResultMap is a map from IPBlock to ConnectivityResult, used to map disjointLocals IPBlocks to ConnectivityResult
@@ -122,3 +124,18 @@ func (cr *ConnectivityResult) String() string {
 	sort.Strings(res)
 	return strings.Join(res, "\n")
 }
+
+// remotesConnectivity translates the ConnectivityResult to the allowed connection per remote, along with
+// the allow and deny rules contributing to it; returns nil if cr is nil
+func (cr *ConnectivityResult) remotesConnectivity() []*vpcmodel.RemoteConnectivity {
+	if cr == nil {
+		return nil
+	}
+	res := make([]*vpcmodel.RemoteConnectivity, 0, len(cr.AllowedConns))
+	for remote, conn := range cr.AllowedConns {
+		res = append(res, &vpcmodel.RemoteConnectivity{Remote: remote, Conn: conn,
+			AllowRules: slices.Sorted(slices.Values(cr.AllowRules[remote])),
+			DenyRules:  slices.Sorted(slices.Values(cr.DenyRules[remote]))})
+	}
+	return res
+}
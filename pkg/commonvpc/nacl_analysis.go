@@ -320,6 +320,35 @@ func (na *NACLAnalyzer) GeneralConnectivityPerSubnet(subnet *Subnet) (
 	return strResult, connectivityObjResult
 }
 
+// ConnectivityPerSubnet returns the structured connectivity of the analyzed subnet input, per disjoint local range
+// within the subnet; naclName and naclIndex identify this nacl within its layer
+func (na *NACLAnalyzer) ConnectivityPerSubnet(subnet *Subnet, naclName string, naclIndex int) []*vpcmodel.SingleSubnetConnectivity {
+	na.AddAnalysisPerSubnet(subnet)
+	ingressRes := na.AnalyzedSubnets[subnet.Cidr].ingressRes
+	egressRes := na.AnalyzedSubnets[subnet.Cidr].egressRes
+
+	// ingress and egress are expected to share the same local ranges; takes the union for safety
+	localRanges := map[string]bool{}
+	for disjointSubnetCidr := range ingressRes {
+		localRanges[disjointSubnetCidr] = true
+	}
+	for disjointSubnetCidr := range egressRes {
+		localRanges[disjointSubnetCidr] = true
+	}
+	res := []*vpcmodel.SingleSubnetConnectivity{}
+	for disjointSubnetCidr := range localRanges {
+		subnetConnectivity := &vpcmodel.SingleSubnetConnectivity{SubnetName: subnet.Name(), SubnetCidr: subnet.Cidr,
+			NaclName: naclName, NaclIndex: naclIndex,
+			Ingress: ingressRes[disjointSubnetCidr].remotesConnectivity(),
+			Egress:  egressRes[disjointSubnetCidr].remotesConnectivity()}
+		if len(localRanges) > 1 {
+			subnetConnectivity.LocalRange = disjointSubnetCidr
+		}
+		res = append(res, subnetConnectivity)
+	}
+	return res
+}
+
 // initConnectivityRelatedCompute performs initial computation for AllowedConnectivity and rulesFilterInConnectivity
 func (na *NACLAnalyzer) initConnectivityRelatedCompute(subnet *Subnet, isIngress bool,
 ) (analyzedConns map[string]*ConnectivityResult) {
@@ -248,6 +248,16 @@ func (sga *SGAnalyzer) getRulesRelevantConn(rules []int, conn *netset.TransportS
 	return relevantRules, nil
 }
 
+// connectivityOfLocal returns the analyzed connectivity of the disjoint local range containing local, or nil if none
+func (sga *SGAnalyzer) connectivityOfLocal(local *netset.IPBlock, isIngress bool) *ConnectivityResult {
+	for definedLocal, analyzedConns := range sga.ingressOrEgressConnectivity(isIngress) {
+		if local.IsSubset(definedLocal) {
+			return analyzedConns
+		}
+	}
+	return nil
+}
+
 func (sga *SGAnalyzer) ingressOrEgressConnectivity(isIngress bool) ConnectivityResultMap {
 	if isIngress {
 		return sga.ingressConnectivityMap
@@ -0,0 +1,400 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commonvpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/np-guard/cloud-resource-collector/pkg/common"
+	"github.com/np-guard/models/pkg/netset"
+)
+
+// functionality for reading the output of `terraform show -json` of a state file or a plan file.
+// the managed resources of the state (or the planned state, for a plan) are read as generic TerraformResource
+// objects, which the provider-specific parsers map onto their resources datamodel.
+// In a plan, values that are known only after apply (ids, addresses, etc.) are missing; references to such values
+// are resolved from the expressions in the plan's configuration, and are replaced by synthesized ids
+
+const (
+	terraformFormatVersionKey    = "format_version"
+	terraformTerraformVersionKey = "terraform_version"
+	terraformManagedMode         = "managed"
+	terraformModulePrefix        = "module."
+	terraformAWSTypePrefix       = "aws_"
+	terraformIDAttr              = "id"
+	terraformPathSep             = "."
+	// number of addresses reserved by the cloud provider at the start of a subnet (the last address is reserved as well)
+	subnetReservedFirstAddresses = 4
+)
+
+var (
+	terraformIndexRegexp     = regexp.MustCompile(`\[[^\]]*\]`)
+	terraformListIndexRegexp = regexp.MustCompile(`\[(\d+)\]`)
+)
+
+// terraform show -json output, see https://developer.hashicorp.com/terraform/internals/json-format
+type terraformShowJSON struct {
+	FormatVersion string                  `json:"format_version"`
+	Values        *terraformValues        `json:"values"`         // of a state
+	PlannedValues *terraformValues        `json:"planned_values"` // of a plan
+	Configuration *terraformConfiguration `json:"configuration"`  // of a plan
+}
+
+type terraformValues struct {
+	RootModule terraformModule `json:"root_module"`
+}
+
+type terraformModule struct {
+	Address      string               `json:"address"`
+	Resources    []*terraformResource `json:"resources"`
+	ChildModules []*terraformModule   `json:"child_modules"`
+}
+
+type terraformResource struct {
+	Address string         `json:"address"`
+	Mode    string         `json:"mode"`
+	Type    string         `json:"type"`
+	Name    string         `json:"name"`
+	Index   any            `json:"index"`
+	Values  map[string]any `json:"values"`
+}
+
+type terraformConfiguration struct {
+	RootModule terraformConfigModule `json:"root_module"`
+}
+
+type terraformConfigModule struct {
+	Resources   []*terraformConfigResource     `json:"resources"`
+	ModuleCalls map[string]terraformModuleCall `json:"module_calls"`
+}
+
+type terraformModuleCall struct {
+	Module terraformConfigModule `json:"module"`
+}
+
+type terraformConfigResource struct {
+	Address     string         `json:"address"`
+	Expressions map[string]any `json:"expressions"`
+}
+
+// TerraformResource is a managed resource read from the output of `terraform show -json`
+type TerraformResource struct {
+	Address string
+	Type    string
+	Name    string
+	Values  map[string]any
+	module  string // the address of the module of the resource, empty for the root module
+	index   string // the count or for_each index of the resource, empty if it has none
+	// map from an attribute path to the references of its expression in the configuration
+	references map[string][]string
+	resources  *TerraformResources
+}
+
+// TerraformResources are the managed resources read from the output of `terraform show -json`
+type TerraformResources struct {
+	Resources []*TerraformResource
+	IsPlan    bool
+	byAddress map[string]*TerraformResource
+}
+
+// IsTerraformJSON checks if content is the output of `terraform show -json`
+func IsTerraformJSON(content []byte) bool {
+	var asMap map[string]json.RawMessage
+	if err := json.Unmarshal(content, &asMap); err != nil {
+		return false
+	}
+	_, hasFormatVersion := asMap[terraformFormatVersionKey]
+	_, hasTerraformVersion := asMap[terraformTerraformVersionKey]
+	return hasFormatVersion && hasTerraformVersion
+}
+
+// NewTerraformResources reads the managed resources from the output of `terraform show -json`:
+// of the state for a state file, and of the planned state for a plan file
+func NewTerraformResources(content []byte) (*TerraformResources, error) {
+	showJSON := &terraformShowJSON{}
+	if err := json.Unmarshal(content, showJSON); err != nil {
+		return nil, err
+	}
+	res := &TerraformResources{byAddress: map[string]*TerraformResource{}}
+	values := showJSON.Values
+	if showJSON.PlannedValues != nil {
+		values, res.IsPlan = showJSON.PlannedValues, true
+	}
+	if values == nil {
+		return nil, fmt.Errorf("terraform json (format version %s) has no resources values", showJSON.FormatVersion)
+	}
+	res.addModuleResources(&values.RootModule)
+
+	expressions := map[string]map[string]any{}
+	if showJSON.Configuration != nil {
+		addConfigExpressions(&showJSON.Configuration.RootModule, "", expressions)
+	}
+	for _, r := range res.Resources {
+		r.references = map[string][]string{}
+		collectReferences(expressions[terraformIndexRegexp.ReplaceAllString(r.Address, "")], "", r.references)
+	}
+	return res, nil
+}
+
+func (trs *TerraformResources) addModuleResources(module *terraformModule) {
+	for _, r := range module.Resources {
+		if r.Mode != terraformManagedMode {
+			continue
+		}
+		resource := &TerraformResource{Address: r.Address, Type: r.Type, Name: r.Name, Values: r.Values,
+			module: module.Address, resources: trs}
+		switch index := r.Index.(type) {
+		case float64:
+			resource.index = strconv.Itoa(int(index))
+		case string:
+			resource.index = strconv.Quote(index)
+		}
+		if resource.Values == nil {
+			resource.Values = map[string]any{}
+		}
+		trs.Resources = append(trs.Resources, resource)
+		trs.byAddress[resource.Address] = resource
+	}
+	for _, child := range module.ChildModules {
+		trs.addModuleResources(child)
+	}
+}
+
+// addConfigExpressions maps the addresses of the configured resources (without count/for_each indexes) to their
+// expressions
+func addConfigExpressions(module *terraformConfigModule, modulePrefix string, res map[string]map[string]any) {
+	for _, r := range module.Resources {
+		res[modulePrefix+r.Address] = r.Expressions
+	}
+	for name, call := range module.ModuleCalls {
+		addConfigExpressions(&call.Module, modulePrefix+terraformModulePrefix+name+terraformPathSep, res)
+	}
+}
+
+// collectReferences maps the paths of the attributes in expressions (including attributes of nested blocks, such as
+// "primary_network_interface.0.subnet") to the references of their expressions
+func collectReferences(expressions map[string]any, pathPrefix string, res map[string][]string) {
+	for attr, expression := range expressions {
+		switch expr := expression.(type) {
+		case map[string]any:
+			if refs, ok := expr["references"].([]any); ok {
+				for _, ref := range refs {
+					if refStr, ok := ref.(string); ok {
+						res[pathPrefix+attr] = append(res[pathPrefix+attr], refStr)
+					}
+				}
+			}
+		case []any:
+			for i, block := range expr {
+				if blockExpressions, ok := block.(map[string]any); ok {
+					collectReferences(blockExpressions, fmt.Sprintf("%s%s.%d.", pathPrefix, attr, i), res)
+				}
+			}
+		}
+	}
+}
+
+// Provider returns the cloud provider of the resources
+func (trs *TerraformResources) Provider() common.Provider {
+	for _, r := range trs.Resources {
+		if strings.HasPrefix(r.Type, terraformAWSTypePrefix) {
+			return common.AWS
+		}
+	}
+	return common.IBM
+}
+
+// OfType returns the resources of the given type
+func (trs *TerraformResources) OfType(resourceType string) []*TerraformResource {
+	res := []*TerraformResource{}
+	for _, r := range trs.Resources {
+		if r.Type == resourceType {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// Get returns the value of an attribute, given by its path (e.g. "primary_network_interface.0.subnet"),
+// or nil if it is missing or unknown
+func (r *TerraformResource) Get(path string) any {
+	var current any = r.Values
+	for _, part := range strings.Split(path, terraformPathSep) {
+		switch value := current.(type) {
+		case map[string]any:
+			current = value[part]
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i >= len(value) {
+				return nil
+			}
+			current = value[i]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// Str returns the value of a string attribute, or "" if it is missing or unknown
+func (r *TerraformResource) Str(path string) string {
+	if value, ok := r.Get(path).(string); ok {
+		return value
+	}
+	return ""
+}
+
+// Int returns the value of a number attribute, or nil if it is missing or unknown
+func (r *TerraformResource) Int(path string) *int64 {
+	if value, ok := r.Get(path).(float64); ok {
+		res := int64(value)
+		return &res
+	}
+	return nil
+}
+
+// Bool returns the value of a bool attribute, or false if it is missing or unknown
+func (r *TerraformResource) Bool(path string) bool {
+	value, ok := r.Get(path).(bool)
+	return ok && value
+}
+
+// Strs returns the values of a list (or set) of strings attribute
+func (r *TerraformResource) Strs(path string) []string {
+	res := []string{}
+	values, _ := r.Get(path).([]any)
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
+// Len returns the length of a list attribute (such as a nested block)
+func (r *TerraformResource) Len(path string) int {
+	values, _ := r.Get(path).([]any)
+	return len(values)
+}
+
+// StrOr returns the value of a string attribute, or defaultValue if it is missing or unknown
+func (r *TerraformResource) StrOr(path, defaultValue string) string {
+	if value := r.Str(path); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// ID returns the id of the resource; if it is unknown (a resource to be created by a plan) its address is used
+func (r *TerraformResource) ID() string {
+	return r.NestedID("")
+}
+
+// NestedID returns the id of a nested block of the resource (e.g. "primary_network_interface.0"), or of the
+// resource itself for an empty path; if it is unknown, an id is synthesized from the resource address
+func (r *TerraformResource) NestedID(path string) string {
+	if path == "" {
+		return r.StrOr(terraformIDAttr, r.Address)
+	}
+	return r.StrOr(path+terraformPathSep+terraformIDAttr, r.Address+terraformPathSep+path)
+}
+
+// RefIDs returns the values of an attribute holding a list of ids of other resources; if the attribute is unknown,
+// the ids are resolved from the references of its expression in the configuration
+func (r *TerraformResource) RefIDs(path string) []string {
+	switch value := r.Get(path).(type) {
+	case string:
+		if value == "" {
+			return []string{}
+		}
+		return []string{value}
+	case []any:
+		return r.Strs(path)
+	}
+	res := []string{}
+	referenced := map[*TerraformResource]bool{}
+	// each reference is listed from the most specific to the least specific (e.g., "ibm_is_vpc.vpc1.id" and then
+	// "ibm_is_vpc.vpc1"), thus only the first reference to each resource is used
+	for _, ref := range r.references[path] {
+		target, attr := r.resolveReference(ref)
+		if target == nil || referenced[target] {
+			continue
+		}
+		referenced[target] = true
+		switch {
+		case attr == "" || attr == terraformIDAttr:
+			res = append(res, target.ID())
+		case strings.HasSuffix(attr, terraformPathSep+terraformIDAttr):
+			res = append(res, target.NestedID(strings.TrimSuffix(attr, terraformPathSep+terraformIDAttr)))
+		default:
+			res = append(res, target.StrOr(attr, target.Address+terraformPathSep+attr))
+		}
+	}
+	return res
+}
+
+// RefID is as RefIDs, for an attribute holding an id of another resource; it returns "" if there is no such id
+func (r *TerraformResource) RefID(path string) string {
+	if ids := r.RefIDs(path); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// resolveReference returns the resource referenced by ref (e.g., "ibm_is_instance.vsi.primary_network_interface[0].id"),
+// and the path of the referenced attribute within it (e.g., "primary_network_interface.0.id");
+// a reference without an index to a resource with count/for_each is resolved to the resource with the index of r
+func (r *TerraformResource) resolveReference(ref string) (target *TerraformResource, attr string) {
+	modulePrefix := ""
+	if r.module != "" {
+		modulePrefix = r.module + terraformPathSep
+	}
+	parts := strings.Split(ref, terraformPathSep)
+	for k := len(parts); k >= 2; k-- {
+		address := modulePrefix + strings.Join(parts[:k], terraformPathSep)
+		target = r.resources.byAddress[address]
+		if target == nil && r.index != "" {
+			target = r.resources.byAddress[address+"["+r.index+"]"]
+		}
+		if target != nil {
+			attr = terraformListIndexRegexp.ReplaceAllString(strings.Join(parts[k:], terraformPathSep), ".$1")
+			return target, attr
+		}
+	}
+	return nil, ""
+}
+
+// AllocateAddress returns the first address in cidr that is not reserved by the cloud provider, and not in taken.
+// It is used for addresses of network interfaces that are known only after apply
+func AllocateAddress(cidr string, taken []string) (string, error) {
+	subnetBlock, err := netset.IPBlockFromCidr(cidr)
+	if err != nil {
+		return "", err
+	}
+	reserved := subnetBlock.LastIPAddressObject()
+	ip := subnetBlock.FirstIPAddressObject()
+	for i := 0; i < subnetReservedFirstAddresses; i++ {
+		reserved = reserved.Union(ip)
+		if ip, err = ip.NextIP(); err != nil {
+			return "", err
+		}
+	}
+	free := subnetBlock.Subtract(reserved)
+	for _, address := range taken {
+		if takenIP, err := netset.IPBlockFromIPAddress(address); err == nil {
+			free = free.Subtract(takenIP)
+		}
+	}
+	if free.IsEmpty() {
+		return "", fmt.Errorf("no free address in %s", cidr)
+	}
+	return free.FirstIPAddress(), nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testfunc
+
+import (
+	"testing"
+
+	"github.com/np-guard/models/pkg/netp"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+type VpcBlastRadiusTest struct {
+	VpcTestCommon
+	ESrc        string
+	EProtocol   netp.ProtocolString
+	ESrcMinPort int64
+	ESrcMaxPort int64
+	EDstMinPort int64
+	EDstMaxPort int64
+}
+
+///////////////////////////////////////////////////////////////////////////////////////////
+// blast radius:
+//////////////////////////////////////////////////////////////////////////////////////////////
+
+const blastRadiusOut = "blast_radius_out"
+
+func (tt *VpcBlastRadiusTest) TestSingleBlastRadius(t *testing.T, mode testMode, rc commonvpc.ResourcesContainer,
+	testName string) {
+	tt.Name = testName
+	tt.setMode(mode)
+	explanationArgs := vpcmodel.NewExplanationArgs(tt.ESrc, "", string(tt.EProtocol),
+		tt.ESrcMinPort, tt.ESrcMaxPort, tt.EDstMinPort, tt.EDstMaxPort, false)
+	tt.UseCases = []vpcmodel.OutputUseCase{vpcmodel.BlastRadius}
+	t.Run(tt.Name, func(t *testing.T) {
+		t.Parallel()
+		tt.runSingleCommonTest(t, blastRadiusOut, rc, vpcmodel.NoGroupingNoConsistencyEdges, false, explanationArgs)
+	})
+}
@@ -46,6 +46,11 @@ const (
 	suffixOutFileDiffSubnets          = "subnetsDiff"
 	suffixOutFileDiffEndpoints        = "endpointsDiff"
 	suffixOutFileExplain              = "explain"
+	suffixOutFileExposure             = "exposure"
+	suffixOutFileBlastRadius          = "blastRadius"
+	suffixOutFileSegmentation         = "segmentation"
+	suffixOutFileFilters              = "filters"
+	suffixOutFileRuleUsage            = "ruleUsage"
 	suffixOutFileDetail               = "_detail"
 	consistencyEdgesExternal          = "_EdgeConsistent"
 	txtOutSuffix                      = ".txt"
@@ -120,6 +125,16 @@ func getTestFileName(testName string,
 		res = baseName + suffixOutFileDiffEndpoints
 	case vpcmodel.Explain:
 		res = baseName + suffixOutFileExplain
+	case vpcmodel.Exposure:
+		res = baseName + suffixOutFileExposure
+	case vpcmodel.BlastRadius:
+		res = baseName + suffixOutFileBlastRadius
+	case vpcmodel.Segmentation:
+		res = baseName + suffixOutFileSegmentation
+	case vpcmodel.Filters:
+		res = baseName + suffixOutFileFilters
+	case vpcmodel.RuleUsage:
+		res = baseName + suffixOutFileRuleUsage
 	}
 	if grouping {
 		res += suffixOutFileWithGrouping
@@ -25,6 +25,7 @@ type VpcExplainTest struct {
 	EDstMinPort   int64
 	EDstMaxPort   int64
 	DetailExplain bool
+	Remediate     bool
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////////
@@ -38,6 +39,7 @@ func (tt *VpcExplainTest) TestSingleExplain(t *testing.T, mode testMode, rc comm
 	tt.setMode(mode)
 	explanationArgs := vpcmodel.NewExplanationArgs(tt.ESrc, tt.EDst, string(tt.EProtocol),
 		tt.ESrcMinPort, tt.ESrcMaxPort, tt.EDstMinPort, tt.EDstMaxPort, tt.DetailExplain)
+	explanationArgs.Remediate = tt.Remediate
 	tt.UseCases = []vpcmodel.OutputUseCase{vpcmodel.Explain}
 	tt.Format = vpcmodel.Text
 	t.Run(tt.Name, func(t *testing.T) {
@@ -24,6 +24,8 @@ type VpcLintTest struct {
 	Enable        []string
 	Disable       []string
 	PrintAllLints bool
+	LintConfig    string // name of the lint config file, in the input dir
+	JSONOutput    bool   // whether the lint results are in json rather than in text
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////////
@@ -54,7 +56,17 @@ func (tt *VpcLintTest) TestSingleLint(t *testing.T, rc commonvpc.ResourcesContai
 func (tt *VpcLintTest) runLintTest(t *testing.T, cConfigs map[string]*vpcmodel.VPCConfig, outDir string) error {
 	// output use case is not significant here, but being used so that lint test can rely on existing mechanism
 	tt.initLintTestFileNames(outDir)
-	actualOutput, _ := linter.LinterExecute(cConfigs, tt.PrintAllLints, tt.Enable, tt.Disable)
+	var lintConfig *linter.LintConfig
+	if tt.LintConfig != "" {
+		var err error
+		lintConfig, err = linter.ReadLintConfig(filepath.Join(GetTestsDirInput(), tt.LintConfig))
+		require.Nil(t, err, "reading lint config")
+	}
+	outFormat := vpcmodel.Text
+	if tt.JSONOutput {
+		outFormat = vpcmodel.JSON
+	}
+	actualOutput, _ := linter.LinterExecute(cConfigs, tt.PrintAllLints, tt.Enable, tt.Disable, lintConfig, outFormat, "")
 	if err := compareOrRegenerateOutputPerTest(t, tt.Mode, actualOutput, lintOut, tt.Name, tt.ExpectedOutput,
 		vpcmodel.AllEndpoints); err != nil {
 		return err
@@ -0,0 +1,51 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testfunc
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+type VpcSegmentationTest struct {
+	VpcTestCommon
+	ZoningFile string // name of the zoning file, in the input dir
+}
+
+///////////////////////////////////////////////////////////////////////////////////////////
+// segmentation:
+//////////////////////////////////////////////////////////////////////////////////////////////
+
+const segmentationOut = "segmentation_out"
+
+func (tt *VpcSegmentationTest) TestSingleSegmentation(t *testing.T, mode testMode, rc commonvpc.ResourcesContainer,
+	testName string) {
+	tt.Name = testName
+	tt.setMode(mode)
+	tt.UseCases = []vpcmodel.OutputUseCase{vpcmodel.Segmentation}
+	t.Run(tt.Name, func(t *testing.T) {
+		t.Parallel()
+		tt.initTest()
+		vpcConfigs := tt.getVPCConfigs(t, tt.InputConfig, rc)
+		zoning, err := vpcmodel.NewZoningModelFromFile(filepath.Join(GetTestsDirInput(), tt.ZoningFile))
+		require.Nil(t, err)
+		uc := vpcmodel.Segmentation
+		require.Nil(t, tt.initTestFileNames(uc, "", true, false, segmentationOut, false, false, false))
+		og, err := vpcmodel.NewOutputGenerator(vpcConfigs, vpcmodel.NoGroupingNoConsistencyEdges, uc, false,
+			nil, tt.Format, false)
+		require.Nil(t, err)
+		og.SetZoningModel(zoning)
+		actualOutput, err := og.Generate(tt.Format, tt.ActualOutput[uc])
+		require.Nil(t, err)
+		require.Nil(t, compareOrRegenerateOutputPerTest(t, tt.Mode, actualOutput, segmentationOut, tt.Name, tt.ExpectedOutput, uc))
+	})
+}
@@ -9,6 +9,7 @@ package testfunc
 import (
 	_ "embed"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +23,13 @@ const secJSONOutSuffix = "_2nd.json"
 type VpcDiffTest struct {
 	VpcTestCommon
 	InputConfig2nd string // 2nd input file for diff
+	Patch          string // if set, the 2nd config is the input config patched with this patch file (instead of InputConfig2nd)
+}
+
+// patchableResourcesContainer is a ResourcesContainer that supports applying a what-if patch on its resources
+type patchableResourcesContainer interface {
+	commonvpc.ResourcesContainer
+	ApplyPatch(patch *vpcmodel.ConfigPatch) error
 }
 
 func (tt *VpcDiffTest) TestDiffSingle(t *testing.T, mode testMode, rc commonvpc.ResourcesContainer, testDir, testName string) {
@@ -40,7 +48,12 @@ func (tt *VpcDiffTest) runDiffSingleTest(t *testing.T, testDir string, rc common
 
 	// get vpcConfigs obj from parsing + analyzing input config file
 	vpcConfigs := tt.getVPCConfigs(t, tt.InputConfig, rc)
-	vpcConfigs2nd := tt.getVPCConfigs(t, tt.InputConfig2nd, rc)
+	var vpcConfigs2nd *vpcmodel.MultipleVPCConfigs
+	if tt.Patch == "" {
+		vpcConfigs2nd = tt.getVPCConfigs(t, tt.InputConfig2nd, rc)
+	} else {
+		vpcConfigs2nd = tt.getPatchedVPCConfigs(t, rc)
+	}
 	vpcConfigs.SetConfigsToCompare(vpcConfigs2nd.Configs())
 
 	// generate actual output for all use cases specified for this test
@@ -53,3 +66,16 @@ func (tt *VpcDiffTest) runDiffSingleTest(t *testing.T, testDir string, rc common
 		fmt.Printf("test %s use-case %d - generated output file: %s\n", tt.Name, uc, outFile)
 	}
 }
+
+// getPatchedVPCConfigs returns the vpc configs of the input config, after applying the test's patch on its resources
+func (tt *VpcDiffTest) getPatchedVPCConfigs(t *testing.T, rc commonvpc.ResourcesContainer) *vpcmodel.MultipleVPCConfigs {
+	patchableRC, ok := rc.(patchableResourcesContainer)
+	require.True(t, ok, "resources container does not support patches")
+	require.Nil(t, patchableRC.ParseResourcesFromFile(filepath.Join(GetTestsDirInput(), tt.InputConfig)))
+	patch, err := vpcmodel.ReadConfigPatch(filepath.Join(GetTestsDirInput(), tt.Patch))
+	require.Nil(t, err)
+	require.Nil(t, patchableRC.ApplyPatch(patch))
+	vpcConfigs, err := patchableRC.VPCConfigsFromResources(tt.ResourceGroup, tt.VpcList, tt.Regions)
+	require.Nil(t, err)
+	return vpcConfigs
+}
@@ -9,8 +9,6 @@ package commonvpc
 import (
 	"errors"
 	"fmt"
-	"sort"
-	"strings"
 
 	"github.com/np-guard/models/pkg/netset"
 	"github.com/np-guard/models/pkg/spec"
@@ -322,16 +320,39 @@ func (nl *NaclLayer) ConnectivityMap() (map[string]*vpcmodel.IPbasedConnectivity
 	return res, nil
 }
 
-func (nl *NaclLayer) GetConnectivityOutputPerEachElemSeparately() string {
-	res := []string{}
-	// iterate over all subnets, collect all outputs per subnet connectivity
-	for _, nacl := range nl.NaclList {
+func (nl *NaclLayer) ConnectivityPerEachElemSeparately() ([]*vpcmodel.SingleSubnetConnectivity, error) {
+	res := []*vpcmodel.SingleSubnetConnectivity{}
+	// iterate over all subnets, collect the connectivity of each subnet implied by its nacl
+	for naclIndex, nacl := range nl.NaclList {
+		if nacl.Analyzer.NaclAnalyzer.Name() == nil {
+			return nil, fmt.Errorf(EmptyNameError, networkACL, naclIndex)
+		}
+		naclName := *nacl.Analyzer.NaclAnalyzer.Name()
 		for _, subnet := range nacl.Subnets {
-			res = append(res, nacl.GeneralConnectivityPerSubnet(subnet))
+			res = append(res, nacl.Analyzer.ConnectivityPerSubnet(subnet, naclName, naclIndex)...)
 		}
 	}
-	sort.Strings(res)
-	return strings.Join(res, "\n")
+	return res, nil
+}
+
+func (nl *NaclLayer) ConnectivityPerFilter() ([]*vpcmodel.FilterConnectivity, error) {
+	subnetsConnectivity, err := nl.ConnectivityPerEachElemSeparately()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*vpcmodel.FilterConnectivity, len(subnetsConnectivity))
+	for i, subnetConnectivity := range subnetsConnectivity {
+		nacl := nl.NaclList[subnetConnectivity.NaclIndex]
+		res[i] = &vpcmodel.FilterConnectivity{
+			Filter: vpcmodel.Filter{LayerName: networkACL, FilterName: subnetConnectivity.NaclName,
+				FilterIndex: subnetConnectivity.NaclIndex},
+			Resources:  []vpcmodel.VPCResourceIntf{nacl.Subnets[subnetConnectivity.SubnetCidr]},
+			LocalRange: subnetConnectivity.LocalRange,
+			Ingress:    subnetConnectivity.Ingress,
+			Egress:     subnetConnectivity.Egress,
+		}
+	}
+	return res, nil
 }
 
 func (nl *NaclLayer) AllowedConnectivity(src, dst vpcmodel.Node, isIngress bool) (*netset.TransportSet, error) {
@@ -458,11 +479,6 @@ type NACL struct {
 	Analyzer *NACLAnalyzer
 }
 
-func (n *NACL) GeneralConnectivityPerSubnet(subnet *Subnet) string {
-	res, _ := n.Analyzer.GeneralConnectivityPerSubnet(subnet)
-	return res
-}
-
 func subnetFromNode(node vpcmodel.Node) (subnet *Subnet, err error) {
 	switch concreteNode := node.(type) {
 	case vpcmodel.InternalNodeIntf:
@@ -558,8 +574,36 @@ func (sgl *SecurityGroupLayer) ConnectivityMap() (map[string]*vpcmodel.IPbasedCo
 	return nil, nil
 }
 
-func (sgl *SecurityGroupLayer) GetConnectivityOutputPerEachElemSeparately() string {
-	return ""
+func (sgl *SecurityGroupLayer) ConnectivityPerEachElemSeparately() ([]*vpcmodel.SingleSubnetConnectivity, error) {
+	return nil, nil
+}
+
+// ConnectivityPerFilter returns the connectivity of each sg's members implied by that sg alone;
+// members of the same sg sharing the same analyzed connectivity are reported together
+func (sgl *SecurityGroupLayer) ConnectivityPerFilter() ([]*vpcmodel.FilterConnectivity, error) {
+	res := []*vpcmodel.FilterConnectivity{}
+	for sgIndex, sg := range sgl.SgList {
+		if sg.Analyzer.SgAnalyzer.Name() == nil {
+			return nil, fmt.Errorf(EmptyNameError, securityGroup, sgIndex)
+		}
+		thisFilter := vpcmodel.Filter{LayerName: securityGroup, FilterName: *sg.Analyzer.SgAnalyzer.Name(), FilterIndex: sgIndex}
+		type ingressAndEgress struct{ ingress, egress *ConnectivityResult }
+		membersPerConnectivity := map[ingressAndEgress][]vpcmodel.VPCResourceIntf{}
+		connectivityOrder := []ingressAndEgress{}
+		for _, member := range sg.Members {
+			key := ingressAndEgress{sg.Analyzer.connectivityOfLocal(member.IPBlock(), true),
+				sg.Analyzer.connectivityOfLocal(member.IPBlock(), false)}
+			if _, ok := membersPerConnectivity[key]; !ok {
+				connectivityOrder = append(connectivityOrder, key)
+			}
+			membersPerConnectivity[key] = append(membersPerConnectivity[key], member)
+		}
+		for _, key := range connectivityOrder {
+			res = append(res, &vpcmodel.FilterConnectivity{Filter: thisFilter, Resources: membersPerConnectivity[key],
+				Ingress: key.ingress.remotesConnectivity(), Egress: key.egress.remotesConnectivity()})
+		}
+	}
+	return res, nil
 }
 
 // AllowedConnectivity
@@ -29,11 +29,14 @@ type abstractTreeNode struct {
 	location          *Location
 	doNotShowInDrawio bool
 	kind              string
+	uid               string
 }
 
 func (tn *abstractTreeNode) labels() []string    { return []string{tn.name} }
 func (tn *abstractTreeNode) Kind() string        { return tn.kind }
 func (tn *abstractTreeNode) SetKind(kind string) { tn.kind = kind }
+func (tn *abstractTreeNode) UID() string         { return tn.uid }
+func (tn *abstractTreeNode) SetUID(uid string)   { tn.uid = uid }
 
 func (tn *abstractTreeNode) ID() uint       { return tn.id }
 func (tn *abstractTreeNode) TextID() uint   { return tn.id + textID }
@@ -55,6 +55,7 @@ type templateData struct {
 	Nodes        []TreeNodeInterface
 	DebugPoints  []debugPoint
 	Relations    string
+	ViewerData   string
 	Explanations []ExplanationEntry
 	clickable    map[TreeNodeInterface]bool
 	svgNames     map[TreeNodeInterface]string
@@ -72,6 +73,7 @@ func newTemplateData(network SquareTreeNodeInterface, explanations []Explanation
 		orderedNodes,
 		network.DebugPoints(),
 		"",
+		"",
 		explanations,
 		map[TreeNodeInterface]bool{},
 		map[TreeNodeInterface]string{},
@@ -80,6 +82,7 @@ func newTemplateData(network SquareTreeNodeInterface, explanations []Explanation
 	if interactive {
 		data.setNodesNames(network)
 		data.setNodesRelations(network)
+		data.setViewerData(network)
 		for _, e := range data.Explanations {
 			data.clickable[e.Src] = true
 			data.clickable[e.Dst] = true
@@ -131,9 +134,18 @@ func (data *templateData) SvgShortLabel(tn TreeNodeInterface) string {
 func (data *templateData) DrawioLabel(tn TreeNodeInterface) string {
 	return joinLabels(tn.labels(), drawioTableSep)
 }
+func (data *templateData) LayoutUIDStyle(tn TreeNodeInterface) string {
+	return layoutUIDStyle(tn)
+}
 func (data *templateData) Clickable(tn TreeNodeInterface) bool {
 	return data.clickable[tn]
 }
+func (data *templateData) CollapsedContent(tn TreeNodeInterface) string {
+	if subnet, ok := tn.(*SubnetTreeNode); ok {
+		return strings.Join(subnet.collapsedContent, SvgTableSep)
+	}
+	return ""
+}
 
 func (data *templateData) Add(a, b int) int     { return a + b }
 func (data *templateData) Add3(a, b, c int) int { return a + b + c }
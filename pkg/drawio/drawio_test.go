@@ -10,8 +10,12 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/np-guard/cloud-resource-collector/pkg/common"
 )
 
@@ -920,3 +924,55 @@ func createEmptySquaresNetwork() SquareTreeNodeInterface {
 
 	return network
 }
+
+// createNetworkForSeed() creates a network with uids, the order of creating its elements is reversed if reversed is true
+func createNetworkForSeed(reversed bool) *NetworkTreeNode {
+	order := func(names []string) []string {
+		if reversed {
+			slices.Reverse(names)
+		}
+		return names
+	}
+	network := NewNetworkTreeNode()
+	cloud := NewCloudTreeNode(network, "IBM Cloud")
+	cloud.SetUID("IBM Cloud")
+	publicNetwork := NewPublicNetworkTreeNode(network)
+	internet := NewInternetTreeNode(publicNetwork, "internet")
+	internet.SetUID("internet")
+	region := NewRegionTreeNode(cloud, "north")
+	for _, vpcName := range order([]string{"vpc1", "vpc2"}) {
+		vpc := NewVpcTreeNode(region, vpcName)
+		vpc.SetUID(vpcName)
+		for _, zoneName := range order([]string{"zone1", "zone2"}) {
+			zone := NewZoneTreeNode(vpc, zoneName)
+			for _, subnetName := range order([]string{"subnet1", "subnet2"}) {
+				subnet := NewSubnetTreeNode(zone, subnetName, "ip", "key")
+				subnetUID := vpcName + zoneName + subnetName
+				subnet.SetUID(subnetUID)
+				for _, niName := range order([]string{"ni1", "ni2", "ni3"}) {
+					ni := NewNITreeNode(subnet, niName, false)
+					ni.SetUID(subnetUID + niName)
+					NewConnectivityLineTreeNode(network, ni, internet, true, "c")
+				}
+			}
+		}
+	}
+	return network
+}
+
+func TestLayoutSeed(t *testing.T) {
+	seedFile := filepath.Join(t.TempDir(), "seed.drawio")
+	seededFile := filepath.Join(t.TempDir(), "seeded.drawio")
+	createFileFromNetwork(createNetworkForSeed(false), seedFile, false, FileDRAWIO, common.IBM)
+	seed, err := ReadLayoutSeed(seedFile)
+	require.Nil(t, err)
+	reversedNetwork := createNetworkForSeed(true)
+	reversedNetwork.SetLayoutSeed(seed)
+	createFileFromNetwork(reversedNetwork, seededFile, false, FileDRAWIO, common.IBM)
+	seededLayout, err := ReadLayoutSeed(seededFile)
+	require.Nil(t, err)
+	// all the elements are located as they were located in the seed file:
+	require.Equal(t, seed, seededLayout)
+	// the cloud, the region, the internet, 2 vpcs, 4 zones, 8 subnets and 24 NIs:
+	require.Equal(t, 41, len(seed))
+}
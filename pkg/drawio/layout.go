@@ -71,13 +71,18 @@ type layoutS struct {
 	network    SquareTreeNodeInterface
 	matrix     *layoutMatrix
 	subnetMode bool
+	seed       LayoutSeed
 }
 
 func newLayout(network SquareTreeNodeInterface, subnetMode bool) *layoutS {
-	return &layoutS{network: network, matrix: newLayoutMatrix(), subnetMode: subnetMode}
+	return &layoutS{network: network, matrix: newLayoutMatrix(), subnetMode: subnetMode, seed: network.(*NetworkTreeNode).layoutSeed}
 }
 
 func (ly *layoutS) layout() {
+	// 0. in case the layout is seeded, order the squares and icons as they were ordered in the previous file
+	if ly.seed != nil {
+		ly.seed.sortTree(ly.network.(*NetworkTreeNode))
+	}
 	// main layout algorithm:
 	// 1. create a 2D matrix  - for each subnet icon, it set the location in the matrix
 	// in case of subnet mode, set the locations of the subnets
@@ -299,6 +304,7 @@ func (ly *layoutS) layoutSubnetsIcons() {
 						ly.setDefaultLocation(subnet, rowIndex, colIndex)
 						calcGroupsVisibility(subnet)
 						groups := getSubnetIconsOrder(subnet)
+						ly.seed.sortIconsGroups(groups)
 						for _, group := range groups {
 							rowIndex, colIndex = ly.layoutGroupIcons(group, rowIndex, colIndex)
 						}
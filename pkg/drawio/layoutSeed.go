@@ -0,0 +1,285 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package drawio
+
+import (
+	"bytes"
+	"cmp"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ///////////////////////////////////////////////////////////////////////////////////////////////////
+// LayoutSeed holds the locations of the elements of a previously generated drawio file, by their uids.
+// when the layout is seeded, the squares and icons are ordered on the canvas as they were ordered in the previous file,
+// so a small change of the input config does not reshuffle the whole map - only new elements, and elements next to removed ones, move.
+// the uid of each element is written to the drawio file in the style of the element, as the value of layoutUIDStyleKey.
+// elements that are not in the previous file keep the places they would have without the seed.
+// ///////////////////////////////////////////////////////////////////////////////////////////////////
+
+const layoutUIDStyleKey = "layoutUID"
+
+type LayoutSeed map[string]point
+
+// seedCell is an mxCell of the previous drawio file; its x,y are relative to its parent
+type seedCell struct {
+	parent string
+	uid    string
+	x, y   int
+}
+
+// ReadLayoutSeed() reads the locations of the elements of a drawio file that was generated by the analyzer
+// (the file can be edited and saved by drawio, including in its compressed format)
+func ReadLayoutSeed(fileName string) (LayoutSeed, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	cells := map[string]*seedCell{}
+	if errParse := readSeedCells(bytes.NewReader(content), cells); errParse != nil {
+		return nil, fmt.Errorf("failed to parse drawio file %s: %w", fileName, errParse)
+	}
+	seed := LayoutSeed{}
+	for _, cell := range cells {
+		if cell.uid != "" {
+			seed[cell.uid] = cell.absoluteLocation(cells)
+		}
+	}
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("drawio file %s has no elements generated by the analyzer", fileName)
+	}
+	return seed, nil
+}
+
+func readSeedCells(r io.Reader, cells map[string]*seedCell) error {
+	decoder := xml.NewDecoder(r)
+	var currentCell *seedCell
+	userObjectID := ""
+	inDiagram := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "diagram":
+				inDiagram = true
+			case "UserObject", "object":
+				// the id of a cell with custom attributes is held by its wrapping element
+				userObjectID = xmlAttr(t, "id")
+			case "mxCell":
+				id := xmlAttr(t, "id")
+				if id == "" {
+					id = userObjectID
+				}
+				currentCell = &seedCell{parent: xmlAttr(t, "parent"), uid: styleValue(xmlAttr(t, "style"), layoutUIDStyleKey)}
+				cells[id] = currentCell
+			case "mxGeometry":
+				if currentCell != nil {
+					currentCell.x, currentCell.y = xmlIntAttr(t, "x"), xmlIntAttr(t, "y")
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "diagram":
+				inDiagram = false
+			case "UserObject", "object":
+				userObjectID = ""
+			case "mxCell":
+				currentCell = nil
+			}
+		case xml.CharData:
+			// a diagram saved by drawio in its compressed format:
+			if content := strings.TrimSpace(string(t)); inDiagram && content != "" {
+				diagram, errDecompress := decompressDiagram(content)
+				if errDecompress != nil {
+					return errDecompress
+				}
+				if errRead := readSeedCells(strings.NewReader(diagram), cells); errRead != nil {
+					return errRead
+				}
+			}
+		}
+	}
+}
+
+// decompressDiagram() decodes a compressed drawio diagram - base64 of the deflated url-encoded xml
+func decompressDiagram(content string) (string, error) {
+	compressed, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", err
+	}
+	reader := flate.NewReader(bytes.NewReader(compressed))
+	defer reader.Close()
+	encoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return url.PathUnescape(string(encoded))
+}
+
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// xmlIntAttr() returns the value of a numeric attribute, which can be a float in a file saved by drawio
+func xmlIntAttr(element xml.StartElement, name string) int {
+	val, err := strconv.ParseFloat(xmlAttr(element, name), 64)
+	if err != nil {
+		return 0
+	}
+	return int(val)
+}
+
+func styleValue(style, key string) string {
+	for _, entry := range strings.Split(style, ";") {
+		if val, ok := strings.CutPrefix(entry, key+"="); ok {
+			return val
+		}
+	}
+	return ""
+}
+
+func (cell *seedCell) absoluteLocation(cells map[string]*seedCell) point {
+	p := point{cell.x, cell.y}
+	// the number of ancestors is bounded, in case the file has a parents cycle:
+	for parent, i := cells[cell.parent], 0; parent != nil && i < len(cells); parent, i = cells[parent.parent], i+1 {
+		p.X += parent.x
+		p.Y += parent.y
+	}
+	return p
+}
+
+// layoutUID() returns the uid identifying a node across drawio files:
+// the uid of its resource, or, for a zone or a region, the layoutUID of its parent with its name
+func layoutUID(tn TreeNodeInterface) string {
+	if tn.UID() != "" {
+		return tn.UID()
+	}
+	switch tn.(type) {
+	case *ZoneTreeNode, *RegionTreeNode:
+		if parentUID := layoutUID(tn.Parent()); parentUID != "" && tn.labels()[0] != "" {
+			return parentUID + "/" + tn.labels()[0]
+		}
+	}
+	return ""
+}
+
+// layoutUIDStyle() returns the style entry holding the layoutUID of a node, or an empty string if it has none
+func layoutUIDStyle(tn TreeNodeInterface) string {
+	if uid := layoutUID(tn); uid != "" {
+		return layoutUIDStyleKey + "=" + html.EscapeString(uid) + ";"
+	}
+	return ""
+}
+
+// compare() compares the locations of two nodes in the previous file, columns first for horizontal order and rows first for vertical order.
+// nodes that are not in the previous file are after the nodes that are
+func (seed LayoutSeed) compare(tn1, tn2 TreeNodeInterface, vertical bool) int {
+	p1, ok1 := seed[layoutUID(tn1)]
+	p2, ok2 := seed[layoutUID(tn2)]
+	switch {
+	case !ok1 && !ok2:
+		return 0
+	case !ok1:
+		return 1
+	case !ok2:
+		return -1
+	case vertical:
+		return cmp.Or(cmp.Compare(p1.Y, p2.Y), cmp.Compare(p1.X, p2.X))
+	}
+	return cmp.Or(cmp.Compare(p1.X, p2.X), cmp.Compare(p1.Y, p2.Y))
+}
+
+// sortBySeed() sorts nodes by their locations in the previous file;
+// the nodes that are not in it (e.g. new nodes, or nodes that are not shown) keep their places in the slice
+func sortBySeed[T TreeNodeInterface](seed LayoutSeed, nodes []T, vertical bool) {
+	if seed == nil {
+		return
+	}
+	seededIndexes := []int{}
+	seededNodes := []T{}
+	for i, tn := range nodes {
+		if _, ok := seed[layoutUID(tn)]; ok {
+			seededIndexes = append(seededIndexes, i)
+			seededNodes = append(seededNodes, tn)
+		}
+	}
+	slices.SortStableFunc(seededNodes, func(tn1, tn2 T) int { return seed.compare(tn1, tn2, vertical) })
+	for i, tn := range seededNodes {
+		nodes[seededIndexes[i]] = tn
+	}
+}
+
+// sortTree() sorts the children of the squares of the network by their locations in the previous file:
+// clouds, regions, vpcs and zones from left to right, subnets and icons from top to bottom
+func (seed LayoutSeed) sortTree(network *NetworkTreeNode) {
+	sortBySeed(seed, network.clouds, false)
+	for _, cloud := range network.clouds {
+		sortBySeed(seed, cloud.(*CloudTreeNode).regions, false)
+		sortBySeed(seed, cloud.IconTreeNodes(), false)
+		for _, region := range cloud.(*CloudTreeNode).regions {
+			sortBySeed(seed, region.(*RegionTreeNode).vpcs, false)
+			sortBySeed(seed, region.IconTreeNodes(), false)
+			for _, vpc := range region.(*RegionTreeNode).vpcs {
+				sortBySeed(seed, vpc.(*VpcTreeNode).zones, false)
+				sortBySeed(seed, vpc.IconTreeNodes(), false)
+				for _, zone := range vpc.(*VpcTreeNode).zones {
+					sortBySeed(seed, zone.(*ZoneTreeNode).subnets, true)
+					for _, subnet := range zone.(*ZoneTreeNode).subnets {
+						sortBySeed(seed, subnet.IconTreeNodes(), true)
+					}
+				}
+			}
+		}
+	}
+	if network.publicNetwork != nil {
+		sortBySeed(seed, network.publicNetwork.IconTreeNodes(), true)
+	}
+}
+
+// sortIconsGroups() sorts the groups of icons of a subnet, and the icons in each group, by their locations in the previous file
+func (seed LayoutSeed) sortIconsGroups(groups [][]IconTreeNodeInterface) {
+	if seed == nil {
+		return
+	}
+	for _, group := range groups {
+		sortBySeed(seed, group, true)
+	}
+	slices.SortStableFunc(groups, func(g1, g2 []IconTreeNodeInterface) int {
+		// empty groups are last:
+		switch {
+		case len(g1) == 0 && len(g2) == 0:
+			return 0
+		case len(g1) == 0:
+			return 1
+		case len(g2) == 0:
+			return -1
+		}
+		return seed.compare(g1[0], g2[0], true)
+	})
+}
@@ -117,8 +117,16 @@ func newLogicalLineTreeNode(network SquareTreeNodeInterface, i1, i2 IconTreeNode
 type ConnectivityTreeNode struct {
 	abstractLineTreeNode
 	directed bool
+	dropped  bool
+	conn     []ConnProtocol
 }
 
+// SetConn sets the connection of the line, to be used by the filters of the html viewer
+func (tn *ConnectivityTreeNode) SetConn(conn []ConnProtocol) { tn.conn = conn }
+
+// SetDropped marks the line as the part of a routing path on which the traffic is dropped
+func (tn *ConnectivityTreeNode) SetDropped() { tn.dropped = true }
+
 func NewConnectivityLineTreeNode(network SquareTreeNodeInterface,
 	src, dst TreeNodeInterface,
 	directed bool,
@@ -86,6 +86,7 @@ type NetworkTreeNode struct {
 	abstractSquareTreeNode
 	clouds        []SquareTreeNodeInterface
 	publicNetwork SquareTreeNodeInterface
+	layoutSeed    LayoutSeed
 }
 
 func NewNetworkTreeNode() *NetworkTreeNode {
@@ -93,6 +94,9 @@ func NewNetworkTreeNode() *NetworkTreeNode {
 }
 func (tn *NetworkTreeNode) NotShownInDrawio() bool { return true }
 
+// SetLayoutSeed sets the locations of the elements in a previous drawio file, to be kept by the layout
+func (tn *NetworkTreeNode) SetLayoutSeed(seed LayoutSeed) { tn.layoutSeed = seed }
+
 func (tn *NetworkTreeNode) children() ([]SquareTreeNodeInterface, []IconTreeNodeInterface, []LineTreeNodeInterface) {
 	sqs := tn.clouds
 	if tn.publicNetwork != nil {
@@ -247,6 +251,9 @@ type SubnetTreeNode struct {
 	cidr         string
 	acl          string
 	isPrivate    bool
+	// collapsedContent is set when the square represents a collapsed part of the network,
+	// it lists the collapsed resources, to be shown when the square is expanded in the html
+	collapsedContent []string
 }
 
 func NewSubnetTreeNode(parent *ZoneTreeNode, name, cidr, acl string) *SubnetTreeNode {
@@ -276,6 +283,9 @@ func (tn *SubnetTreeNode) nonGroupingIcons() []IconTreeNodeInterface {
 }
 func (tn *SubnetTreeNode) IsPrivate() bool             { return tn.isPrivate }
 func (tn *SubnetTreeNode) SetIsPrivate(isPrivate bool) { tn.isPrivate = isPrivate }
+func (tn *SubnetTreeNode) SetCollapsedContent(content []string) {
+	tn.collapsedContent = content
+}
 
 // /////////////////////////////////////////////////////////////////////////////////////
 // GroupSquareTreeNode is a tree node that represents a group of icons that share the same connectivity
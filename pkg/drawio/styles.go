@@ -134,9 +134,10 @@ const (
 	// currently relevant for line colors:
 	blackColor = "black"
 	blueColor  = "blue"
+	redColor   = "red"
 )
 
-var colorCodes = map[string]string{blackColor: "#000000", blueColor: "#007FFF"}
+var colorCodes = map[string]string{blackColor: "#000000", blueColor: "#007FFF", redColor: "#FF0000"}
 
 // regular go constants can not be shared with the template, so we put them in a struct
 type stylesConsts struct {
@@ -324,6 +325,10 @@ func (stl *templateStyles) lineParameters(tn LineTreeNodeInterface) (start, end,
 		if con.directed {
 			start = ovalEndEdge
 		}
+		if con.dropped {
+			color = redColor
+			dash = true
+		}
 		if con.Src().IsIcon() && con.Src().(IconTreeNodeInterface).IsGroupingPoint() && !con.Src().(*GroupPointTreeNode).hasShownSquare() {
 			start = noneEndEdge
 		}
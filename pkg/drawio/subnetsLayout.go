@@ -141,6 +141,8 @@ type subnetsLayout struct {
 	zonesCol          map[TreeNodeInterface]int
 	treeNodesToGroups map[TreeNodeInterface]*groupDataS
 	topFakeGroup      *groupDataS
+	// seed - the locations of the squares in a previous file, used to choose between equivalent layouts
+	seed LayoutSeed
 }
 
 func newSubnetsLayout(network SquareTreeNodeInterface) *subnetsLayout {
@@ -150,6 +152,7 @@ func newSubnetsLayout(network SquareTreeNodeInterface) *subnetsLayout {
 		subnetsIndexes:    map[TreeNodeInterface]indexes{},
 		zonesCol:          map[TreeNodeInterface]int{},
 		treeNodesToGroups: map[TreeNodeInterface]*groupDataS{},
+		seed:              network.(*NetworkTreeNode).layoutSeed,
 	}
 }
 
@@ -522,20 +525,62 @@ func (ly *subnetsLayout) setZonesCol(zoneOrders [][]TreeNodeInterface) {
 		vpc := order[0].Parent()
 		vpcToOrders[vpc] = append(vpcToOrders[vpc], order)
 	}
+	vpcs := slices.Collect(maps.Keys(vpcToOrders))
+	ly.sortZoneOrdersBySeed(vpcs, vpcToOrders)
 	i := 0
-	for _, vpcOrders := range vpcToOrders {
-		for _, order := range vpcOrders {
+	for _, vpc := range vpcs {
+		for _, order := range vpcToOrders[vpc] {
 			for _, z := range order {
 				ly.zonesCol[z] = i
 				i++
 			}
 		}
 	}
+	zonesWithNoOrder := []TreeNodeInterface{}
 	for miniGroup := range ly.miniGroups {
-		if _, ok := ly.zonesCol[miniGroup.zone]; !ok {
-			ly.zonesCol[miniGroup.zone] = len(ly.zonesCol)
+		if _, ok := ly.zonesCol[miniGroup.zone]; !ok && !slices.Contains(zonesWithNoOrder, miniGroup.zone) {
+			zonesWithNoOrder = append(zonesWithNoOrder, miniGroup.zone)
 		}
 	}
+	sortBySeed(ly.seed, zonesWithNoOrder, false)
+	for _, z := range zonesWithNoOrder {
+		ly.zonesCol[z] = len(ly.zonesCol)
+	}
+}
+
+// sortZoneOrdersBySeed() sorts the vpcs and their zoneOrders by the locations of the zones in the previous file.
+// a zoneOrder that was from right to left in the previous file is reversed
+func (ly *subnetsLayout) sortZoneOrdersBySeed(vpcs []TreeNodeInterface, vpcToOrders map[TreeNodeInterface][][]TreeNodeInterface) {
+	if ly.seed == nil {
+		return
+	}
+	for _, orders := range vpcToOrders {
+		for _, order := range orders {
+			if ly.seed.compare(order[0], order[len(order)-1], false) > 0 {
+				slices.Reverse(order)
+			}
+		}
+		slices.SortStableFunc(orders, func(o1, o2 []TreeNodeInterface) int { return ly.seed.compare(o1[0], o2[0], false) })
+	}
+	sortBySeed(ly.seed, vpcs, false)
+}
+
+// seedOrderedSubnets() returns the subnets, ordered by their locations in the previous file
+func (ly *subnetsLayout) seedOrderedSubnets(subnets subnetSet) []TreeNodeInterface {
+	res := subnets.AsList()
+	sortBySeed(ly.seed, res, true)
+	return res
+}
+
+// seedOrderedMiniGroups() returns the miniGroups, ordered by the locations of their subnets in the previous file
+func (ly *subnetsLayout) seedOrderedMiniGroups(miniGroups miniGroupSet) []*miniGroupDataS {
+	res := miniGroups.AsList()
+	if ly.seed != nil {
+		slices.SortStableFunc(res, func(mg1, mg2 *miniGroupDataS) int {
+			return ly.seed.compare(ly.seedOrderedSubnets(mg1.subnets)[0], ly.seedOrderedSubnets(mg2.subnets)[0], true)
+		})
+	}
+	return res
 }
 
 func (ly *subnetsLayout) calcZonePairScores() map[TreeNodeInterface]map[TreeNodeInterface]int {
@@ -631,7 +676,7 @@ func (ly *subnetsLayout) layoutGroup(group *groupDataS, parentFirstRow int) {
 	for _, child := range childrenOrder {
 		ly.layoutGroup(child, firstRow)
 	}
-	for miniGroup := range group.miniGroups {
+	for _, miniGroup := range ly.seedOrderedMiniGroups(group.miniGroups) {
 		if miniGroup.located {
 			continue
 		}
@@ -717,7 +762,7 @@ func (ly *subnetsLayout) setSubnetsMatrix() {
 			if rowSize < len(miniGroup.subnets) {
 				rowSize = len(miniGroup.subnets)
 			}
-			for s := range miniGroup.subnets {
+			for _, s := range ly.seedOrderedSubnets(miniGroup.subnets) {
 				ly.subnetMatrix[rIndex+i][colIndex] = s
 				ly.subnetsIndexes[s] = indexes{rIndex + i, colIndex}
 				i++
@@ -59,6 +59,8 @@ type TreeNodeInterface interface {
 	labels() []string
 	Kind() string
 	SetKind(string)
+	UID() string
+	SetUID(string)
 
 	DrawioParent() TreeNodeInterface
 	Parent() TreeNodeInterface
@@ -0,0 +1,142 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package drawio
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+)
+
+//////////////////////////////////////////////////////////////////////
+// setViewerData() embeds in the html the data used by its client-side viewer:
+// the properties of the endpoints, by which they can be searched and filtered,
+// and the connectivity lines, by which the lines can be filtered and the reachability of an endpoint is computed
+/////////////////////////////////////////////////////////////////////////////
+
+// ConnProtocol is the part of a connection with one protocol (TCP, UDP or ICMP), as used by the html viewer filters;
+// DstPorts are the allowed destination ports for TCP and UDP, and are empty for ICMP
+type ConnProtocol struct {
+	Protocol string      `json:"protocol"`
+	DstPorts []PortRange `json:"dst_ports,omitempty"`
+}
+
+type PortRange struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+type viewerEndpoint struct {
+	Name     string   `json:"name"`
+	Vpc      string   `json:"vpc,omitempty"`
+	Zone     string   `json:"zone,omitempty"`
+	Subnet   string   `json:"subnet,omitempty"`
+	SGs      []string `json:"sgs,omitempty"`
+	External bool     `json:"external"`
+}
+
+// viewerLine is a connectivity line of the canvas; in case of a grouped line, srcs and dsts are the grouped nodes,
+// and elements are all the lines and grouping points drawing it
+type viewerLine struct {
+	Srcs     []string       `json:"srcs"`
+	Dsts     []string       `json:"dsts"`
+	Directed bool           `json:"directed"`
+	Conn     []ConnProtocol `json:"conn"`
+	Elements []string       `json:"elements"`
+}
+
+type viewerData struct {
+	Endpoints map[string]*viewerEndpoint `json:"endpoints"`
+	Lines     []*viewerLine              `json:"lines"`
+}
+
+func (data *templateData) setViewerData(network TreeNodeInterface) {
+	res := viewerData{Endpoints: map[string]*viewerEndpoint{}, Lines: []*viewerLine{}}
+	for _, icon := range getAllIcons(network) {
+		if !icon.IsGroupingPoint() && !icon.IsGateway() && !icon.NotShownInDrawio() {
+			res.Endpoints[common.UintToString(icon.ID())] = data.newViewerEndpoint(icon)
+		}
+	}
+	for _, line := range getAllLines(network) {
+		connLine, ok := line.(*ConnectivityTreeNode)
+		if !ok {
+			continue
+		}
+		info := getLineInfo(connLine)
+		if info == nil {
+			continue
+		}
+		vLine := &viewerLine{Srcs: lineEnds(info.src, info.srcGroupingLines, true),
+			Dsts: lineEnds(info.dst, info.dstGroupingLines, false), Directed: connLine.directed, Conn: connLine.conn,
+			Elements: []string{common.UintToString(connLine.ID())}}
+		for _, tn := range []TreeNodeInterface{info.srcGroupingPoint, info.dstGroupingPoint} {
+			if tn != nil {
+				vLine.Elements = append(vLine.Elements, common.UintToString(tn.ID()))
+			}
+		}
+		for _, l := range append(slices.Clone(info.srcGroupingLines), info.dstGroupingLines...) {
+			vLine.Elements = append(vLine.Elements, common.UintToString(l.ID()))
+		}
+		// in subnets mode, the line ends are squares, and on routing paths they can also be gateways:
+		for _, tn := range []TreeNodeInterface{info.src, info.dst} {
+			if tnID := common.UintToString(tn.ID()); (tn.IsSquare() || tn.(IconTreeNodeInterface).IsGateway()) && res.Endpoints[tnID] == nil {
+				res.Endpoints[tnID] = data.newViewerEndpoint(tn)
+			}
+		}
+		res.Lines = append(res.Lines, vLine)
+	}
+	b, _ := json.Marshal(res)
+	data.ViewerData = string(b)
+}
+
+// lineEnds returns the ids of the nodes at one end of a line, which are the grouped nodes in case of a grouping point
+func lineEnds(end TreeNodeInterface, groupingLines []LineTreeNodeInterface, isSrc bool) []string {
+	if len(groupingLines) == 0 {
+		return []string{common.UintToString(end.ID())}
+	}
+	res := make([]string, len(groupingLines))
+	for i, l := range groupingLines {
+		if isSrc {
+			res[i] = common.UintToString(l.Src().ID())
+		} else {
+			res[i] = common.UintToString(l.Dst().ID())
+		}
+	}
+	return res
+}
+
+// viewerName returns the name of a square, without its other labels (e.g. the cidr of a subnet)
+func viewerName(tn TreeNodeInterface) string {
+	if labels := tn.labels(); len(labels) > 0 {
+		return labels[0]
+	}
+	return ""
+}
+
+func (data *templateData) newViewerEndpoint(tn TreeNodeInterface) *viewerEndpoint {
+	res := &viewerEndpoint{Name: data.NodeName(tn)}
+	for _, parent := range nodeParents(tn) {
+		switch parent.(type) {
+		case *VpcTreeNode:
+			res.Vpc = viewerName(parent)
+		case *ZoneTreeNode:
+			res.Zone = viewerName(parent)
+		case *SubnetTreeNode:
+			res.Subnet = viewerName(parent)
+		case *PublicNetworkTreeNode:
+			res.External = true
+		}
+	}
+	if icon, ok := tn.(IconTreeNodeInterface); ok {
+		for sg := range icon.SGs() {
+			res.SGs = append(res.SGs, viewerName(sg))
+		}
+		slices.Sort(res.SGs)
+	}
+	return res
+}
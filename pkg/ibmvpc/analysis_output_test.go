@@ -321,6 +321,46 @@ var tests = []*testfunc.VpcAnalysisTest{
 			Format:      vpcmodel.JSON,
 		},
 	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.AllEndpoints},
+			Format:      vpcmodel.JSON,
+		},
+		GroupingType: vpcmodel.GroupingNoConsistencyEdges,
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "multiple_vpcs",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.AllSubnets},
+			Format:      vpcmodel.JSON,
+		},
+		GroupingType: vpcmodel.GroupingNoConsistencyEdges,
+	},
+	// single-subnet use-case with json and md formats, and with grouping of remote cidrs
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.SingleSubnet},
+			Format:      vpcmodel.JSON,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.SingleSubnet},
+			Format:      vpcmodel.MD,
+		},
+		GroupingType: vpcmodel.GroupingNoConsistencyEdges,
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.SingleSubnet},
+			Format:      vpcmodel.Text,
+		},
+		GroupingType: vpcmodel.GroupingNoConsistencyEdges,
+	},
 	// multi-vpc config example
 	{
 		VpcTestCommon: testfunc.VpcTestCommon{
@@ -760,6 +800,84 @@ var tests = []*testfunc.VpcAnalysisTest{
 			Format:      vpcmodel.Text,
 		},
 	},
+	// exposure to external networks
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Exposure},
+			Format:      vpcmodel.Text,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Exposure},
+			Format:      vpcmodel.Text,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Exposure},
+			Format:      vpcmodel.MD,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "load_balancer",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Exposure},
+			Format:      vpcmodel.JSON,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Filters},
+			Format:      vpcmodel.Text,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Filters},
+			Format:      vpcmodel.MD,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.Filters},
+			Format:      vpcmodel.JSON,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.RuleUsage},
+			Format:      vpcmodel.Text,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "acl_testing3",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.RuleUsage},
+			Format:      vpcmodel.JSON,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.RuleUsage},
+			Format:      vpcmodel.MD,
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "tf_ibm",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.AllEndpoints},
+			Format:      vpcmodel.Text,
+		},
+	},
 }
 
 // uncomment the function below to run for updating the expected output
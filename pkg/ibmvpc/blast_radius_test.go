@@ -0,0 +1,78 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ibmvpc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/np-guard/models/pkg/netp"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc/testfunc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+var blastRadiusTests = []*testfunc.VpcBlastRadiusTest{
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "FromVsi2",
+			InputConfig: "sg_testing1_new",
+			Format:      vpcmodel.Text,
+		},
+		ESrc: "vsi2-ky",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "FromVsi2TCPPort22",
+			InputConfig: "sg_testing1_new",
+			Format:      vpcmodel.MD,
+		},
+		ESrc:        "vsi2-ky",
+		EProtocol:   netp.ProtocolStringTCP,
+		ESrcMinPort: netp.MinPort,
+		ESrcMaxPort: netp.MaxPort,
+		EDstMinPort: 22,
+		EDstMaxPort: 22,
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "NoReachable",
+			InputConfig: "sg_testing1_new",
+			Format:      vpcmodel.Text,
+		},
+		ESrc: "vsi1-ky",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "MultiVPC",
+			InputConfig: "tgw_larger_example",
+			Format:      vpcmodel.JSON,
+		},
+		ESrc: "vsi11-ky",
+	},
+}
+
+func TestBlastRadiusWithComparison(t *testing.T) {
+	// blastRadiusTests is the list of tests to run
+	for testIdx := range blastRadiusTests {
+		tt := blastRadiusTests[testIdx]
+		tt.TestSingleBlastRadius(t, testfunc.OutputComparison, NewIBMresourcesContainer(), tt.Name)
+	}
+	fmt.Println("done")
+}
+
+// uncomment the function below for generating the expected output files instead of comparing
+
+/*
+func TestBlastRadiusWithGeneration(t *testing.T) {
+	// blastRadiusTests is the list of tests to run
+	for testIdx := range blastRadiusTests {
+		tt := blastRadiusTests[testIdx]
+		tt.TestSingleBlastRadius(t, testfunc.OutputGeneration, NewIBMresourcesContainer(), tt.Name)
+	}
+	fmt.Println("done")
+}*/
@@ -0,0 +1,399 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ibmvpc
+
+import (
+	"fmt"
+	"slices"
+
+	vpc1 "github.com/IBM/vpc-go-sdk/vpcv1"
+
+	"github.com/np-guard/cloud-resource-collector/pkg/ibm/datamodel"
+	"github.com/np-guard/models/pkg/netp"
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+// functionality for applying a what-if patch (vpcmodel.ConfigPatch) on the resources of an IBMresourcesContainer
+
+const (
+	protocolAll       = "all"
+	protocolICMP      = "icmp"
+	protocolTCP       = "tcp"
+	protocolUDP       = "udp"
+	patchRuleIDPrefix = "patch-rule-"
+	ipVersion         = "ipv4"
+)
+
+// PatchedVpcConfigsFromFiles is as VpcConfigsFromFiles, with the patch applied on the resources before
+// generating the configs
+func (rc *IBMresourcesContainer) PatchedVpcConfigsFromFiles(fileNames []string, patch *vpcmodel.ConfigPatch,
+	resourceGroup string, vpcIDs, regions []string) (*vpcmodel.MultipleVPCConfigs, error) {
+	rc, err1 := rc.mergeResourcesFromFiles(fileNames)
+	if err1 != nil {
+		return nil, err1
+	}
+	if err := rc.ApplyPatch(patch); err != nil {
+		return nil, fmt.Errorf("error applying patch: %w", err)
+	}
+	vpcConfigs, err2 := rc.VPCConfigsFromResources(resourceGroup, vpcIDs, regions)
+	if err2 != nil {
+		return nil, fmt.Errorf("error generating cloud config from patched vpc resources: %w", err2)
+	}
+	return vpcConfigs, nil
+}
+
+// ApplyPatch applies the changes of the patch, in order, on the resources of rc
+func (rc *IBMresourcesContainer) ApplyPatch(patch *vpcmodel.ConfigPatch) error {
+	for i, change := range patch.Changes {
+		var err error
+		switch change.Op {
+		case vpcmodel.AddSGRule:
+			err = rc.addSGRule(change, i)
+		case vpcmodel.RemoveSGRule:
+			err = rc.removeSGRule(change)
+		case vpcmodel.AttachSG, vpcmodel.DetachSG:
+			err = rc.attachOrDetachSG(change)
+		case vpcmodel.AddNACLRule:
+			err = rc.addNACLRule(change, i)
+		case vpcmodel.RemoveNACLRule:
+			err = rc.removeNACLRule(change)
+		case vpcmodel.SetSubnetNACL:
+			err = rc.setSubnetNACL(change)
+		case vpcmodel.AttachPGW, vpcmodel.DetachPGW:
+			err = rc.attachOrDetachPGW(change)
+		default:
+			err = fmt.Errorf("unsupported op %q", change.Op)
+		}
+		if err != nil {
+			return fmt.Errorf("change %d (%s): %w", i, change.Op, err)
+		}
+	}
+	return nil
+}
+
+// getByName returns the single element of list with the given name
+func getByName[T any](list []T, name, kind string, nameOf func(T) *string) (res T, err error) {
+	found := false
+	for _, elem := range list {
+		if n := nameOf(elem); n != nil && *n == name {
+			if found {
+				return res, fmt.Errorf("%s name %s is ambiguous", kind, name)
+			}
+			res, found = elem, true
+		}
+	}
+	if !found {
+		return res, fmt.Errorf("could not find %s %s", kind, name)
+	}
+	return res, nil
+}
+
+func (rc *IBMresourcesContainer) getSG(name string) (*datamodel.SecurityGroup, error) {
+	return getByName(rc.SecurityGroupList, name, "security group",
+		func(sg *datamodel.SecurityGroup) *string { return sg.Name })
+}
+
+func (rc *IBMresourcesContainer) getNACL(name string) (*datamodel.NetworkACL, error) {
+	return getByName(rc.NetworkACLList, name, "network ACL",
+		func(nacl *datamodel.NetworkACL) *string { return nacl.Name })
+}
+
+func (rc *IBMresourcesContainer) getSubnet(name string) (*datamodel.Subnet, error) {
+	return getByName(rc.SubnetList, name, "subnet",
+		func(subnet *datamodel.Subnet) *string { return subnet.Name })
+}
+
+func (rc *IBMresourcesContainer) getInstance(name string) (*datamodel.Instance, error) {
+	return getByName(rc.InstanceList, name, "instance",
+		func(instance *datamodel.Instance) *string { return instance.Name })
+}
+
+func (rc *IBMresourcesContainer) getPGW(name string) (*datamodel.PublicGateway, error) {
+	return getByName(rc.PublicGWList, name, "public gateway",
+		func(pgw *datamodel.PublicGateway) *string { return pgw.Name })
+}
+
+func validatePatchRule(rule *vpcmodel.PatchRule) error {
+	if rule == nil {
+		return fmt.Errorf("missing new_rule")
+	}
+	if rule.Direction != commonvpc.Inbound && rule.Direction != commonvpc.Outbound {
+		return fmt.Errorf("illegal rule direction %q", rule.Direction)
+	}
+	switch rule.Protocol {
+	case protocolAll, protocolICMP, protocolTCP, protocolUDP:
+		return nil
+	default:
+		return fmt.Errorf("illegal rule protocol %q", rule.Protocol)
+	}
+}
+
+func portOrDefault(port *int64, defaultPort int64) *int64 {
+	if port == nil {
+		return &defaultPort
+	}
+	return port
+}
+
+// newSGRuleRemote returns the remote of a SG rule from a string that is a cidr, an ip address or a SG name
+func newSGRuleRemote(remote string) *vpc1.SecurityGroupRuleRemote {
+	if remote == "" {
+		remote = netset.CidrAll
+	}
+	if _, err := netset.IPBlockFromCidr(remote); err == nil {
+		return &vpc1.SecurityGroupRuleRemote{CIDRBlock: &remote}
+	}
+	if _, err := netset.IPBlockFromIPAddress(remote); err == nil {
+		return &vpc1.SecurityGroupRuleRemote{Address: &remote}
+	}
+	return &vpc1.SecurityGroupRuleRemote{Name: &remote}
+}
+
+func newSGRuleLocal(local string) *vpc1.SecurityGroupRuleLocal {
+	if local == "" {
+		local = netset.CidrAll
+	}
+	if _, err := netset.IPBlockFromIPAddress(local); err == nil {
+		return &vpc1.SecurityGroupRuleLocal{Address: &local}
+	}
+	return &vpc1.SecurityGroupRuleLocal{CIDRBlock: &local}
+}
+
+func newSGRule(rule *vpcmodel.PatchRule, id string) vpc1.SecurityGroupRuleIntf {
+	direction, protocol, ipv := rule.Direction, rule.Protocol, ipVersion
+	remote, local := newSGRuleRemote(rule.Remote), newSGRuleLocal(rule.Local)
+	switch protocol {
+	case protocolAll:
+		return &vpc1.SecurityGroupRuleSecurityGroupRuleProtocolAll{Direction: &direction, ID: &id, IPVersion: &ipv,
+			Protocol: &protocol, Remote: remote, Local: local}
+	case protocolICMP:
+		return &vpc1.SecurityGroupRuleSecurityGroupRuleProtocolIcmp{Direction: &direction, ID: &id, IPVersion: &ipv,
+			Protocol: &protocol, Remote: remote, Local: local, Type: rule.ICMPType, Code: rule.ICMPCode}
+	default:
+		return &vpc1.SecurityGroupRuleSecurityGroupRuleProtocolTcpudp{Direction: &direction, ID: &id, IPVersion: &ipv,
+			Protocol: &protocol, Remote: remote, Local: local,
+			PortMin: portOrDefault(rule.DstPortMin, netp.MinPort), PortMax: portOrDefault(rule.DstPortMax, netp.MaxPort)}
+	}
+}
+
+func (rc *IBMresourcesContainer) addSGRule(change *vpcmodel.ConfigChange, changeIndex int) error {
+	sg, err := rc.getSG(change.SG)
+	if err != nil {
+		return err
+	}
+	if err := validatePatchRule(change.NewRule); err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s%d", patchRuleIDPrefix, changeIndex)
+	sg.Rules = append(sg.Rules, newSGRule(change.NewRule, id))
+	return nil
+}
+
+func sgRuleID(rule vpc1.SecurityGroupRuleIntf) *string {
+	switch ruleObj := rule.(type) {
+	case *vpc1.SecurityGroupRuleSecurityGroupRuleProtocolAll:
+		return ruleObj.ID
+	case *vpc1.SecurityGroupRuleSecurityGroupRuleProtocolTcpudp:
+		return ruleObj.ID
+	case *vpc1.SecurityGroupRuleSecurityGroupRuleProtocolIcmp:
+		return ruleObj.ID
+	}
+	return nil
+}
+
+func (rc *IBMresourcesContainer) removeSGRule(change *vpcmodel.ConfigChange) error {
+	sg, err := rc.getSG(change.SG)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(sg.Rules, func(rule vpc1.SecurityGroupRuleIntf) bool {
+		id := sgRuleID(rule)
+		return id != nil && *id == change.RuleID
+	})
+	if index < 0 {
+		return fmt.Errorf("could not find rule %s in security group %s", change.RuleID, change.SG)
+	}
+	sg.Rules = slices.Delete(sg.Rules, index, index+1)
+	return nil
+}
+
+// instanceTargets returns the SG targets representing the network interfaces of an instance:
+// its virtual network interfaces if there are such, and otherwise its network interfaces
+func (rc *IBMresourcesContainer) instanceTargets(instance *datamodel.Instance) []*vpc1.SecurityGroupTargetReference {
+	res := []*vpc1.SecurityGroupTargetReference{}
+	vniType, niType := commonvpc.VirtualNetworkInterfaceResourceType, commonvpc.NetworkInterfaceResourceType
+	for j := range instance.NetworkAttachments {
+		for _, vni := range rc.VirtualNIList {
+			if target, ok := vni.Target.(*vpc1.VirtualNetworkInterfaceTarget); ok &&
+				*target.ID == *instance.NetworkAttachments[j].ID {
+				res = append(res, &vpc1.SecurityGroupTargetReference{ID: vni.ID, Name: vni.Name, ResourceType: &vniType})
+			}
+		}
+	}
+	if len(instance.NetworkAttachments) > 0 {
+		return res
+	}
+	for j := range instance.NetworkInterfaces {
+		netintf := instance.NetworkInterfaces[j]
+		res = append(res, &vpc1.SecurityGroupTargetReference{ID: netintf.ID, Name: netintf.Name, ResourceType: &niType})
+	}
+	return res
+}
+
+func (rc *IBMresourcesContainer) attachOrDetachSG(change *vpcmodel.ConfigChange) error {
+	sg, err := rc.getSG(change.SG)
+	if err != nil {
+		return err
+	}
+	instance, err := rc.getInstance(change.Instance)
+	if err != nil {
+		return err
+	}
+	for _, target := range rc.instanceTargets(instance) {
+		index := slices.IndexFunc(sg.Targets, func(t vpc1.SecurityGroupTargetReferenceIntf) bool {
+			ref, ok := t.(*vpc1.SecurityGroupTargetReference)
+			return ok && ref.ID != nil && *ref.ID == *target.ID
+		})
+		switch {
+		case change.Op == vpcmodel.AttachSG && index < 0:
+			sg.Targets = append(sg.Targets, target)
+		case change.Op == vpcmodel.DetachSG && index >= 0:
+			sg.Targets = slices.Delete(sg.Targets, index, index+1)
+		}
+	}
+	return nil
+}
+
+func newNACLRule(rule *vpcmodel.PatchRule, id string) vpc1.NetworkACLRuleItemIntf {
+	name, action, direction, protocol, ipv := rule.Name, rule.Action, rule.Direction, rule.Protocol, ipVersion
+	src, dst := rule.Source, rule.Destination
+	for _, str := range []*string{&src, &dst} {
+		if *str == "" {
+			*str = netset.CidrAll
+		}
+	}
+	if name == "" {
+		name = id
+	}
+	if action == "" {
+		action = commonvpc.ALLOW
+	}
+	switch protocol {
+	case protocolAll:
+		return &vpc1.NetworkACLRuleItemNetworkACLRuleProtocolAll{Name: &name, ID: &id, Action: &action,
+			Direction: &direction, Protocol: &protocol, IPVersion: &ipv, Source: &src, Destination: &dst}
+	case protocolICMP:
+		return &vpc1.NetworkACLRuleItemNetworkACLRuleProtocolIcmp{Name: &name, ID: &id, Action: &action,
+			Direction: &direction, Protocol: &protocol, IPVersion: &ipv, Source: &src, Destination: &dst,
+			Type: rule.ICMPType, Code: rule.ICMPCode}
+	default:
+		return &vpc1.NetworkACLRuleItemNetworkACLRuleProtocolTcpudp{Name: &name, ID: &id, Action: &action,
+			Direction: &direction, Protocol: &protocol, IPVersion: &ipv, Source: &src, Destination: &dst,
+			SourcePortMin: portOrDefault(rule.SrcPortMin, netp.MinPort), SourcePortMax: portOrDefault(rule.SrcPortMax, netp.MaxPort),
+			DestinationPortMin: portOrDefault(rule.DstPortMin, netp.MinPort),
+			DestinationPortMax: portOrDefault(rule.DstPortMax, netp.MaxPort)}
+	}
+}
+
+func naclRuleName(rule vpc1.NetworkACLRuleItemIntf) *string {
+	switch ruleObj := rule.(type) {
+	case *vpc1.NetworkACLRuleItemNetworkACLRuleProtocolAll:
+		return ruleObj.Name
+	case *vpc1.NetworkACLRuleItemNetworkACLRuleProtocolTcpudp:
+		return ruleObj.Name
+	case *vpc1.NetworkACLRuleItemNetworkACLRuleProtocolIcmp:
+		return ruleObj.Name
+	}
+	return nil
+}
+
+func naclRuleIndex(nacl *datamodel.NetworkACL, name string) (int, error) {
+	index := slices.IndexFunc(nacl.Rules, func(rule vpc1.NetworkACLRuleItemIntf) bool {
+		ruleName := naclRuleName(rule)
+		return ruleName != nil && *ruleName == name
+	})
+	if index < 0 {
+		return index, fmt.Errorf("could not find rule %s in network ACL %s", name, *nacl.Name)
+	}
+	return index, nil
+}
+
+// addNACLRule inserts the new rule before the rule named change.Before, or as the first rule if Before is empty
+func (rc *IBMresourcesContainer) addNACLRule(change *vpcmodel.ConfigChange, changeIndex int) error {
+	nacl, err := rc.getNACL(change.NACL)
+	if err != nil {
+		return err
+	}
+	if err := validatePatchRule(change.NewRule); err != nil {
+		return err
+	}
+	index := 0
+	if change.Before != "" {
+		if index, err = naclRuleIndex(nacl, change.Before); err != nil {
+			return err
+		}
+	}
+	id := fmt.Sprintf("%s%d", patchRuleIDPrefix, changeIndex)
+	nacl.Rules = slices.Insert(nacl.Rules, index, newNACLRule(change.NewRule, id))
+	return nil
+}
+
+func (rc *IBMresourcesContainer) removeNACLRule(change *vpcmodel.ConfigChange) error {
+	nacl, err := rc.getNACL(change.NACL)
+	if err != nil {
+		return err
+	}
+	index, err := naclRuleIndex(nacl, change.RuleName)
+	if err != nil {
+		return err
+	}
+	nacl.Rules = slices.Delete(nacl.Rules, index, index+1)
+	return nil
+}
+
+// setSubnetNACL detaches the subnet from its current nacl and attaches it to the nacl of the change
+func (rc *IBMresourcesContainer) setSubnetNACL(change *vpcmodel.ConfigChange) error {
+	subnet, err := rc.getSubnet(change.Subnet)
+	if err != nil {
+		return err
+	}
+	nacl, err := rc.getNACL(change.NACL)
+	if err != nil {
+		return err
+	}
+	for _, otherNACL := range rc.NetworkACLList {
+		otherNACL.Subnets = slices.DeleteFunc(otherNACL.Subnets, func(s vpc1.SubnetReference) bool {
+			return s.CRN != nil && *s.CRN == *subnet.CRN
+		})
+	}
+	nacl.Subnets = append(nacl.Subnets, vpc1.SubnetReference{CRN: subnet.CRN, ID: subnet.ID, Name: subnet.Name})
+	subnet.NetworkACL = &vpc1.NetworkACLReference{CRN: nacl.CRN, ID: nacl.ID, Name: nacl.Name}
+	return nil
+}
+
+func (rc *IBMresourcesContainer) attachOrDetachPGW(change *vpcmodel.ConfigChange) error {
+	subnet, err := rc.getSubnet(change.Subnet)
+	if err != nil {
+		return err
+	}
+	if change.Op == vpcmodel.DetachPGW {
+		if subnet.PublicGateway == nil {
+			return fmt.Errorf("subnet %s has no public gateway", change.Subnet)
+		}
+		subnet.PublicGateway = nil
+		return nil
+	}
+	pgw, err := rc.getPGW(change.PGW)
+	if err != nil {
+		return err
+	}
+	subnet.PublicGateway = &vpc1.PublicGatewayReference{CRN: pgw.CRN, ID: pgw.ID, Name: pgw.Name}
+	return nil
+}
@@ -90,6 +90,41 @@ var diffTests = []*testfunc.VpcDiffTest{
 			Format:      vpcmodel.Text,
 		},
 	},
+	{
+		// what-if diff against a patched copy of the config
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "sg_testing1_new_patched",
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.EndpointsDiff},
+			Format:      vpcmodel.Text,
+		},
+		Patch: "patch_sg_testing1_new.yaml",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "sg_testing1_new_patched",
+			InputConfig: "sg_testing1_new",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.SubnetsDiff},
+			Format:      vpcmodel.MD,
+		},
+		Patch: "patch_sg_testing1_new.yaml",
+	},
+	{
+		// terraform state vs. terraform plan
+		VpcTestCommon: testfunc.VpcTestCommon{
+			InputConfig: "tf_ibm",
+			UseCases:    []vpcmodel.OutputUseCase{vpcmodel.EndpointsDiff},
+			Format:      vpcmodel.Text,
+		},
+	},
+}
+
+// diffTestName returns the name of a diff test, which is the name of its input config unless specified otherwise
+func diffTestName(tt *testfunc.VpcDiffTest) string {
+	if tt.Name != "" {
+		return tt.Name
+	}
+	return tt.InputConfig
 }
 
 // uncomment the function below to run for updating the expected output
@@ -99,7 +134,7 @@ var diffTests = []*testfunc.VpcDiffTest{
 		// tests is the list of tests to run
 		for testIdx := range diffTests {
 			tt := diffTests[testIdx]
-			tt.TestDiffSingle(t, testfunc.OutputGeneration, NewIBMresourcesContainer(), diffOut, tt.InputConfig)
+			tt.TestDiffSingle(t, testfunc.OutputGeneration, NewIBMresourcesContainer(), diffOut, diffTestName(tt))
 		}
 		fmt.Println("done")
 	}
@@ -111,7 +146,7 @@ func TestDiffWithComparison(t *testing.T) {
 	// tests is the list of tests to run
 	for testIdx := range diffTests {
 		tt := diffTests[testIdx]
-		tt.TestDiffSingle(t, testfunc.OutputComparison, NewIBMresourcesContainer(), diffOut, tt.InputConfig)
+		tt.TestDiffSingle(t, testfunc.OutputComparison, NewIBMresourcesContainer(), diffOut, diffTestName(tt))
 	}
 	fmt.Println("done")
 }
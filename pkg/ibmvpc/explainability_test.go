@@ -771,6 +771,46 @@ var explainTests = []*testfunc.VpcExplainTest{
 		EDst:          "vsi1-ky",
 		DetailExplain: true,
 	},
+	// remediation: SG rules to add at both egress and ingress
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "VsiToVsiRemediation",
+			InputConfig: "sg_testing1_new",
+		},
+		ESrc:        "vsi1-ky",
+		EDst:        "vsi3a-ky",
+		EProtocol:   netp.ProtocolStringTCP,
+		ESrcMinPort: netp.MinPort,
+		ESrcMaxPort: netp.MaxPort,
+		EDstMinPort: 443,
+		EDstMaxPort: 443,
+		Remediate:   true,
+	},
+	// remediation: NACL rule to add for enabling the TCP response
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "NaclResponseRemediation",
+			InputConfig: "acl_testing3",
+		},
+		ESrc:        "vsi3a-ky",
+		EDst:        "vsi1-ky",
+		EProtocol:   netp.ProtocolStringTCP,
+		ESrcMinPort: netp.MinPort,
+		ESrcMaxPort: netp.MaxPort,
+		EDstMinPort: 443,
+		EDstMaxPort: 443,
+		Remediate:   true,
+	},
+	// remediation: no router to the public internet, can not be fixed by rules
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "NoRouterRemediation",
+			InputConfig: "sg_testing1_new",
+		},
+		ESrc:      "vsi3a-ky",
+		EDst:      "8.8.8.8",
+		Remediate: true,
+	},
 	// todo: add a test in which two SGs are connected to a VSI but only one of them enables the connection
 }
 
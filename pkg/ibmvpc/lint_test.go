@@ -35,7 +35,7 @@ var lintTests = []*testfunc.VpcLintTest{
 		},
 		Disable: []string{"nacl-split-subnet", "subnet-cidr-overlap", "nacl-unattached",
 			"sg-unattached", "sg-rule-cidr-out-of-range", "nacl-rule-cidr-out-of-range",
-			"tcp-response-blocked", "sg-rule-implied"},
+			"tcp-response-blocked", "sg-rule-implied", "isolated-endpoints", "udp-icmp-response-blocked"},
 	},
 	{
 		VpcTestCommon: testfunc.VpcTestCommon{
@@ -80,6 +80,92 @@ var lintTests = []*testfunc.VpcLintTest{
 		},
 		Enable: []string{"sg-split-subnet"},
 	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "acl3_lint_config",
+			InputConfig: "acl_testing3_with_redundant_rules",
+		},
+		LintConfig: "lint_config_acl_testing3_with_redundant_rules.yaml",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "acl3_lint_config_json",
+			InputConfig: "acl_testing3_with_redundant_rules",
+		},
+		LintConfig: "lint_config_acl_testing3_with_redundant_rules.yaml",
+		JSONOutput: true,
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "load_balancer_sensitive_ports",
+			InputConfig: "load_balancer",
+		},
+		LintConfig: "lint_config_sensitive_ports.yaml",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "lb_bad_practice",
+			InputConfig: "lb_bad_practice",
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "routing_bad_practice",
+			InputConfig: "routing_bad_practice",
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "routing_bad_practice_json",
+			InputConfig: "routing_bad_practice",
+		},
+		JSONOutput: true,
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "routing_next_hop_allows_forwarded",
+			InputConfig: "routing_next_hop_allows_forwarded",
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "tgw_prefix_filters_bad_practice",
+			InputConfig: "tgw_prefix_filters_bad_practice",
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "isolated_endpoints",
+			InputConfig: "isolated_endpoints",
+		},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "sg_overly_permissive",
+			InputConfig: "experiments_env",
+		},
+		LintConfig:    "lint_config_sg_overly_permissive.yaml",
+		PrintAllLints: true,
+		Enable:        []string{"sg-rule-overly-permissive"},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "sg_overly_permissive_json",
+			InputConfig: "experiments_env",
+		},
+		LintConfig: "lint_config_sg_overly_permissive.yaml",
+		JSONOutput: true,
+		Enable:     []string{"sg-rule-overly-permissive"},
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "rules_blocked_by_other_layer_json",
+			InputConfig: "sg_testing1_new_respond_partly",
+		},
+		Enable:     []string{"nacl-rule-blocked-by-sg", "sg-rule-denied-by-nacl"},
+		Disable:    []string{"tcp-response-blocked"},
+		JSONOutput: true,
+	},
 }
 
 func TestLintWithComparsion(t *testing.T) {
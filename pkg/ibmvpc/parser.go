@@ -103,16 +103,9 @@ func mergeResourcesContainers(rc1, rc2 *IBMresourcesContainer) (*IBMresourcesCon
 // vpcID, resourceGroup and regions are used to filter the vpc configs
 func (rc *IBMresourcesContainer) VpcConfigsFromFiles(fileNames []string, resourceGroup string, vpcIDs, regions []string) (
 	*vpcmodel.MultipleVPCConfigs, error) {
-	for _, file := range fileNames {
-		mergedRC := NewIBMresourcesContainer()
-		err1 := mergedRC.ParseResourcesFromFile(file)
-		if err1 != nil {
-			return nil, fmt.Errorf("error parsing input vpc resources file: %w", err1)
-		}
-		rc, err1 = mergeResourcesContainers(mergedRC, rc)
-		if err1 != nil {
-			return nil, err1
-		}
+	rc, err1 := rc.mergeResourcesFromFiles(fileNames)
+	if err1 != nil {
+		return nil, err1
 	}
 	vpcConfigs, err2 := rc.VPCConfigsFromResources(resourceGroup, vpcIDs, regions)
 	if err2 != nil {
@@ -121,13 +114,32 @@ func (rc *IBMresourcesContainer) VpcConfigsFromFiles(fileNames []string, resourc
 	return vpcConfigs, nil
 }
 
+// mergeResourcesFromFiles returns the resources of rc merged with the resources parsed from the input files
+func (rc *IBMresourcesContainer) mergeResourcesFromFiles(fileNames []string) (*IBMresourcesContainer, error) {
+	for _, file := range fileNames {
+		mergedRC := NewIBMresourcesContainer()
+		err := mergedRC.ParseResourcesFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing input vpc resources file: %w", err)
+		}
+		rc, err = mergeResourcesContainers(mergedRC, rc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
 // parseResourcesFromFile returns IBMresourcesContainer object, containing the configured resources structs
-// from the input JSON file
+// from the input JSON file: either a resources file of the collector, or the output of `terraform show -json`
 func (rc *IBMresourcesContainer) ParseResourcesFromFile(fileName string) error {
 	inputConfigContent, err := os.ReadFile(fileName)
 	if err != nil {
 		return err
 	}
+	if commonvpc.IsTerraformJSON(inputConfigContent) {
+		return rc.parseTerraformResources(inputConfigContent)
+	}
 	err = json.Unmarshal(inputConfigContent, &rc)
 	if err != nil {
 		return err
@@ -414,6 +426,7 @@ func (rc *IBMresourcesContainer) getInstancesConfig(
 		if err != nil {
 			return err
 		}
+		vsiNode.ResourceTags = instance.Tags
 		vpcConfig := res.Config(vpcUID)
 		vpcConfig.NodeSets = append(vpcConfig.NodeSets, vsiNode)
 		vpcConfig.UIDToResource[vsiNode.ResourceUID] = vsiNode
@@ -463,6 +476,8 @@ func createNetworkInterface(name, id, address string, instance *datamodel.Instan
 	if err != nil {
 		return err
 	}
+	// the network interfaces are labeled by the tags of their instance
+	intfNode.ResourceTags = instance.Tags
 	vpcConfig.Nodes = append(vpcConfig.Nodes, intfNode)
 	vpcConfig.UIDToResource[intfNode.ResourceUID] = intfNode
 	vsiNode.VPCnodes = append(vsiNode.VPCnodes, intfNode)
@@ -493,6 +508,7 @@ func (rc *IBMresourcesContainer) getSubnetsConfig(
 		if err != nil {
 			return nil, err
 		}
+		subnetNode.ResourceTags = subnet.Tags
 		if subnet.PublicGateway != nil {
 			if _, ok := pgwToSubnet[*subnet.PublicGateway.Name]; !ok {
 				pgwToSubnet[*subnet.PublicGateway.Name] = []*commonvpc.Subnet{}
@@ -888,6 +904,13 @@ func (tgw *TransitGateway) addVPC(vpc *commonvpc.VPC, tgwConn *datamodel.Transit
 			}
 			tgw.vpcsAPToPrefixRules[vpcUID][ipB] = rulesInTable
 		}
+		transitConn, errConn := tgw.newTransitConnection(tgwConn, vpc, vpcAPToPrefixRules)
+		if errConn != nil {
+			logging.Warnf("ignoring transit connection for linting, vpcID: %s, tgwID: %s, err is: %s\n", vpcUID, tgw.UID(),
+				errConn.Error())
+		} else {
+			tgw.transitConns = append(tgw.transitConns, transitConn)
+		}
 	}
 }
 
@@ -1334,7 +1357,7 @@ func (rc *IBMresourcesContainer) getLoadBalancersConfig(
 			},
 		}
 
-		loadBalancer.listeners = getLoadBalancerServer(vpcConfig, loadBalancerObj)
+		loadBalancer.listeners, loadBalancer.pools = getLoadBalancerServer(vpcConfig, loadBalancerObj)
 		privateIPs, err := getLoadBalancerIPs(vpcConfig, loadBalancerObj, loadBalancer, vpc, subnetsIPBlocks)
 		if err != nil {
 			return err
@@ -1362,24 +1385,26 @@ func getLoadBalancerVpcUID(rc *IBMresourcesContainer, loadBalancerObj *datamodel
 }
 
 // getLoadBalancerServer() parse and return all the servers.
-// currently as a list of listeners, TBD
+// currently as a list of listeners, TBD; also returns the pools with the ports of their members
 func getLoadBalancerServer(vpcConfig *vpcmodel.VPCConfig,
-	loadBalancerObj *datamodel.LoadBalancer) []LoadBalancerListener {
+	loadBalancerObj *datamodel.LoadBalancer) ([]LoadBalancerListener, []*vpcmodel.LoadBalancerPool) {
 	pools := map[string]LoadBalancerPool{}
+	poolsInfo := []*vpcmodel.LoadBalancerPool{}
 	listeners := []LoadBalancerListener{}
 	for poolIndex := range loadBalancerObj.Pools {
 		poolObj := loadBalancerObj.Pools[poolIndex]
 		pool := LoadBalancerPool{}
-		// todo: handle pools currently we just collect them
-		// pool.name = *poolObj.Name
-		// pool.protocol = *poolObj.Protocol
+		poolInfo := &vpcmodel.LoadBalancerPool{Name: *poolObj.Name, Protocol: *poolObj.Protocol}
 		for _, memberObj := range poolObj.Members {
-			// todo handle the ports:
-			// member.port = *memberObj.Port
 			address := *memberObj.Target.(*vpc1.LoadBalancerPoolMemberTarget).Address
-			pool = append(pool, getCertainNodes(vpcConfig.Nodes, func(n vpcmodel.Node) bool { return n.CidrOrAddress() == address })...)
+			memberNodes := getCertainNodes(vpcConfig.Nodes, func(n vpcmodel.Node) bool { return n.CidrOrAddress() == address })
+			pool = append(pool, memberNodes...)
+			for _, memberNode := range memberNodes {
+				poolInfo.Members = append(poolInfo.Members, &vpcmodel.LoadBalancerPoolMember{Node: memberNode, Port: *memberObj.Port})
+			}
 		}
 		pools[*poolObj.ID] = pool
+		poolsInfo = append(poolsInfo, poolInfo)
 	}
 	for listenerIndex := range loadBalancerObj.Listeners {
 		listenerObj := loadBalancerObj.Listeners[listenerIndex]
@@ -1407,7 +1432,7 @@ func getLoadBalancerServer(vpcConfig *vpcmodel.VPCConfig,
 		}
 		listeners = append(listeners, listener)
 	}
-	return listeners
+	return listeners, poolsInfo
 }
 
 // ///////////////////////////////////////////////////////////
@@ -331,6 +331,25 @@ func (r *route) string() string {
 	return ""
 }
 
+func (a routingAction) modelAction() vpcmodel.RouteAction {
+	switch a {
+	case deliver:
+		return vpcmodel.RouteDeliver
+	case delegate:
+		return vpcmodel.RouteDelegate
+	case drop:
+		return vpcmodel.RouteDrop
+	case delegateVPC:
+		return vpcmodel.RouteDelegateVPC
+	}
+	return ""
+}
+
+func (r *route) modelRoute() *vpcmodel.Route {
+	return &vpcmodel.Route{Name: r.name, Action: r.action.modelAction(), Zone: r.zone, Priority: r.priority,
+		Destination: r.destIPBlock, DestPrefix: r.destPrefixLen, NextHop: r.nextHopIPBlock, Description: r.string()}
+}
+
 // routingResult captures routing results per zone
 type routingResult struct {
 
@@ -342,7 +361,7 @@ type routingResult struct {
 	delegatedDestinations *netset.IPBlock // union of all ip-ranges for delegated destinations
 }
 
-// routingTable implements VPCResourceIntf (TODO: should implement RoutingResource interface or another separate interface?)
+// routingTable implements VPCResourceIntf; its ingress and egress variants implement vpcmodel.RoutingTable
 type routingTable struct {
 	vpcmodel.VPCResource
 
@@ -363,6 +382,14 @@ func (rt *routingTable) ShowOnSubnetMode() bool {
 	return false
 }
 
+func (rt *routingTable) Routes() []*vpcmodel.Route {
+	res := make([]*vpcmodel.Route, len(rt.routesList))
+	for i, r := range rt.routesList {
+		res[i] = r.modelRoute()
+	}
+	return res
+}
+
 func computeRoutesPerZone(routes []*route) map[string][]*route {
 	res := map[string][]*route{}
 	for _, r := range routes {
@@ -597,6 +624,11 @@ type ingressRoutingTable struct {
 // prefix `Y`, under `vpc-A` prefixes, even though `A` does not have this cidr. this way, `C` can route to `A`
 // if the dest is in `B`, and from A can route to `B` through the other TGW, and based on the ingress routing table.
 
+// AttachedSubnets returns nil, since an ingress routing table is not attached to subnets
+func (irt *ingressRoutingTable) AttachedSubnets() []vpcmodel.Subnet {
+	return nil
+}
+
 func (irt *ingressRoutingTable) advertiseRoutes(vpcConfig *vpcmodel.VPCConfig) {
 	if irt.source != tgwSource {
 		return // currently supporting only tgw source for routes advertisement
@@ -694,3 +726,11 @@ func newEgressRoutingTableFromRoutes(routes []*route,
 		vpc:          vpcConfig.VPC.(*commonvpc.VPC),
 	}
 }
+
+func (ert *egressRoutingTable) AttachedSubnets() []vpcmodel.Subnet {
+	res := make([]vpcmodel.Subnet, len(ert.subnets))
+	for i, subnet := range ert.subnets {
+		res[i] = subnet
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ibmvpc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc/testfunc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+var segmentationTests = []*testfunc.VpcSegmentationTest{
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "Zones",
+			InputConfig: "sg_testing1_new",
+			Format:      vpcmodel.Text,
+		},
+		ZoningFile: "zoning_sg_testing1_new.json",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "Zones",
+			InputConfig: "sg_testing1_new",
+			Format:      vpcmodel.MD,
+		},
+		ZoningFile: "zoning_sg_testing1_new.json",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "Zones",
+			InputConfig: "sg_testing1_new",
+			Format:      vpcmodel.JSON,
+		},
+		ZoningFile: "zoning_sg_testing1_new.json",
+	},
+	{
+		VpcTestCommon: testfunc.VpcTestCommon{
+			Name:        "MultiVPC",
+			InputConfig: "tgw_larger_example",
+			Format:      vpcmodel.MD,
+		},
+		ZoningFile: "zoning_tgw_larger_example.json",
+	},
+}
+
+func TestSegmentationWithComparison(t *testing.T) {
+	// segmentationTests is the list of tests to run
+	for testIdx := range segmentationTests {
+		tt := segmentationTests[testIdx]
+		tt.TestSingleSegmentation(t, testfunc.OutputComparison, NewIBMresourcesContainer(), tt.Name)
+	}
+	fmt.Println("done")
+}
+
+// uncomment the function below for generating the expected output files instead of comparing
+
+/*
+func TestSegmentationWithGeneration(t *testing.T) {
+	// segmentationTests is the list of tests to run
+	for testIdx := range segmentationTests {
+		tt := segmentationTests[testIdx]
+		tt.TestSingleSegmentation(t, testfunc.OutputGeneration, NewIBMresourcesContainer(), tt.Name)
+	}
+	fmt.Println("done")
+}*/
@@ -0,0 +1,652 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ibmvpc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	vpc1 "github.com/IBM/vpc-go-sdk/vpcv1"
+
+	"github.com/np-guard/cloud-resource-collector/pkg/common"
+	"github.com/np-guard/cloud-resource-collector/pkg/ibm/datamodel"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/logging"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+// functionality for reading the resources from the output of `terraform show -json` of a state or a plan,
+// by mapping the ibm_is_* resources onto the resources datamodel
+
+const (
+	tfVPC                  = "ibm_is_vpc"
+	tfAddressPrefix        = "ibm_is_vpc_address_prefix"
+	tfSubnet               = "ibm_is_subnet"
+	tfSubnetPGWAttachment  = "ibm_is_subnet_public_gateway_attachment"
+	tfSubnetNACLAttachment = "ibm_is_subnet_network_acl_attachment"
+	tfPublicGateway        = "ibm_is_public_gateway"
+	tfFloatingIP           = "ibm_is_floating_ip"
+	tfNetworkACL           = "ibm_is_network_acl"
+	tfNetworkACLRule       = "ibm_is_network_acl_rule"
+	tfSecurityGroup        = "ibm_is_security_group"
+	tfSecurityGroupRule    = "ibm_is_security_group_rule"
+	tfSecurityGroupTarget  = "ibm_is_security_group_target"
+	tfInstance             = "ibm_is_instance"
+	tfVirtualNI            = "ibm_is_virtual_network_interface"
+
+	tfCRN                  = "crn"
+	tfName                 = "name"
+	tfZone                 = "zone"
+	tfVPCAttr              = "vpc"
+	tfSubnetAttr           = "subnet"
+	tfPublicGatewayAttr    = "public_gateway"
+	tfNetworkACLAttr       = "network_acl"
+	tfSecurityGroupsAttr   = "security_groups"
+	tfRuleID               = "rule_id"
+	tfDefaultNACL          = "default_network_acl"
+	tfDefaultSG            = "default_security_group"
+	tfNameSuffix           = "_name"
+	tfPrimaryNI            = "primary_network_interface"
+	tfNIs                  = "network_interfaces"
+	tfPrimaryAttachment    = "primary_network_attachment"
+	tfAttachments          = "network_attachments"
+	tfAttachmentVNI        = "virtual_network_interface.0."
+	crnRegionIndex         = 5
+	defaultNACLNameSuffix  = "-default-acl"
+	defaultSGNameSuffix    = "-default-sg"
+	defaultRulesNamePrefix = "allow-"
+)
+
+// resource types which are relevant to the connectivity, but are not supported yet as terraform input
+var tfUnsupportedTypePrefixes = []string{"ibm_is_lb", "ibm_is_virtual_endpoint_gateway", "ibm_is_vpc_routing_table",
+	"ibm_is_instance_network", "ibm_tg_", "ibm_container_vpc"}
+
+// terraformParser maps terraform resources onto the resources of an IBMresourcesContainer
+type terraformParser struct {
+	rc           *IBMresourcesContainer
+	trs          *commonvpc.TerraformResources
+	vpcResources map[string]*commonvpc.TerraformResource // map from vpc id to its terraform resource
+	vpcs         map[string]*datamodel.VPC               // the following maps are from the resource id to the resource
+	subnets      map[string]*datamodel.Subnet
+	pgws         map[string]*datamodel.PublicGateway
+	nacls        map[string]*datamodel.NetworkACL
+	sgs          map[string]*datamodel.SecurityGroup
+	defaultNACLs map[string]*datamodel.VPC // map from the id of the default nacl of a vpc to the vpc
+	defaultSGs   map[string]*datamodel.VPC // map from the id of the default sg of a vpc to the vpc
+	subnetNACL   map[string]string         // map from subnet id to the id of its nacl
+	// network interfaces (and virtual network interfaces) info
+	interfaceTypes   map[string]string   // map from interface id to its resource type
+	interfaceZones   map[string]string   // map from interface id to its zone
+	takenAddresses   map[string][]string // map from subnet id to the addresses of its interfaces
+	pendingAddresses []*pendingAddress
+}
+
+// pendingAddress is an address of an interface, which is known only after apply
+type pendingAddress struct {
+	ip      *vpc1.ReservedIPReference
+	subnet  *datamodel.Subnet
+	address string // the address of the terraform resource of the interface
+}
+
+// parseTerraformResources adds to rc the resources read from the output of `terraform show -json`
+func (rc *IBMresourcesContainer) parseTerraformResources(content []byte) error {
+	trs, err := commonvpc.NewTerraformResources(content)
+	if err != nil {
+		return err
+	}
+	if provider := trs.Provider(); provider != common.IBM {
+		return fmt.Errorf("terraform resources of provider %s are not supported for provider %s", provider, common.IBM)
+	}
+	// as with a collector output, the resources read replace any resources previously read into rc
+	rc.ResourcesContainerModel = datamodel.ResourcesContainerModel{}
+	p := &terraformParser{rc: rc, trs: trs, vpcResources: map[string]*commonvpc.TerraformResource{},
+		vpcs: map[string]*datamodel.VPC{}, subnets: map[string]*datamodel.Subnet{},
+		pgws: map[string]*datamodel.PublicGateway{}, nacls: map[string]*datamodel.NetworkACL{},
+		sgs: map[string]*datamodel.SecurityGroup{}, defaultNACLs: map[string]*datamodel.VPC{},
+		defaultSGs: map[string]*datamodel.VPC{}, subnetNACL: map[string]string{}, interfaceTypes: map[string]string{},
+		interfaceZones: map[string]string{}, takenAddresses: map[string][]string{}}
+	return p.parse()
+}
+
+func (p *terraformParser) parse() error {
+	p.warnUnsupported()
+	for _, parseFunc := range []func() error{p.parseVPCs, p.parsePublicGateways, p.parseSecurityGroups,
+		p.parseNetworkACLs, p.parseNetworkACLRules, p.parseSubnets, p.parseSecurityGroupRules, p.parseInstances,
+		p.parseSecurityGroupTargets, p.allocatePendingAddresses, p.parseFloatingIPs} {
+		if err := parseFunc(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *terraformParser) warnUnsupported() {
+	for _, r := range p.trs.Resources {
+		if slices.ContainsFunc(tfUnsupportedTypePrefixes, func(prefix string) bool { return strings.HasPrefix(r.Type, prefix) }) {
+			logging.Warnf("ignoring %s - resource type %s is not supported yet for terraform input\n", r.Address, r.Type)
+		}
+	}
+}
+
+// identity returns the id, crn and name of a terraform resource; the crn defaults to the id, and the name
+// defaults to the terraform resource name
+func identity(r *commonvpc.TerraformResource) (id, crn, name *string) {
+	idStr := r.ID()
+	crnStr, nameStr := r.StrOr(tfCRN, idStr), r.StrOr(tfName, r.Name)
+	return &idStr, &crnStr, &nameStr
+}
+
+func zoneReference(zone string) *vpc1.ZoneReference {
+	return &vpc1.ZoneReference{Name: &zone}
+}
+
+func vpcReference(vpc *datamodel.VPC) *vpc1.VPCReference {
+	return &vpc1.VPCReference{CRN: vpc.CRN, ID: vpc.ID, Name: vpc.Name}
+}
+
+// regionFromCRN returns the region of a crn of the form crn:v1:bluemix:public:is:<region>:...
+func regionFromCRN(crn string) string {
+	if parts := strings.Split(crn, ":"); len(parts) > crnRegionIndex {
+		return parts[crnRegionIndex]
+	}
+	return ""
+}
+
+// regionFromZone returns the region of a zone of the form <region>-<zone number>
+func regionFromZone(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+func (p *terraformParser) vpcOf(r *commonvpc.TerraformResource) (*datamodel.VPC, error) {
+	vpcID := r.RefID(tfVPCAttr)
+	if vpc, ok := p.vpcs[vpcID]; ok {
+		return vpc, nil
+	}
+	return nil, fmt.Errorf("%s: could not find vpc %s", r.Address, vpcID)
+}
+
+func (p *terraformParser) parseVPCs() error {
+	for _, r := range p.trs.OfType(tfVPC) {
+		id, crn, name := identity(r)
+		resourceGroup := r.Str("resource_group")
+		vpc := &datamodel.VPC{VPC: vpc1.VPC{ID: id, CRN: crn, Name: name,
+			ResourceGroup: &vpc1.ResourceGroupReference{ID: &resourceGroup, Name: &resourceGroup}},
+			Region: regionFromCRN(*crn), AddressPrefixes: []vpc1.AddressPrefix{}}
+		p.rc.VpcList = append(p.rc.VpcList, vpc)
+		p.vpcs[*id], p.vpcResources[*id] = vpc, r
+		// the default nacl and sg of a vpc are usually not managed by terraform
+		p.defaultNACLs[r.StrOr(tfDefaultNACL, r.Address+"."+tfDefaultNACL)] = vpc
+		p.defaultSGs[r.StrOr(tfDefaultSG, r.Address+"."+tfDefaultSG)] = vpc
+	}
+	for _, r := range p.trs.OfType(tfAddressPrefix) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		cidr := r.Str("cidr")
+		vpc.AddressPrefixes = append(vpc.AddressPrefixes, vpc1.AddressPrefix{Zone: zoneReference(r.Str(tfZone)), CIDR: &cidr})
+	}
+	return nil
+}
+
+func (p *terraformParser) parsePublicGateways() error {
+	for _, r := range p.trs.OfType(tfPublicGateway) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		id, crn, name := identity(r)
+		pgw := &datamodel.PublicGateway{PublicGateway: vpc1.PublicGateway{ID: id, CRN: crn, Name: name,
+			Zone: zoneReference(r.Str(tfZone)), VPC: vpcReference(vpc)}}
+		p.rc.PublicGWList = append(p.rc.PublicGWList, pgw)
+		p.pgws[*id] = pgw
+	}
+	return nil
+}
+
+func (p *terraformParser) parseSecurityGroups() error {
+	for _, r := range p.trs.OfType(tfSecurityGroup) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		id, crn, name := identity(r)
+		p.addSecurityGroup(&datamodel.SecurityGroup{SecurityGroup: vpc1.SecurityGroup{ID: id, CRN: crn, Name: name,
+			VPC: vpcReference(vpc), Rules: []vpc1.SecurityGroupRuleIntf{}, Targets: []vpc1.SecurityGroupTargetReferenceIntf{}}})
+	}
+	return nil
+}
+
+func (p *terraformParser) addSecurityGroup(sg *datamodel.SecurityGroup) {
+	p.rc.SecurityGroupList = append(p.rc.SecurityGroupList, sg)
+	p.sgs[*sg.ID] = sg
+}
+
+// securityGroup returns the sg with the given id; the default sg of a vpc is added on its first use,
+// with the rules of a default sg: inbound from its members, and outbound to anywhere
+func (p *terraformParser) securityGroup(id string) *datamodel.SecurityGroup {
+	if sg, ok := p.sgs[id]; ok {
+		return sg
+	}
+	vpc, ok := p.defaultSGs[id]
+	if !ok {
+		return nil
+	}
+	name := p.vpcResources[*vpc.ID].StrOr(tfDefaultSG+tfNameSuffix, *vpc.Name+defaultSGNameSuffix)
+	logging.Warnf("security group %s is not managed by terraform, assuming it has the rules of a default security group\n", name)
+	inboundID, outboundID := id+"-"+commonvpc.Inbound, id+"-"+commonvpc.Outbound
+	sg := &datamodel.SecurityGroup{SecurityGroup: vpc1.SecurityGroup{ID: &id, CRN: &id, Name: &name, VPC: vpcReference(vpc),
+		Rules: []vpc1.SecurityGroupRuleIntf{
+			newSGRule(&vpcmodel.PatchRule{Direction: commonvpc.Inbound, Protocol: protocolAll, Remote: name}, inboundID),
+			newSGRule(&vpcmodel.PatchRule{Direction: commonvpc.Outbound, Protocol: protocolAll}, outboundID)},
+		Targets: []vpc1.SecurityGroupTargetReferenceIntf{}}}
+	p.addSecurityGroup(sg)
+	return sg
+}
+
+// setRuleProtocol sets the protocol, ports and icmp type and code of rule from the sg or nacl rule of r, whose
+// attributes are prefixed by prefix. Both the tcp, udp and icmp nested blocks, and the protocol attribute of
+// newer provider versions, are supported
+func setRuleProtocol(r *commonvpc.TerraformResource, prefix string, rule *vpcmodel.PatchRule) {
+	for _, protocol := range []string{protocolTCP, protocolUDP, protocolICMP} {
+		if r.Len(prefix+protocol) > 0 {
+			rule.Protocol = protocol
+			prefix = prefix + protocol + ".0."
+			break
+		}
+	}
+	if rule.Protocol == "" {
+		rule.Protocol = r.StrOr(prefix+"protocol", protocolAll)
+	}
+	switch rule.Protocol {
+	case protocolICMP:
+		rule.ICMPType, rule.ICMPCode = r.Int(prefix+"type"), r.Int(prefix+"code")
+	case protocolTCP, protocolUDP:
+		rule.DstPortMin, rule.DstPortMax = r.Int(prefix+"port_min"), r.Int(prefix+"port_max")
+		rule.SrcPortMin, rule.SrcPortMax = r.Int(prefix+"source_port_min"), r.Int(prefix+"source_port_max")
+	}
+}
+
+func naclRuleFromTerraform(r *commonvpc.TerraformResource, prefix string) (*vpcmodel.PatchRule, error) {
+	rule := &vpcmodel.PatchRule{Name: r.Str(prefix + tfName), Action: r.Str(prefix + "action"),
+		Direction: r.Str(prefix + "direction"), Source: r.Str(prefix + "source"), Destination: r.Str(prefix + "destination")}
+	setRuleProtocol(r, prefix, rule)
+	if err := validatePatchRule(rule); err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Address, err)
+	}
+	return rule, nil
+}
+
+func (p *terraformParser) parseNetworkACLs() error {
+	for _, r := range p.trs.OfType(tfNetworkACL) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		id, crn, name := identity(r)
+		nacl := &datamodel.NetworkACL{NetworkACL: vpc1.NetworkACL{ID: id, CRN: crn, Name: name, VPC: vpcReference(vpc),
+			Rules: []vpc1.NetworkACLRuleItemIntf{}, Subnets: []vpc1.SubnetReference{}}}
+		for i := 0; i < r.Len("rules"); i++ {
+			rulePath := fmt.Sprintf("rules.%d", i)
+			rule, err := naclRuleFromTerraform(r, rulePath+".")
+			if err != nil {
+				return err
+			}
+			nacl.Rules = append(nacl.Rules, newNACLRule(rule, r.NestedID(rulePath)))
+		}
+		p.rc.NetworkACLList = append(p.rc.NetworkACLList, nacl)
+		p.nacls[*id] = nacl
+	}
+	return nil
+}
+
+func naclRuleID(rule vpc1.NetworkACLRuleItemIntf) *string {
+	switch ruleObj := rule.(type) {
+	case *vpc1.NetworkACLRuleItemNetworkACLRuleProtocolAll:
+		return ruleObj.ID
+	case *vpc1.NetworkACLRuleItemNetworkACLRuleProtocolTcpudp:
+		return ruleObj.ID
+	case *vpc1.NetworkACLRuleItemNetworkACLRuleProtocolIcmp:
+		return ruleObj.ID
+	}
+	return nil
+}
+
+// parseNetworkACLRules adds the rules of ibm_is_network_acl_rule resources to their nacls, each before the rule
+// referenced by its "before" attribute, or as the last rule. Rules that are already listed in the rules of
+// their nacl (as in a state) are skipped
+func (p *terraformParser) parseNetworkACLRules() error {
+	pending := p.trs.OfType(tfNetworkACLRule)
+	// a rule may be placed before another rule resource, thus the rules are added in iterations
+	for len(pending) > 0 {
+		var deferred []*commonvpc.TerraformResource
+		for _, r := range pending {
+			added, err := p.addNetworkACLRule(r, false)
+			if err != nil {
+				return err
+			}
+			if !added {
+				deferred = append(deferred, r)
+			}
+		}
+		if len(deferred) == len(pending) {
+			for _, r := range deferred {
+				logging.Warnf("%s: could not find the rule it is placed before, adding it as the last rule\n", r.Address)
+				if _, err := p.addNetworkACLRule(r, true); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		pending = deferred
+	}
+	return nil
+}
+
+func (p *terraformParser) addNetworkACLRule(r *commonvpc.TerraformResource, force bool) (bool, error) {
+	nacl, ok := p.nacls[r.RefID(tfNetworkACLAttr)]
+	if !ok {
+		return false, fmt.Errorf("%s: could not find network acl %s", r.Address, r.RefID(tfNetworkACLAttr))
+	}
+	rule, err := naclRuleFromTerraform(r, "")
+	if err != nil {
+		return false, err
+	}
+	if _, err := naclRuleIndex(nacl, rule.Name); err == nil {
+		return true, nil
+	}
+	index := len(nacl.Rules)
+	if before := r.RefID("before"); before != "" && !force {
+		index = slices.IndexFunc(nacl.Rules, func(rule vpc1.NetworkACLRuleItemIntf) bool {
+			id, name := naclRuleID(rule), naclRuleName(rule)
+			return (id != nil && *id == before) || (name != nil && *name == before)
+		})
+		if index < 0 {
+			return false, nil
+		}
+	}
+	nacl.Rules = slices.Insert(nacl.Rules, index, newNACLRule(rule, r.StrOr(tfRuleID, r.Address+"."+tfRuleID)))
+	return true, nil
+}
+
+// networkACL returns the nacl with the given id; a nacl not managed by terraform (such as the default nacl of a vpc)
+// is added on its first use, with the rules of a default nacl: allowing all traffic
+func (p *terraformParser) networkACL(id string, vpc *datamodel.VPC) *datamodel.NetworkACL {
+	if nacl, ok := p.nacls[id]; ok {
+		return nacl
+	}
+	name := id
+	if defaultNACLVPC, ok := p.defaultNACLs[id]; ok {
+		name = p.vpcResources[*defaultNACLVPC.ID].StrOr(tfDefaultNACL+tfNameSuffix, *vpc.Name+defaultNACLNameSuffix)
+	}
+	logging.Warnf("network acl %s is not managed by terraform, assuming it allows all traffic\n", name)
+	nacl := &datamodel.NetworkACL{NetworkACL: vpc1.NetworkACL{ID: &id, CRN: &id, Name: &name, VPC: vpcReference(vpc),
+		Subnets: []vpc1.SubnetReference{}}}
+	for _, direction := range []string{commonvpc.Inbound, commonvpc.Outbound} {
+		ruleName := defaultRulesNamePrefix + direction
+		nacl.Rules = append(nacl.Rules, newNACLRule(&vpcmodel.PatchRule{Name: ruleName, Direction: direction,
+			Protocol: protocolAll}, id+"-"+ruleName))
+	}
+	p.rc.NetworkACLList = append(p.rc.NetworkACLList, nacl)
+	p.nacls[id] = nacl
+	return nacl
+}
+
+func (p *terraformParser) parseSubnets() error {
+	for _, r := range p.trs.OfType(tfSubnet) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		id, crn, name := identity(r)
+		cidr, zone := r.Str("ipv4_cidr_block"), r.Str(tfZone)
+		if cidr == "" {
+			return fmt.Errorf("%s: unknown ipv4_cidr_block, subnets should have an explicit cidr", r.Address)
+		}
+		if vpc.Region == "" {
+			vpc.Region = regionFromZone(zone)
+		}
+		subnet := &datamodel.Subnet{Subnet: vpc1.Subnet{ID: id, CRN: crn, Name: name, Zone: zoneReference(zone),
+			Ipv4CIDRBlock: &cidr, VPC: vpcReference(vpc)}}
+		p.rc.SubnetList = append(p.rc.SubnetList, subnet)
+		p.subnets[*id] = subnet
+		if err := p.setSubnetPGW(r, tfPublicGatewayAttr, subnet); err != nil {
+			return err
+		}
+		p.subnetNACL[*id] = r.RefID(tfNetworkACLAttr)
+		if p.subnetNACL[*id] == "" {
+			p.subnetNACL[*id] = p.vpcResources[*vpc.ID].StrOr(tfDefaultNACL, p.vpcResources[*vpc.ID].Address+"."+tfDefaultNACL)
+		}
+	}
+	for _, r := range p.trs.OfType(tfSubnetPGWAttachment) {
+		subnet, err := p.subnetOf(r, tfSubnetAttr)
+		if err != nil {
+			return err
+		}
+		if err := p.setSubnetPGW(r, tfPublicGatewayAttr, subnet); err != nil {
+			return err
+		}
+	}
+	for _, r := range p.trs.OfType(tfSubnetNACLAttachment) {
+		subnet, err := p.subnetOf(r, tfSubnetAttr)
+		if err != nil {
+			return err
+		}
+		p.subnetNACL[*subnet.ID] = r.RefID(tfNetworkACLAttr)
+	}
+	for _, subnet := range p.rc.SubnetList {
+		nacl := p.networkACL(p.subnetNACL[*subnet.ID], p.vpcs[*subnet.VPC.ID])
+		nacl.Subnets = append(nacl.Subnets, vpc1.SubnetReference{CRN: subnet.CRN, ID: subnet.ID, Name: subnet.Name})
+		subnet.NetworkACL = &vpc1.NetworkACLReference{CRN: nacl.CRN, ID: nacl.ID, Name: nacl.Name}
+	}
+	return nil
+}
+
+func (p *terraformParser) subnetOf(r *commonvpc.TerraformResource, path string) (*datamodel.Subnet, error) {
+	subnetID := r.RefID(path)
+	if subnet, ok := p.subnets[subnetID]; ok {
+		return subnet, nil
+	}
+	return nil, fmt.Errorf("%s: could not find subnet %s", r.Address, subnetID)
+}
+
+func (p *terraformParser) setSubnetPGW(r *commonvpc.TerraformResource, path string, subnet *datamodel.Subnet) error {
+	pgwID := r.RefID(path)
+	if pgwID == "" {
+		return nil
+	}
+	pgw, ok := p.pgws[pgwID]
+	if !ok {
+		return fmt.Errorf("%s: could not find public gateway %s", r.Address, pgwID)
+	}
+	subnet.PublicGateway = &vpc1.PublicGatewayReference{CRN: pgw.CRN, ID: pgw.ID, Name: pgw.Name}
+	return nil
+}
+
+// sgRuleRemote returns the remote of a sg rule, replacing a sg id by its name
+func (p *terraformParser) sgRuleRemote(remote string) string {
+	if sg := p.securityGroup(remote); sg != nil {
+		return *sg.Name
+	}
+	return remote
+}
+
+func (p *terraformParser) parseSecurityGroupRules() error {
+	for _, r := range p.trs.OfType(tfSecurityGroupRule) {
+		sg := p.securityGroup(r.RefID("group"))
+		if sg == nil {
+			return fmt.Errorf("%s: could not find security group %s", r.Address, r.RefID("group"))
+		}
+		rule := &vpcmodel.PatchRule{Direction: r.Str("direction"), Remote: p.sgRuleRemote(r.RefID("remote")),
+			Local: r.Str("local")}
+		setRuleProtocol(r, "", rule)
+		if err := validatePatchRule(rule); err != nil {
+			return fmt.Errorf("%s: %w", r.Address, err)
+		}
+		sg.Rules = append(sg.Rules, newSGRule(rule, r.StrOr(tfRuleID, r.Address+"."+tfRuleID)))
+	}
+	return nil
+}
+
+// addSGTargets adds the interface of the given id and type as a target of the sgs with the given ids,
+// or of the default sg of the vpc if there are no such
+func (p *terraformParser) addSGTargets(r *commonvpc.TerraformResource, sgIDs []string, targetID, targetType string,
+	vpc *datamodel.VPC) {
+	if len(sgIDs) == 0 {
+		sgIDs = []string{p.vpcResources[*vpc.ID].StrOr(tfDefaultSG, p.vpcResources[*vpc.ID].Address+"."+tfDefaultSG)}
+	}
+	for _, sgID := range sgIDs {
+		sg := p.securityGroup(sgID)
+		if sg == nil {
+			logging.Warnf("%s: ignoring unknown security group %s\n", r.Address, sgID)
+			continue
+		}
+		sg.Targets = append(sg.Targets, &vpc1.SecurityGroupTargetReference{ID: &targetID, ResourceType: &targetType})
+	}
+}
+
+// parseInterface returns the id, name, primary ip and subnet of a network interface or a virtual network interface,
+// whose attributes in r are prefixed by prefix, and adds it as a target of its sgs
+func (p *terraformParser) parseInterface(r *commonvpc.TerraformResource, prefix, interfaceType, zone string,
+	vpc *datamodel.VPC) (id, name *string, primaryIP *vpc1.ReservedIPReference, subnet *datamodel.Subnet, err error) {
+	subnet, err = p.subnetOf(r, prefix+tfSubnetAttr)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	idStr := r.NestedID(strings.TrimSuffix(prefix, "."))
+	nameStr := r.StrOr(prefix+tfName, idStr)
+	address := r.StrOr(prefix+"primary_ip.0.address", r.Str(prefix+"primary_ipv4_address"))
+	primaryIP = &vpc1.ReservedIPReference{Address: &address}
+	if address == "" {
+		p.pendingAddresses = append(p.pendingAddresses, &pendingAddress{ip: primaryIP, subnet: subnet, address: r.Address})
+	} else {
+		p.takenAddresses[*subnet.ID] = append(p.takenAddresses[*subnet.ID], address)
+	}
+	p.interfaceTypes[idStr], p.interfaceZones[idStr] = interfaceType, zone
+	p.addSGTargets(r, r.RefIDs(prefix+tfSecurityGroupsAttr), idStr, interfaceType, vpc)
+	return &idStr, &nameStr, primaryIP, subnet, nil
+}
+
+func (p *terraformParser) parseInstances() error {
+	for _, r := range p.trs.OfType(tfInstance) {
+		vpc, err := p.vpcOf(r)
+		if err != nil {
+			return err
+		}
+		id, crn, name := identity(r)
+		zone := r.Str(tfZone)
+		instance := &datamodel.Instance{Instance: vpc1.Instance{ID: id, CRN: crn, Name: name, Zone: zoneReference(zone),
+			VPC: vpcReference(vpc), NetworkAttachments: []vpc1.InstanceNetworkAttachmentReference{}},
+			NetworkInterfaces: []vpc1.NetworkInterface{}}
+		for _, prefix := range blocksPrefixes(r, tfPrimaryNI, tfNIs) {
+			niID, niName, primaryIP, subnet, err := p.parseInterface(r, prefix, commonvpc.NetworkInterfaceResourceType, zone, vpc)
+			if err != nil {
+				return err
+			}
+			instance.NetworkInterfaces = append(instance.NetworkInterfaces, vpc1.NetworkInterface{ID: niID, Name: niName,
+				PrimaryIP: primaryIP, Subnet: &vpc1.SubnetReference{CRN: subnet.CRN, ID: subnet.ID, Name: subnet.Name}})
+		}
+		for _, prefix := range blocksPrefixes(r, tfPrimaryAttachment, tfAttachments) {
+			if err := p.parseNetworkAttachment(r, prefix, instance, vpc); err != nil {
+				return err
+			}
+		}
+		p.rc.InstanceList = append(p.rc.InstanceList, instance)
+	}
+	return nil
+}
+
+// blocksPrefixes returns the attribute prefixes of the nested blocks of the primary block and of the other blocks
+// (e.g., of primary_network_interface and of network_interfaces)
+func blocksPrefixes(r *commonvpc.TerraformResource, primaryBlock, otherBlocks string) []string {
+	res := []string{}
+	if r.Len(primaryBlock) > 0 {
+		res = append(res, primaryBlock+".0.")
+	}
+	for i := 0; i < r.Len(otherBlocks); i++ {
+		res = append(res, fmt.Sprintf("%s.%d.", otherBlocks, i))
+	}
+	return res
+}
+
+// parseNetworkAttachment adds a network attachment of an instance, with its virtual network interface which is
+// either defined inline or by an ibm_is_virtual_network_interface resource
+func (p *terraformParser) parseNetworkAttachment(r *commonvpc.TerraformResource, prefix string,
+	instance *datamodel.Instance, vpc *datamodel.VPC) error {
+	attachmentID := r.NestedID(strings.TrimSuffix(prefix, "."))
+	vniResource, vniPrefix := r, prefix+tfAttachmentVNI
+	vniID := r.RefID(vniPrefix + "id")
+	for _, vni := range p.trs.OfType(tfVirtualNI) {
+		if vni.ID() == vniID {
+			vniResource, vniPrefix = vni, ""
+		}
+	}
+	id, name, primaryIP, subnet, err := p.parseInterface(vniResource, vniPrefix,
+		commonvpc.VirtualNetworkInterfaceResourceType, *instance.Zone.Name, vpc)
+	if err != nil {
+		return err
+	}
+	instance.NetworkAttachments = append(instance.NetworkAttachments,
+		vpc1.InstanceNetworkAttachmentReference{ID: &attachmentID, Name: name})
+	p.rc.VirtualNIList = append(p.rc.VirtualNIList, &datamodel.VirtualNI{VirtualNetworkInterface: vpc1.VirtualNetworkInterface{
+		ID: id, Name: name, PrimaryIP: primaryIP, Subnet: &vpc1.SubnetReference{CRN: subnet.CRN, ID: subnet.ID, Name: subnet.Name},
+		Target: &vpc1.VirtualNetworkInterfaceTarget{ID: &attachmentID}}})
+	return nil
+}
+
+func (p *terraformParser) parseSecurityGroupTargets() error {
+	for _, r := range p.trs.OfType(tfSecurityGroupTarget) {
+		sg := p.securityGroup(r.RefID("security_group"))
+		if sg == nil {
+			return fmt.Errorf("%s: could not find security group %s", r.Address, r.RefID("security_group"))
+		}
+		targetID := r.RefID("target")
+		targetType, ok := p.interfaceTypes[targetID]
+		if !ok {
+			logging.Warnf("%s: ignoring target %s - only network interfaces targets are supported\n", r.Address, targetID)
+			continue
+		}
+		sg.Targets = append(sg.Targets, &vpc1.SecurityGroupTargetReference{ID: &targetID, ResourceType: &targetType})
+	}
+	return nil
+}
+
+// allocatePendingAddresses sets the addresses of interfaces which are known only after apply,
+// to the first free addresses of their subnets
+func (p *terraformParser) allocatePendingAddresses() error {
+	for _, pending := range p.pendingAddresses {
+		address, err := commonvpc.AllocateAddress(*pending.subnet.Ipv4CIDRBlock, p.takenAddresses[*pending.subnet.ID])
+		if err != nil {
+			return fmt.Errorf("%s: %w", pending.address, err)
+		}
+		logging.Warnf("%s: the address of the interface is known only after apply, assuming it is %s\n", pending.address, address)
+		*pending.ip.Address = address
+		p.takenAddresses[*pending.subnet.ID] = append(p.takenAddresses[*pending.subnet.ID], address)
+	}
+	return nil
+}
+
+func (p *terraformParser) parseFloatingIPs() error {
+	for _, r := range p.trs.OfType(tfFloatingIP) {
+		targetID := r.RefID("target")
+		if targetID == "" {
+			logging.Warnf("skipping %s - it does not have a target\n", r.Address)
+			continue
+		}
+		id, crn, name := identity(r)
+		address := r.Str("address") // empty if known only after apply
+		p.rc.FloatingIPList = append(p.rc.FloatingIPList, &datamodel.FloatingIP{FloatingIP: vpc1.FloatingIP{ID: id, CRN: crn,
+			Name: name, Address: &address, Zone: zoneReference(r.StrOr(tfZone, p.interfaceZones[targetID])),
+			Target: &vpc1.FloatingIPTargetNetworkInterfaceReference{ID: &targetID}}})
+	}
+	return nil
+}
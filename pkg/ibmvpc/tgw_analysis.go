@@ -8,6 +8,8 @@ package ibmvpc
 
 import (
 	"errors"
+	"slices"
+	"strings"
 
 	"github.com/np-guard/cloud-resource-collector/pkg/ibm/datamodel"
 	"github.com/np-guard/models/pkg/netset"
@@ -19,7 +21,8 @@ const (
 	permitAction = "permit"
 	denyAction   = "deny"
 )
-const defaultPrefixFilter = -1
+const defaultPrefixFilter = vpcmodel.DefaultPrefixFilter
+const maxPrefixLength = 32
 
 // getVPCdestSubnetsByAdvertisedRoutes returns a slice of subnets from vpc, which can be destinations of the
 // transit gateway tg, based on its available routes (as determined by prefix filters and matched address prefixes)
@@ -76,6 +79,67 @@ func getVPCAdvertisedRoutes(tc *datamodel.TransitConnection, tcIndex int, vpc *c
 	return advertisedRoutesRes, vpcAPToPrefixRules, nil
 }
 
+// newTransitConnection returns the transit connection tc of vpc to tgw, along with the prefix filters that determine
+// the advertisement of each of the vpc's address prefixes (as computed by getVPCAdvertisedRoutes)
+func (tgw *TransitGateway) newTransitConnection(tc *datamodel.TransitConnection, vpc *commonvpc.VPC,
+	vpcAPToPrefixRules map[*netset.IPBlock]vpcmodel.RulesInTable) (*vpcmodel.TransitConnection, error) {
+	defaultPermit, err := parseActionString(tc.PrefixFiltersDefault)
+	if err != nil {
+		return nil, err
+	}
+	res := &vpcmodel.TransitConnection{VPC: vpc, DefaultPermit: defaultPermit}
+	if tc.Name != nil {
+		res.Name = *tc.Name
+	}
+	for filterIndex := range tc.PrefixFilters {
+		prefixFilter, errFilter := tgw.newPrefixFilter(tc, filterIndex)
+		if errFilter != nil {
+			return nil, errFilter
+		}
+		res.PrefixFilters = append(res.PrefixFilters, prefixFilter)
+	}
+	for ap, rulesInTable := range vpcAPToPrefixRules {
+		res.AddressPrefixes = append(res.AddressPrefixes, &vpcmodel.AddressPrefixFilter{AddressPrefix: ap,
+			FilterIndex: rulesInTable.Rules[0], Permit: rulesInTable.RulesOfType == vpcmodel.OnlyAllow})
+	}
+	slices.SortFunc(res.AddressPrefixes, func(ap1, ap2 *vpcmodel.AddressPrefixFilter) int {
+		return strings.Compare(ap1.AddressPrefix.ToCidrListString(), ap2.AddressPrefix.ToCidrListString())
+	})
+	return res, nil
+}
+
+// newPrefixFilter returns the prefix filter indexed filterIndex of tc; with le/ge, a filter matches the routes
+// within its prefix whose length is at most le and at least ge, and without either it matches the exact prefix
+func (tgw *TransitGateway) newPrefixFilter(tc *datamodel.TransitConnection, filterIndex int) (*vpcmodel.PrefixFilter, error) {
+	pf := tc.PrefixFilters[filterIndex]
+	prefix, err := netset.IPBlockFromCidr(*pf.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	prefixLength, err := prefix.PrefixLength()
+	if err != nil {
+		return nil, err
+	}
+	permit, err := parseActionString(pf.Action)
+	if err != nil {
+		return nil, err
+	}
+	description, _, err := tgw.tgwPrefixStr(tc, filterIndex)
+	if err != nil {
+		return nil, err
+	}
+	res := &vpcmodel.PrefixFilter{Prefix: prefix, MinLength: prefixLength, MaxLength: prefixLength, Permit: permit,
+		Description: description}
+	if pf.Ge != nil {
+		res.MinLength = max(prefixLength, *pf.Ge)
+		res.MaxLength = maxPrefixLength
+	}
+	if pf.Le != nil {
+		res.MaxLength = *pf.Le
+	}
+	return res, nil
+}
+
 // return for a given address-prefix (input cidr) the matching prefix-filter index and its action (allow = true/deny = false)
 // if there is no specific prefix filter then gets the details of the configured default prefix filter
 func getMatchedFilterIndexAndAction(cidr string, tc *datamodel.TransitConnection) (returnedFilterIndex int,
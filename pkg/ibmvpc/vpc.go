@@ -140,6 +140,7 @@ type LoadBalancer struct {
 	vpcmodel.VPCResource
 	nodes     []vpcmodel.Node
 	listeners []LoadBalancerListener
+	pools     []*vpcmodel.LoadBalancerPool
 	// abstractionInfo holds the information the relevant for the abstraction of the load balancer
 	abstractionInfo *vpcmodel.AbstractionInfo
 }
@@ -193,6 +194,10 @@ func (lb *LoadBalancer) AbstractionInfo() *vpcmodel.AbstractionInfo {
 	return lb.abstractionInfo
 }
 
+func (lb *LoadBalancer) Pools() []*vpcmodel.LoadBalancerPool {
+	return lb.pools
+}
+
 // ////////////////////////////////////////////////////////////////////////////////
 // LoadBalancerRule is a rule applied to all private IPs of a given load balancer:
 // these private IPs can only init connection to pool members of the load balancer.
@@ -443,6 +448,9 @@ type TransitGateway struct {
 	// and the index of the matching filter in the transit connection if exists (index "-1" is for default )
 	// this struct can be though of as the "explain" parallel of availableRoutes; note that unlike availableRoutes it also lists deny prefixes
 	vpcsAPToPrefixRules map[string]map[*netset.IPBlock]vpcmodel.RulesInTable
+
+	// transitConns are the transit connections of the connected vpcs, along with their prefix filters
+	transitConns []*vpcmodel.TransitConnection
 }
 
 func (tgw *TransitGateway) addSourceAndDestNodes() {
@@ -454,6 +462,10 @@ func (tgw *TransitGateway) addSourceAndDestNodes() {
 	}
 }
 
+func (tgw *TransitGateway) TransitConnections() []*vpcmodel.TransitConnection {
+	return tgw.transitConns
+}
+
 func (tgw *TransitGateway) Region() *commonvpc.Region {
 	return tgw.region
 }
@@ -0,0 +1,161 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity of a linter's findings
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+
+	defaultSeverity = SeverityWarning
+	defaultFailOn   = SeverityError
+)
+
+// severities ordered from the lowest to the highest
+var severities = []Severity{SeverityInfo, SeverityWarning, SeverityError}
+
+func (s Severity) rank() int {
+	return slices.Index(severities, s)
+}
+
+func (s *Severity) UnmarshalYAML(value *yaml.Node) error {
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+	if !slices.Contains(severities, Severity(str)) {
+		return fmt.Errorf("line %d: severity %q is not one of %v", value.Line, str, severities)
+	}
+	*s = Severity(str)
+	return nil
+}
+
+// LintConfig is the content of a lint configuration file
+type LintConfig struct {
+	// FailOn is the lowest severity of findings that fails the lint command; defaults to error
+	FailOn  Severity                 `yaml:"fail-on"`
+	Linters map[string]*LinterConfig `yaml:"linters"`
+}
+
+// LinterConfig is the configuration of a single linter
+type LinterConfig struct {
+	Severity     Severity          `yaml:"severity"`
+	Params       map[string]string `yaml:"params"`
+	Suppressions []*Suppression    `yaml:"suppressions"`
+}
+
+// Suppression of the findings referring to a resource, e.g. a subnet or a security group, by name;
+// if RuleIndex is set then only the findings of this rule of the named nacl or security group are suppressed
+type Suppression struct {
+	Resource      string `yaml:"resource"`
+	RuleIndex     *int   `yaml:"rule-index"`
+	Justification string `yaml:"justification"`
+}
+
+// ReadLintConfig reads and validates a lint configuration file
+func ReadLintConfig(filePath string) (*LintConfig, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	res := &LintConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(res); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing lint config file %s: %w", filePath, err)
+	}
+	if err := res.validate(); err != nil {
+		return nil, fmt.Errorf("lint config file %s: %w", filePath, err)
+	}
+	return res, nil
+}
+
+func (lc *LintConfig) validate() error {
+	if lc.FailOn == "" {
+		lc.FailOn = defaultFailOn
+	}
+	for name, linterConfig := range lc.Linters {
+		if !IsValidLintersNames(name) {
+			return fmt.Errorf("linter %s does not exist.\t\nLegal linters: %s", name, ValidLintersNames())
+		}
+		if linterConfig == nil {
+			continue
+		}
+		for _, suppression := range linterConfig.Suppressions {
+			if suppression.Resource == "" {
+				return fmt.Errorf("a suppression of linter %s has no resource", name)
+			}
+			if suppression.Justification == "" {
+				return fmt.Errorf("the suppression of %s in linter %s has no justification", suppression.Resource, name)
+			}
+		}
+	}
+	return nil
+}
+
+// linterConfig returns the configuration of the given linter, nil if there is none
+func (lc *LintConfig) linterConfig(name string) *LinterConfig {
+	if lc == nil {
+		return nil
+	}
+	return lc.Linters[name]
+}
+
+func (lc *LintConfig) failOn() Severity {
+	if lc == nil {
+		return defaultFailOn
+	}
+	return lc.FailOn
+}
+
+// suppresses returns true if the suppression applies to the given finding
+func (s *Suppression) suppresses(f Finding) bool {
+	if s.RuleIndex != nil {
+		rule := f.Rule()
+		return rule != nil && rule.Filter.FilterName == s.Resource && rule.RuleIndex == *s.RuleIndex
+	}
+	for _, resource := range f.Resources() {
+		if slices.Contains(resource.names(), s.Resource) {
+			return true
+		}
+	}
+	for _, vpc := range f.VPC() {
+		if vpc != nil && vpc.Name() == s.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// FindingsError is returned by the lint command when there are findings with severity at least the fail-on severity
+type FindingsError struct {
+	Severity Severity // the highest severity of the findings
+}
+
+func (e *FindingsError) Error() string {
+	return fmt.Sprintf("lint found issues of severity %s", e.Severity)
+}
+
+// ExitCode is the exit code of the lint command by the highest severity of the findings: 1 for info, 2 for warning
+// and 3 for error
+func (e *FindingsError) ExitCode() int {
+	return e.Severity.rank() + 1
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/commonvpc"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+// isolatedEndpointsLint: VSIs, VPEs and subnets with no allowed ingress or no allowed egress connectivity
+type isolatedEndpointsLint struct {
+	connectionLinter
+}
+
+func newIsolatedEndpoints(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &isolatedEndpointsLint{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Endpoints and subnets with no allowed ingress or egress connectivity",
+				enable:      true,
+			},
+			nodesConn: nodesConn}}
+}
+
+// the directions in which an endpoint (or all the endpoints of a subnet) has no allowed connectivity,
+// each with the filter layers blocking all its connectivity by themselves
+type isolation struct {
+	ingress         bool
+	egress          bool
+	ingressBlockers []string
+	egressBlockers  []string
+}
+
+// an endpoint or a subnet with no allowed connectivity in at least one direction
+type isolatedResource struct {
+	vpcResource vpcmodel.VPCResourceIntf
+	resource    vpcmodel.VPCResourceIntf // a network interface of a vsi, a reserved ip of a vpe or a subnet
+	isolation
+}
+
+var filterLayers = []string{vpcmodel.NaclLayer, vpcmodel.SecurityGroupLayer}
+
+// isEndpointToCheck returns true for the endpoints of vsis and vpes
+func isEndpointToCheck(node vpcmodel.Node) bool {
+	return node.Kind() == commonvpc.ResourceTypeNetworkInterface || node.Kind() == commonvpc.ResourceTypeReservedIP
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for isolatedEndpointsLint
+// ////////////////////////////////////////////////////////
+
+// Check considers the connectivity of all the configs, since an endpoint may be connected only to endpoints of other
+// vpcs through a transit gateway
+func (lint *isolatedEndpointsLint) Check() error {
+	hasIngress, hasEgress := lint.connectedNodes()
+	for uid, config := range lint.configs {
+		if config.IsMultipleVPCsConfig {
+			continue
+		}
+		nodesConn := lint.nodesConn[uid]
+		for _, subnet := range config.Subnets {
+			subnetIsolation := isolation{ingress: true, egress: true, ingressBlockers: filterLayers, egressBlockers: filterLayers}
+			isolatedEndpoints := []*isolatedResource{}
+			for _, node := range subnet.Nodes() {
+				if !isEndpointToCheck(node) {
+					continue
+				}
+				nodeIsolation := isolation{ingress: !hasIngress[node], egress: !hasEgress[node]}
+				if nodeIsolation.ingress {
+					nodeIsolation.ingressBlockers = blockingLayers(nodesConn, node, true)
+				}
+				if nodeIsolation.egress {
+					nodeIsolation.egressBlockers = blockingLayers(nodesConn, node, false)
+				}
+				subnetIsolation.intersect(&nodeIsolation)
+				if nodeIsolation.ingress || nodeIsolation.egress {
+					isolatedEndpoints = append(isolatedEndpoints,
+						&isolatedResource{vpcResource: config.VPC, resource: node, isolation: nodeIsolation})
+				}
+			}
+			// a subnet all of whose endpoints are isolated in the same directions is reported instead of its endpoints
+			if len(isolatedEndpoints) > 1 && len(isolatedEndpoints) == countEndpoints(subnet) &&
+				!slices.ContainsFunc(isolatedEndpoints, func(endpoint *isolatedResource) bool {
+					return endpoint.ingress != subnetIsolation.ingress || endpoint.egress != subnetIsolation.egress
+				}) {
+				lint.addFinding(&isolatedResource{vpcResource: config.VPC, resource: subnet, isolation: subnetIsolation})
+				continue
+			}
+			for _, endpoint := range isolatedEndpoints {
+				lint.addFinding(endpoint)
+			}
+		}
+	}
+	return nil
+}
+
+func countEndpoints(subnet vpcmodel.Subnet) int {
+	res := 0
+	for _, node := range subnet.Nodes() {
+		if isEndpointToCheck(node) {
+			res++
+		}
+	}
+	return res
+}
+
+// connectedNodes returns the nodes with some allowed ingress connectivity, and those with some allowed egress
+// connectivity, in any of the configs
+func (lint *isolatedEndpointsLint) connectedNodes() (hasIngress, hasEgress map[vpcmodel.VPCResourceIntf]bool) {
+	hasIngress = map[vpcmodel.VPCResourceIntf]bool{}
+	hasEgress = map[vpcmodel.VPCResourceIntf]bool{}
+	for _, nodesConn := range lint.nodesConn {
+		for src, srcMap := range nodesConn.AllowedConnsCombinedResponsive {
+			for dst, conn := range srcMap {
+				if !conn.AllConn().IsEmpty() {
+					hasEgress[src] = true
+					hasIngress[dst] = true
+				}
+			}
+		}
+	}
+	return hasIngress, hasEgress
+}
+
+// blockingLayers returns the filter layers that block by themselves all the connectivity of the node in the given direction
+func blockingLayers(nodesConn *vpcmodel.VPCConnectivity, node vpcmodel.Node, isIngress bool) []string {
+	res := []string{}
+	for _, layer := range filterLayers {
+		layerRes, ok := nodesConn.AllowedConnsPerLayer[node][layer]
+		if !ok {
+			continue
+		}
+		conns := layerRes.EgressAllowedConns
+		if isIngress {
+			conns = layerRes.IngressAllowedConns
+		}
+		blocked := true
+		for _, conn := range conns {
+			if !conn.IsEmpty() {
+				blocked = false
+				break
+			}
+		}
+		if blocked {
+			res = append(res, layer)
+		}
+	}
+	return res
+}
+
+// intersect updates the isolation of a subnet with the isolation of one of its endpoints
+func (i *isolation) intersect(other *isolation) {
+	i.ingress = i.ingress && other.ingress
+	i.egress = i.egress && other.egress
+	i.ingressBlockers = slices.DeleteFunc(slices.Clone(i.ingressBlockers), func(layer string) bool {
+		return !slices.Contains(other.ingressBlockers, layer)
+	})
+	i.egressBlockers = slices.DeleteFunc(slices.Clone(i.egressBlockers), func(layer string) bool {
+		return !slices.Contains(other.egressBlockers, layer)
+	})
+}
+
+// blockersStr describes the layers blocking the connectivity in a direction
+func blockersStr(blockers []string) string {
+	if len(blockers) == 0 {
+		return "blocked by the combination of network ACLs, security groups and routing resources"
+	}
+	names := make([]string, len(blockers))
+	for i, layer := range blockers {
+		names[i] = vpcmodel.FilterKindName(layer)
+	}
+	return "blocked by " + strings.Join(names, " and ")
+}
+
+// isolationStr describes the isolated directions; a single isolated direction is named, while isolation in both
+// directions is reported as no connectivity
+func (i *isolation) isolationStr() string {
+	switch {
+	case i.ingress && i.egress:
+		return fmt.Sprintf("has no allowed connectivity: ingress %s, egress %s", blockersStr(i.ingressBlockers),
+			blockersStr(i.egressBlockers))
+	case i.ingress:
+		return fmt.Sprintf("has no allowed ingress connectivity (%s)", blockersStr(i.ingressBlockers))
+	default:
+		return fmt.Sprintf("has no allowed egress connectivity (%s)", blockersStr(i.egressBlockers))
+	}
+}
+
+func blockersNames(isolated bool, blockers []string) []string {
+	if !isolated {
+		return nil
+	}
+	res := make([]string, len(blockers))
+	for i, layer := range blockers {
+		res[i] = vpcmodel.FilterKindName(layer)
+	}
+	return res
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for isolatedResource
+//////////////////////////////////////////////////////////
+
+func (finding *isolatedResource) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *isolatedResource) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.resource)}
+}
+
+func (finding *isolatedResource) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *isolatedResource) resourceStr() string {
+	if node, ok := finding.resource.(vpcmodel.Node); ok {
+		return node.NameForAnalyzerOut(nil)
+	}
+	return fmt.Sprintf("subnet %q (all its endpoints)", finding.resource.Name())
+}
+
+func (finding *isolatedResource) String() string {
+	return fmt.Sprintf("In VPC %q, %s %s", finding.vpcResource.Name(), finding.resourceStr(), finding.isolationStr())
+}
+
+// for json:
+type isolatedResourceJSON struct {
+	VpcName         string   `json:"vpc_name"`
+	Kind            string   `json:"kind"`
+	Name            string   `json:"name"`
+	IngressIsolated bool     `json:"ingress_isolated"`
+	IngressBlockers []string `json:"ingress_blocked_by,omitempty"`
+	EgressIsolated  bool     `json:"egress_isolated"`
+	EgressBlockers  []string `json:"egress_blocked_by,omitempty"`
+}
+
+func (finding *isolatedResource) ToJSON() any {
+	name := finding.resource.Name()
+	if node, ok := finding.resource.(vpcmodel.Node); ok {
+		name = node.NameForAnalyzerOut(nil)
+	}
+	return isolatedResourceJSON{VpcName: finding.vpcResource.Name(), Kind: finding.resource.Kind(), Name: name,
+		IngressIsolated: finding.ingress, IngressBlockers: blockersNames(finding.ingress, finding.ingressBlockers),
+		EgressIsolated: finding.egress, EgressBlockers: blockersNames(finding.egress, finding.egressBlockers)}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netp"
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+const udpPoolProtocol = "udp"
+
+// lbMemberUnreachableLint: load balancer pool members that are not reachable from the load balancer on their port
+type lbMemberUnreachableLint struct {
+	basicLinter
+}
+
+func newLBMemberUnreachable(name string, configs map[string]*vpcmodel.VPCConfig,
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &lbMemberUnreachableLint{
+		basicLinter: basicLinter{
+			configs:     configs,
+			name:        name,
+			description: "Load balancer pool members not reachable from the load balancer",
+			enable:      true,
+		}}
+}
+
+// a pool member that some private IPs of its load balancer can not reach on the member port
+type memberUnreachable struct {
+	lb         vpcmodel.LoadBalancer
+	pool       *vpcmodel.LoadBalancerPool
+	member     *vpcmodel.LoadBalancerPoolMember
+	conn       *netset.TransportSet // the connection to the member port
+	privateIPs []vpcmodel.Node      // the private IPs of the load balancer that can not reach the member
+}
+
+// a subnet of a load balancer from which none of the members of a pool is reachable
+type lbSubnetNoMember struct {
+	lb     vpcmodel.LoadBalancer
+	pool   *vpcmodel.LoadBalancerPool
+	subnet vpcmodel.Subnet
+}
+
+// memberConn returns the connection from the load balancer to the port of the given pool member
+func memberConn(pool *vpcmodel.LoadBalancerPool, member *vpcmodel.LoadBalancerPoolMember) *netset.TransportSet {
+	protocol := netp.ProtocolStringTCP
+	if strings.EqualFold(pool.Protocol, udpPoolProtocol) {
+		protocol = netp.ProtocolStringUDP
+	}
+	return netset.NewTCPorUDPTransport(protocol, netp.MinPort, netp.MaxPort, member.Port, member.Port)
+}
+
+// reachableConn returns the connection from src to dst, excluding TCP connections whose response is blocked
+func reachableConn(nodesConn *vpcmodel.VPCConnectivity, src, dst vpcmodel.Node) *netset.TransportSet {
+	conn, ok := nodesConn.AllowedConnsCombinedResponsive[src][dst]
+	if !ok {
+		return netset.NoTransports()
+	}
+	return conn.AllConn().Subtract(conn.TCPRspDisable)
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for lbMemberUnreachableLint
+// ////////////////////////////////////////////////////////
+
+// Check computes the connectivity of each config with load balancers without abstracting them, since the
+// connectivity of each of their private IPs is required
+func (lint *lbMemberUnreachableLint) Check() error {
+	for _, config := range lint.configs {
+		if config.IsMultipleVPCsConfig || len(config.LoadBalancers) == 0 {
+			continue
+		}
+		nodesConn, err := config.GetVPCNetworkConnectivity(false, vpcmodel.NoGroupingNoConsistencyEdges)
+		if err != nil {
+			return err
+		}
+		for _, lb := range config.LoadBalancers {
+			lint.checkLoadBalancer(nodesConn, lb)
+		}
+	}
+	return nil
+}
+
+func (lint *lbMemberUnreachableLint) checkLoadBalancer(nodesConn *vpcmodel.VPCConnectivity, lb vpcmodel.LoadBalancer) {
+	for _, pool := range lb.Pools() {
+		// the subnets of the private IPs, mapped to whether some member of the pool is reachable from them
+		subnetReachesMember := map[vpcmodel.Subnet]bool{}
+		for _, privateIP := range lb.Nodes() {
+			if internal, ok := privateIP.(vpcmodel.InternalNodeIntf); ok {
+				subnetReachesMember[internal.Subnet()] = false
+			}
+		}
+		for _, member := range pool.Members {
+			conn := memberConn(pool, member)
+			unreachableFrom := []vpcmodel.Node{}
+			for _, privateIP := range lb.Nodes() {
+				if !conn.IsSubset(reachableConn(nodesConn, privateIP, member.Node)) {
+					unreachableFrom = append(unreachableFrom, privateIP)
+				} else if internal, ok := privateIP.(vpcmodel.InternalNodeIntf); ok {
+					subnetReachesMember[internal.Subnet()] = true
+				}
+			}
+			if len(unreachableFrom) > 0 {
+				lint.addFinding(&memberUnreachable{lb: lb, pool: pool, member: member, conn: conn,
+					privateIPs: unreachableFrom})
+			}
+		}
+		if len(pool.Members) == 0 {
+			continue
+		}
+		for subnet, reachesMember := range subnetReachesMember {
+			if !reachesMember {
+				lint.addFinding(&lbSubnetNoMember{lb: lb, pool: pool, subnet: subnet})
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for memberUnreachable
+//////////////////////////////////////////////////////////
+
+func (finding *memberUnreachable) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.lb.VPC()}
+}
+
+func (finding *memberUnreachable) Resources() []ResourceRef {
+	res := []ResourceRef{NewResourceRef(finding.lb), NewResourceRef(finding.member.Node)}
+	for _, privateIP := range finding.privateIPs {
+		res = append(res, NewResourceRef(privateIP))
+	}
+	return res
+}
+
+func (finding *memberUnreachable) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func nodesNames(nodes []vpcmodel.Node) []string {
+	res := make([]string, len(nodes))
+	for i, node := range nodes {
+		res[i] = node.NameForAnalyzerOut(nil)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (finding *memberUnreachable) String() string {
+	return fmt.Sprintf("In VPC %q, member %s of pool %q of load balancer %q is not reachable on %s from the "+
+		"load balancer's private IPs %s", finding.lb.VPC().Name(), finding.member.Node.NameForAnalyzerOut(nil),
+		finding.pool.Name, finding.lb.Name(), common.ShortString(finding.conn),
+		strings.Join(nodesNames(finding.privateIPs), ", "))
+}
+
+// for json:
+type memberUnreachableJSON struct {
+	VpcName      string         `json:"vpc_name"`
+	LoadBalancer string         `json:"load_balancer"`
+	Pool         string         `json:"pool"`
+	Member       string         `json:"member"`
+	Conn         netset.Details `json:"member_connection"`
+	PrivateIPs   []string       `json:"unreachable_from"`
+}
+
+func (finding *memberUnreachable) ToJSON() any {
+	return memberUnreachableJSON{VpcName: finding.lb.VPC().Name(), LoadBalancer: finding.lb.Name(),
+		Pool: finding.pool.Name, Member: finding.member.Node.NameForAnalyzerOut(nil),
+		Conn: netset.ToJSON(finding.conn), PrivateIPs: nodesNames(finding.privateIPs)}
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for lbSubnetNoMember
+//////////////////////////////////////////////////////////
+
+func (finding *lbSubnetNoMember) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.lb.VPC()}
+}
+
+func (finding *lbSubnetNoMember) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.lb), NewResourceRef(finding.subnet)}
+}
+
+func (finding *lbSubnetNoMember) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *lbSubnetNoMember) String() string {
+	return fmt.Sprintf("In VPC %q, none of the members of pool %q of load balancer %q is reachable from its "+
+		"subnet %q", finding.lb.VPC().Name(), finding.pool.Name, finding.lb.Name(), finding.subnet.Name())
+}
+
+// for json:
+type lbSubnetNoMemberJSON struct {
+	VpcName      string `json:"vpc_name"`
+	LoadBalancer string `json:"load_balancer"`
+	Pool         string `json:"pool"`
+	Subnet       string `json:"subnet"`
+}
+
+func (finding *lbSubnetNoMember) ToJSON() any {
+	return lbSubnetNoMemberJSON{VpcName: finding.lb.VPC().Name(), LoadBalancer: finding.lb.Name(),
+		Pool: finding.pool.Name, Subnet: finding.subnet.Name()}
+}
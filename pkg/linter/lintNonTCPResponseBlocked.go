@@ -0,0 +1,181 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+const allRanges = "(all ranges)"
+
+// blockedNonTCPResponseLint: UDP connections and ICMP echo requests whose response is blocked by the stateless
+// network ACLs
+type blockedNonTCPResponseLint struct {
+	connectionLinter
+}
+
+func newNonTCPResponseBlocked(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &blockedNonTCPResponseLint{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Blocked UDP and ICMP echo response",
+				enable:      true,
+			},
+			nodesConn: nodesConn}}
+}
+
+// an endpoint of a connection; external addresses of the same kind are grouped into a single endpoint
+type responseEndpoint struct {
+	resource vpcmodel.VPCResourceIntf // nil for external addresses
+	name     string
+}
+
+// UDP connection or ICMP echo requests with no response
+type blockedNonTCPResponseConn struct {
+	src              responseEndpoint
+	dst              responseEndpoint
+	nonTCPRspDisable *netset.TransportSet
+}
+
+// the blocked connections between an internal endpoint and external addresses of a given kind, that are grouped
+// into a single finding
+type externalPeersKey struct {
+	internal       vpcmodel.VPCResourceIntf
+	isSrcExternal  bool
+	publicInternet bool
+	conn           string
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for blockedNonTCPResponseLint
+// ////////////////////////////////////////////////////////
+
+func (lint *blockedNonTCPResponseLint) Check() error {
+	for uid, nodesConn := range lint.nodesConn {
+		config := lint.configs[uid]
+		externalPeers := map[externalPeersKey]*netset.IPBlock{}
+		externalConns := map[externalPeersKey]*netset.TransportSet{}
+		for src, srcMap := range nodesConn.AllowedConnsCombinedResponsive {
+			for dst, conn := range srcMap {
+				nonTCPRspDisable := conn.NonTCPRspDisable()
+				if nonTCPRspDisable.IsEmpty() {
+					continue
+				}
+				srcExternal, isSrcExternal := externalNode(src)
+				dstExternal, isDstExternal := externalNode(dst)
+				var key externalPeersKey
+				var external vpcmodel.Node
+				switch {
+				case isSrcExternal:
+					key = externalPeersKey{internal: dst, isSrcExternal: true}
+					external = srcExternal
+				case isDstExternal:
+					key = externalPeersKey{internal: src}
+					external = dstExternal
+				default:
+					lint.addFinding(&blockedNonTCPResponseConn{src: responseEndpoint{resource: src, name: src.NameForAnalyzerOut(nil)},
+						dst: responseEndpoint{resource: dst, name: dst.NameForAnalyzerOut(nil)}, nonTCPRspDisable: nonTCPRspDisable})
+					continue
+				}
+				key.publicInternet = external.IsPublicInternet()
+				key.conn = common.ShortString(nonTCPRspDisable)
+				if _, ok := externalPeers[key]; !ok {
+					externalPeers[key] = netset.NewIPBlock()
+					externalConns[key] = nonTCPRspDisable
+				}
+				externalPeers[key] = externalPeers[key].Union(external.IPBlock())
+			}
+		}
+		for key, addresses := range externalPeers {
+			internal := responseEndpoint{resource: key.internal, name: key.internal.NameForAnalyzerOut(nil)}
+			external := responseEndpoint{name: externalAddressesName(config, key.publicInternet, addresses)}
+			finding := &blockedNonTCPResponseConn{src: internal, dst: external, nonTCPRspDisable: externalConns[key]}
+			if key.isSrcExternal {
+				finding.src, finding.dst = external, internal
+			}
+			lint.addFinding(finding)
+		}
+	}
+	return nil
+}
+
+func externalNode(resource vpcmodel.VPCResourceIntf) (vpcmodel.Node, bool) {
+	node, ok := resource.(vpcmodel.Node)
+	return node, ok && node.IsExternal()
+}
+
+// externalAddressesName returns the name of the given Public Internet or Service Network addresses
+func externalAddressesName(config *vpcmodel.VPCConfig, publicInternet bool, addresses *netset.IPBlock) string {
+	kind := "Service Network"
+	if publicInternet {
+		kind = "Public Internet"
+	}
+	if addresses.Equal(externalAddresses(config, publicInternet)) {
+		return kind + " " + allRanges
+	}
+	return kind + " " + addresses.ToCidrListString()
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for blockedNonTCPResponseConn
+//////////////////////////////////////////////////////////
+
+func (finding *blockedNonTCPResponseConn) VPC() []vpcmodel.VPCResourceIntf {
+	res := []vpcmodel.VPCResourceIntf{}
+	for _, endpoint := range []responseEndpoint{finding.src, finding.dst} {
+		if endpoint.resource != nil {
+			res = append(res, endpoint.resource.VPC())
+		}
+	}
+	return res
+}
+
+func (finding *blockedNonTCPResponseConn) Resources() []ResourceRef {
+	res := []ResourceRef{}
+	for _, endpoint := range []responseEndpoint{finding.src, finding.dst} {
+		if endpoint.resource != nil {
+			res = append(res, NewResourceRef(endpoint.resource))
+		}
+	}
+	return res
+}
+
+func (finding *blockedNonTCPResponseConn) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (endpoint *responseEndpoint) fullName() string {
+	if endpoint.resource != nil && endpoint.resource.VPC() != nil {
+		return endpoint.resource.VPC().Name() + deliminator + endpoint.name
+	}
+	return endpoint.name
+}
+
+func (finding *blockedNonTCPResponseConn) String() string {
+	return fmt.Sprintf("In the connection from %q to %q %s response is blocked", finding.src.fullName(),
+		finding.dst.fullName(), common.ShortString(finding.nonTCPRspDisable))
+}
+
+// UDP connection or ICMP echo requests with no response
+type blockedNonTCPResponseConnJSON struct {
+	Src              string         `json:"source"`
+	Dst              string         `json:"destination"`
+	NonTCPRspDisable netset.Details `json:"udp_icmp_non_responsive"`
+}
+
+func (finding *blockedNonTCPResponseConn) ToJSON() any {
+	return blockedNonTCPResponseConnJSON{Src: finding.src.fullName(), Dst: finding.dst.fullName(),
+		NonTCPRspDisable: netset.ToJSON(finding.nonTCPRspDisable)}
+}
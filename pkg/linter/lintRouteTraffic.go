@@ -0,0 +1,277 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+func newRouteDropAllowed(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &routingTableLinter{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Drop routes blackholing traffic allowed by network ACLs and security groups",
+				enable:      true,
+			},
+			nodesConn: nodesConn},
+		checkForTable: findDropRoutesOfAllowedTraffic}
+}
+
+func newRouteNextHopBlocked(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &routingTableLinter{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Next hops whose security groups block the traffic routed to them",
+				enable:      true,
+			},
+			nodesConn: nodesConn},
+		checkForTable: findBlockedNextHops}
+}
+
+// a connection of src to dst
+type nodesPairConn struct {
+	src  vpcmodel.Node
+	dst  vpcmodel.Node
+	conn *netset.TransportSet
+}
+
+// a drop route that blackholes traffic from the subnets attached to its routing table, which the filters allow
+type routeDropsAllowed struct {
+	vpcResource vpcmodel.VPCResourceIntf
+	table       vpcmodel.RoutingTable
+	route       *vpcmodel.Route
+	dropped     *netset.IPBlock // the part of the route's destination to which the route is applied
+	conns       []nodesPairConn // the allowed connections to the dropped destination
+}
+
+// the next hop of a deliver route, whose security groups block traffic routed to it from the subnets attached
+// to the route's routing table
+type nextHopBlocked struct {
+	vpcResource vpcmodel.VPCResourceIntf
+	table       vpcmodel.RoutingTable
+	route       *vpcmodel.Route
+	nextHop     vpcmodel.Node
+	conns       []nodesPairConn // the connections from the sources to the next hop, blocked by its security groups
+}
+
+// routedSources returns the nodes of the subnets attached to the table, whose egress traffic is subject to the
+// routes of the given zone
+func routedSources(table vpcmodel.RoutingTable, zone string) []vpcmodel.Node {
+	res := []vpcmodel.Node{}
+	for _, subnet := range table.AttachedSubnets() {
+		if subnet.ZoneName() == zone {
+			res = append(res, subnet.Nodes()...)
+		}
+	}
+	return res
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for routingTableLinter
+// ////////////////////////////////////////////////////////
+
+// findDropRoutesOfAllowedTraffic checks only egress routing tables, as the sources of the traffic routed by
+// ingress routing tables are outside the VPC
+func findDropRoutesOfAllowedTraffic(config *vpcmodel.VPCConfig, nodesConn *vpcmodel.VPCConnectivity,
+	table vpcmodel.RoutingTable) (res []Finding, err error) {
+	if nodesConn == nil {
+		return nil, nil
+	}
+	routes := table.Routes()
+	for _, route := range routes {
+		if route.Action != vpcmodel.RouteDrop {
+			continue
+		}
+		dropped := effectiveDestination(route, routes)
+		if dropped.IsEmpty() {
+			continue
+		}
+		finding := &routeDropsAllowed{vpcResource: config.VPC, table: table, route: route, dropped: dropped}
+		for _, src := range routedSources(table, route.Zone) {
+			for dst, conn := range nodesConn.AllowedConnsCombinedResponsive[src] {
+				dstNode, ok := dst.(vpcmodel.Node)
+				if ok && dstNode.IPBlock().Overlap(dropped) && !conn.AllConn().IsEmpty() {
+					finding.conns = append(finding.conns, nodesPairConn{src: src, dst: dstNode, conn: conn.AllConn()})
+				}
+			}
+		}
+		if len(finding.conns) > 0 {
+			res = append(res, finding)
+		}
+	}
+	return res, nil
+}
+
+// findBlockedNextHops reports, for each deliver route, the part of the traffic forwarded by the route (that is, the
+// traffic the filters allow from the routed sources to the route's destination) that the next hop's security groups
+// block
+func findBlockedNextHops(config *vpcmodel.VPCConfig, nodesConn *vpcmodel.VPCConnectivity,
+	table vpcmodel.RoutingTable) (res []Finding, err error) {
+	sgLayer := config.GetFilterTrafficResourceOfKind(vpcmodel.SecurityGroupLayer)
+	if sgLayer == nil || nodesConn == nil {
+		return nil, nil
+	}
+	routes := table.Routes()
+	for _, route := range routes {
+		if route.Action != vpcmodel.RouteDeliver {
+			continue
+		}
+		destination := effectiveDestination(route, routes)
+		if destination.IsEmpty() {
+			continue
+		}
+		nextHop := nextHopNode(config, route)
+		if nextHop == nil {
+			continue // reported by route-next-hop-unknown
+		}
+		finding := &nextHopBlocked{vpcResource: config.VPC, table: table, route: route, nextHop: nextHop}
+		for _, src := range routedSources(table, route.Zone) {
+			if src == nextHop {
+				continue
+			}
+			forwarded := forwardedConn(nodesConn, src, destination)
+			if forwarded.IsEmpty() {
+				continue
+			}
+			allowed, errConn := sgLayer.AllowedConnectivity(src, nextHop, true)
+			if errConn != nil {
+				return nil, errConn
+			}
+			if blocked := forwarded.Subtract(allowed); !blocked.IsEmpty() {
+				finding.conns = append(finding.conns, nodesPairConn{src: src, dst: nextHop, conn: blocked})
+			}
+		}
+		if len(finding.conns) > 0 {
+			res = append(res, finding)
+		}
+	}
+	return res, nil
+}
+
+// forwardedConn returns the union of the connections the filters allow from src to the nodes overlapping destination
+func forwardedConn(nodesConn *vpcmodel.VPCConnectivity, src vpcmodel.Node, destination *netset.IPBlock) *netset.TransportSet {
+	res := netset.NoTransports()
+	for dst, conn := range nodesConn.AllowedConnsCombinedResponsive[src] {
+		if dstNode, ok := dst.(vpcmodel.Node); ok && dstNode.IPBlock().Overlap(destination) {
+			res = res.Union(conn.AllConn())
+		}
+	}
+	return res
+}
+
+func connsStrings(conns []nodesPairConn) []string {
+	res := make([]string, len(conns))
+	for i, conn := range conns {
+		res[i] = fmt.Sprintf("%s => %s: %s", conn.src.NameForAnalyzerOut(nil), conn.dst.NameForAnalyzerOut(nil),
+			common.ShortString(conn.conn))
+	}
+	sort.Strings(res)
+	return res
+}
+
+// for json:
+type nodesPairConnJSON struct {
+	Src  string         `json:"src"`
+	Dst  string         `json:"dst"`
+	Conn netset.Details `json:"conn"`
+}
+
+func connsToJSON(conns []nodesPairConn) []nodesPairConnJSON {
+	res := make([]nodesPairConnJSON, len(conns))
+	for i, conn := range conns {
+		res[i] = nodesPairConnJSON{Src: conn.src.NameForAnalyzerOut(nil), Dst: conn.dst.NameForAnalyzerOut(nil),
+			Conn: netset.ToJSON(conn.conn)}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Src < res[j].Src || (res[i].Src == res[j].Src && res[i].Dst < res[j].Dst)
+	})
+	return res
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for routeDropsAllowed
+//////////////////////////////////////////////////////////
+
+func (finding *routeDropsAllowed) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *routeDropsAllowed) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.table)}
+}
+
+func (finding *routeDropsAllowed) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *routeDropsAllowed) String() string {
+	return fmt.Sprintf("In VPC %q, drop route %q of routing table %q blackholes traffic to %s allowed by network ACLs "+
+		"and security groups:\n\t\t%s", finding.vpcResource.Name(), finding.route.Name, finding.table.Name(),
+		finding.dropped.ToCidrListString(), strings.Join(connsStrings(finding.conns), "\n\t\t"))
+}
+
+type routeDropsAllowedJSON struct {
+	VpcName      string              `json:"vpc_name"`
+	RoutingTable string              `json:"routing_table"`
+	Route        routeJSON           `json:"route"`
+	Dropped      string              `json:"dropped_destination"`
+	Conns        []nodesPairConnJSON `json:"allowed_connections"`
+}
+
+func (finding *routeDropsAllowed) ToJSON() any {
+	return routeDropsAllowedJSON{VpcName: finding.vpcResource.Name(), RoutingTable: finding.table.Name(),
+		Route: toRouteJSON(finding.route), Dropped: finding.dropped.ToCidrListString(), Conns: connsToJSON(finding.conns)}
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for nextHopBlocked
+//////////////////////////////////////////////////////////
+
+func (finding *nextHopBlocked) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *nextHopBlocked) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.table), NewResourceRef(finding.nextHop)}
+}
+
+func (finding *nextHopBlocked) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *nextHopBlocked) String() string {
+	return fmt.Sprintf("In VPC %q, next hop %s of route %q of routing table %q is blocked by its security groups "+
+		"for traffic routed to it:\n\t\t%s", finding.vpcResource.Name(), finding.nextHop.NameForAnalyzerOut(nil),
+		finding.route.Name, finding.table.Name(), strings.Join(connsStrings(finding.conns), "\n\t\t"))
+}
+
+type nextHopBlockedJSON struct {
+	VpcName      string              `json:"vpc_name"`
+	RoutingTable string              `json:"routing_table"`
+	Route        routeJSON           `json:"route"`
+	NextHop      string              `json:"next_hop"`
+	Conns        []nodesPairConnJSON `json:"blocked_connections"`
+}
+
+func (finding *nextHopBlocked) ToJSON() any {
+	return nextHopBlockedJSON{VpcName: finding.vpcResource.Name(), RoutingTable: finding.table.Name(),
+		Route: toRouteJSON(finding.route), NextHop: finding.nextHop.NameForAnalyzerOut(nil),
+		Conns: connsToJSON(finding.conns)}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+func newRouteShadowed(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &routingTableLinter{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Routes shadowed by more specific or higher priority routes",
+				enable:      true,
+			},
+			nodesConn: nodesConn},
+		checkForTable: findShadowedRoutes}
+}
+
+func newRouteNextHopUnknown(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &routingTableLinter{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Deliver routes whose next hop is not an endpoint in the VPC",
+				enable:      true,
+			},
+			nodesConn: nodesConn},
+		checkForTable: findRoutesWithUnknownNextHop}
+}
+
+// a route that is not applied to any destination, since the routes that precede it cover its destination
+type routeShadowed struct {
+	vpcResource vpcmodel.VPCResourceIntf
+	table       vpcmodel.RoutingTable
+	route       *vpcmodel.Route
+	shadowing   []*vpcmodel.Route
+}
+
+// a deliver route whose next hop is not the address of an endpoint in the VPC
+type routeNextHopUnknown struct {
+	vpcResource vpcmodel.VPCResourceIntf
+	table       vpcmodel.RoutingTable
+	route       *vpcmodel.Route
+}
+
+// precedingRoutes returns the routes selected over the given route for some of its destination
+func precedingRoutes(route *vpcmodel.Route, routes []*vpcmodel.Route) []*vpcmodel.Route {
+	res := []*vpcmodel.Route{}
+	for _, other := range routes {
+		if other != route && other.Zone == route.Zone && other.Precedes(route) &&
+			other.Destination.Overlap(route.Destination) {
+			res = append(res, other)
+		}
+	}
+	return res
+}
+
+// effectiveDestination returns the part of the route's destination to which the route is applied
+func effectiveDestination(route *vpcmodel.Route, routes []*vpcmodel.Route) *netset.IPBlock {
+	res := route.Destination
+	for _, other := range precedingRoutes(route, routes) {
+		res = res.Subtract(other.Destination)
+	}
+	return res
+}
+
+// nextHopNode returns the internal node whose address is the next hop of the given deliver route, if any
+func nextHopNode(config *vpcmodel.VPCConfig, route *vpcmodel.Route) vpcmodel.Node {
+	for _, node := range config.Nodes {
+		if node.IsInternal() && node.IPBlock().Equal(route.NextHop) {
+			return node
+		}
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for routingTableLinter
+// ////////////////////////////////////////////////////////
+
+func findShadowedRoutes(config *vpcmodel.VPCConfig, _ *vpcmodel.VPCConnectivity,
+	table vpcmodel.RoutingTable) (res []Finding, err error) {
+	routes := table.Routes()
+	for _, route := range routes {
+		if effectiveDestination(route, routes).IsEmpty() {
+			res = append(res, &routeShadowed{vpcResource: config.VPC, table: table, route: route,
+				shadowing: precedingRoutes(route, routes)})
+		}
+	}
+	return res, nil
+}
+
+func findRoutesWithUnknownNextHop(config *vpcmodel.VPCConfig, _ *vpcmodel.VPCConnectivity,
+	table vpcmodel.RoutingTable) (res []Finding, err error) {
+	for _, route := range table.Routes() {
+		if route.Action == vpcmodel.RouteDeliver && nextHopNode(config, route) == nil {
+			res = append(res, &routeNextHopUnknown{vpcResource: config.VPC, table: table, route: route})
+		}
+	}
+	return res, nil
+}
+
+// for json:
+type routeJSON struct {
+	Name        string `json:"name"`
+	Zone        string `json:"zone"`
+	Destination string `json:"destination"`
+	Action      string `json:"action"`
+	NextHop     string `json:"next_hop,omitempty"`
+	Priority    int    `json:"priority"`
+}
+
+func toRouteJSON(route *vpcmodel.Route) routeJSON {
+	res := routeJSON{Name: route.Name, Zone: route.Zone, Destination: route.Destination.ToCidrListString(),
+		Action: string(route.Action), Priority: route.Priority}
+	if route.NextHop != nil {
+		res.NextHop = route.NextHop.ToIPAddressString()
+	}
+	return res
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for routeShadowed
+//////////////////////////////////////////////////////////
+
+func (finding *routeShadowed) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *routeShadowed) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.table)}
+}
+
+func (finding *routeShadowed) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *routeShadowed) String() string {
+	res := fmt.Sprintf("In VPC %q, route %q of routing table %q is shadowed by more specific or higher priority routes"+
+		"\n\tRoute details: %s\n\tShadowing routes:", finding.vpcResource.Name(), finding.route.Name, finding.table.Name(),
+		finding.route.Description)
+	shadowing := make([]string, len(finding.shadowing))
+	for i, route := range finding.shadowing {
+		shadowing[i] = fmt.Sprintf("\n\t\t%s: %s", route.Name, route.Description)
+	}
+	sort.Strings(shadowing)
+	return res + strings.Join(shadowing, "")
+}
+
+type routeShadowedJSON struct {
+	VpcName      string      `json:"vpc_name"`
+	RoutingTable string      `json:"routing_table"`
+	Route        routeJSON   `json:"route"`
+	Shadowing    []routeJSON `json:"shadowing_routes"`
+}
+
+func (finding *routeShadowed) ToJSON() any {
+	shadowing := make([]routeJSON, len(finding.shadowing))
+	for i, route := range finding.shadowing {
+		shadowing[i] = toRouteJSON(route)
+	}
+	sort.Slice(shadowing, func(i, j int) bool { return shadowing[i].Name < shadowing[j].Name })
+	return routeShadowedJSON{VpcName: finding.vpcResource.Name(), RoutingTable: finding.table.Name(),
+		Route: toRouteJSON(finding.route), Shadowing: shadowing}
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for routeNextHopUnknown
+//////////////////////////////////////////////////////////
+
+func (finding *routeNextHopUnknown) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *routeNextHopUnknown) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.table)}
+}
+
+func (finding *routeNextHopUnknown) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *routeNextHopUnknown) String() string {
+	return fmt.Sprintf("In VPC %q, route %q of routing table %q delivers traffic to %s through next hop %s, "+
+		"which is not the address of an endpoint in the VPC", finding.vpcResource.Name(), finding.route.Name,
+		finding.table.Name(), finding.route.Destination.ToCidrListString(), finding.route.NextHop.ToIPAddressString())
+}
+
+type routeNextHopUnknownJSON struct {
+	VpcName      string    `json:"vpc_name"`
+	RoutingTable string    `json:"routing_table"`
+	Route        routeJSON `json:"route"`
+}
+
+func (finding *routeNextHopUnknown) ToJSON() any {
+	return routeNextHopUnknownJSON{VpcName: finding.vpcResource.Name(), RoutingTable: finding.table.Name(),
+		Route: toRouteJSON(finding.route)}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+// ruleBlockedByOtherLayerLint: filter rules of one layer all of whose allowed traffic is blocked by the other layer;
+// nacl rules whose traffic is blocked by the sgs of the subnets' endpoints, and sg rules whose traffic is denied by
+// the nacls of the endpoints' subnets
+type ruleBlockedByOtherLayerLint struct {
+	connectionLinter
+	layer      string // the layer of the checked rules
+	otherLayer string // the layer blocking the traffic of the rules
+}
+
+func newNACLRuleBlockedBySG(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &ruleBlockedByOtherLayerLint{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Network ACL rules whose allowed traffic is blocked by security groups",
+				enable:      false,
+			},
+			nodesConn: nodesConn},
+		layer:      vpcmodel.NaclLayer,
+		otherLayer: vpcmodel.SecurityGroupLayer}
+}
+
+func newSGRuleDeniedByNACL(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &ruleBlockedByOtherLayerLint{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Security group rules whose allowed traffic is denied by network ACLs",
+				enable:      false,
+			},
+			nodesConn: nodesConn},
+		layer:      vpcmodel.SecurityGroupLayer,
+		otherLayer: vpcmodel.NaclLayer}
+}
+
+// a rule that never takes effect, since all the traffic it allows is blocked by the other filter layer
+type ruleBlockedByOtherLayer struct {
+	rule        vpcmodel.RuleOfFilter
+	vpcResource vpcmodel.VPCResourceIntf
+	otherLayer  string
+}
+
+// identifies a rule in the rules of a filter layer
+type layerRuleKey struct {
+	filterIndex int
+	ruleIndex   int
+	isIngress   bool
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for ruleBlockedByOtherLayerLint
+// ////////////////////////////////////////////////////////
+
+// Check compares, for each internal node, direction and peer, the connectivity allowed by each of the two filter layers
+// alone. A rule is reported if it contributes to traffic allowed by its layer and blocked by the other layer, and does
+// not contribute to any traffic allowed by both layers
+func (lint *ruleBlockedByOtherLayerLint) Check() error {
+	for uid, config := range lint.configs {
+		if config.IsMultipleVPCsConfig {
+			continue // no use in executing lint on dummy vpcs
+		}
+		filterLayer := config.GetFilterTrafficResourceOfKind(lint.layer)
+		if filterLayer == nil || config.GetFilterTrafficResourceOfKind(lint.otherLayer) == nil {
+			continue
+		}
+		nodesConn := lint.nodesConn[uid]
+		blockedRules, err := lint.rulesOfTraffic(filterLayer, nodesConn, true, nil)
+		if err != nil {
+			return err
+		}
+		if len(blockedRules) == 0 {
+			continue
+		}
+		effectiveRules, err := lint.rulesOfTraffic(filterLayer, nodesConn, false, blockedRules)
+		if err != nil {
+			return err
+		}
+		rules, err := filterLayer.GetRules()
+		if err != nil {
+			return err
+		}
+		for i := range rules {
+			key := layerRuleKey{filterIndex: rules[i].Filter.FilterIndex, ruleIndex: rules[i].RuleIndex,
+				isIngress: rules[i].IsIngress}
+			if blockedRules[key] && !effectiveRules[key] {
+				lint.addFinding(&ruleBlockedByOtherLayer{rule: rules[i], vpcResource: config.VPC, otherLayer: lint.otherLayer})
+			}
+		}
+	}
+	return nil
+}
+
+// rulesOfTraffic returns the rules of the linter's layer contributing to the traffic allowed by this layer that is
+// blocked by the other layer (if blockedByOther) or allowed by it as well (otherwise). If candidates are given,
+// the computation stops once all of them are found
+func (lint *ruleBlockedByOtherLayerLint) rulesOfTraffic(filterLayer vpcmodel.FilterTrafficResource,
+	nodesConn *vpcmodel.VPCConnectivity, blockedByOther bool, candidates map[layerRuleKey]bool) (map[layerRuleKey]bool, error) {
+	res := map[layerRuleKey]bool{}
+	for node, connsPerLayer := range nodesConn.AllowedConnsPerLayer {
+		layerConns, otherLayerConns := connsPerLayer[lint.layer], connsPerLayer[lint.otherLayer]
+		if layerConns == nil || otherLayerConns == nil {
+			continue
+		}
+		for _, isIngress := range []bool{true, false} {
+			peersConns := layerConns.EgressAllowedConns
+			if isIngress {
+				peersConns = layerConns.IngressAllowedConns
+			}
+			for peer := range peersConns {
+				src, dst := node, peer
+				if isIngress {
+					src, dst = peer, node
+				}
+				conn := nodesConn.GetPerLayerConnectivity(lint.layer, src, dst, isIngress)
+				otherConn := nodesConn.GetPerLayerConnectivity(lint.otherLayer, src, dst, isIngress)
+				if blockedByOther {
+					conn = conn.Subtract(otherConn)
+				} else {
+					conn = conn.Intersect(otherConn)
+				}
+				if conn.IsEmpty() {
+					continue
+				}
+				if err := addRulesOfConn(res, filterLayer, src, dst, conn, isIngress); err != nil {
+					return nil, err
+				}
+				if candidates != nil && containsAllRules(res, candidates) {
+					return res, nil
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// addRulesOfConn adds to rules the allow rules of the filter layer contributing to conn from src to dst
+func addRulesOfConn(rules map[layerRuleKey]bool, filterLayer vpcmodel.FilterTrafficResource, src, dst vpcmodel.Node,
+	conn *netset.TransportSet, isIngress bool) error {
+	allowRules, _, err := filterLayer.RulesInConnectivity(src, dst, conn, isIngress)
+	if err != nil {
+		return err
+	}
+	for _, rulesInTable := range allowRules {
+		for _, ruleIndex := range rulesInTable.Rules {
+			rules[layerRuleKey{filterIndex: rulesInTable.TableIndex, ruleIndex: ruleIndex, isIngress: isIngress}] = true
+		}
+	}
+	return nil
+}
+
+func containsAllRules(rules, subset map[layerRuleKey]bool) bool {
+	for key := range subset {
+		if !rules[key] {
+			return false
+		}
+	}
+	return true
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for ruleBlockedByOtherLayer
+//////////////////////////////////////////////////////////
+
+func (finding *ruleBlockedByOtherLayer) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *ruleBlockedByOtherLayer) Resources() []ResourceRef {
+	return []ResourceRef{NewFilterRef(finding.vpcResource, finding.rule.Filter)}
+}
+
+func (finding *ruleBlockedByOtherLayer) Rule() *vpcmodel.RuleOfFilter {
+	return &finding.rule
+}
+
+func (finding *ruleBlockedByOtherLayer) String() string {
+	rule := finding.rule
+	blockedBy := "blocked by the security groups of the endpoints in the subnets it is applied to"
+	if finding.otherLayer == vpcmodel.NaclLayer {
+		blockedBy = "denied by the network ACLs of the subnets of the endpoints it is applied to"
+	}
+	return fmt.Sprintf("In VPC %q, %s %q rule never takes effect, since all the traffic it allows is %s\n\tRule details: %s",
+		finding.vpcResource.Name(), rule.Filter.LayerName, rule.Filter.FilterName, blockedBy, strings.TrimSpace(rule.RuleDesc))
+}
+
+// for json:
+type ruleBlockedByOtherLayerJSON struct {
+	VpcName       string                `json:"vpc_name"`
+	Rule          vpcmodel.RuleOfFilter `json:"rule_details"`
+	BlockingLayer string                `json:"blocking_layer"`
+}
+
+func (finding *ruleBlockedByOtherLayer) ToJSON() any {
+	rule := vpcmodel.RuleOfFilter{Filter: finding.rule.Filter, RuleIndex: finding.rule.RuleIndex,
+		RuleDesc: finding.rule.RuleDesc}
+	return ruleBlockedByOtherLayerJSON{VpcName: finding.vpcResource.Name(), Rule: rule,
+		BlockingLayer: vpcmodel.FilterKindName(finding.otherLayer)}
+}
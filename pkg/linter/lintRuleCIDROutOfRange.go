@@ -8,6 +8,7 @@ package linter
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/np-guard/models/pkg/netset"
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
@@ -21,15 +22,20 @@ type ruleNonRelevantCIDR struct {
 	disjoint    bool // is the relevant src/dst block disjoint to the VPC address range;
 }
 
+// allowedCIDRsParam is a comma separated list of CIDRs outside of the VPC address space that rules may reference,
+// e.g. of an on-premises network
+const allowedCIDRsParam = "allowed-cidrs"
+
 // NACL rules that references CIDRs not in the vpc
 func newNACLRuleCIDROutOfRange(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
 			name:        name,
 			description: "Network ACL rules referencing CIDRs outside of the VPC address space",
 			enable:      true,
+			params:      map[string]string{allowedCIDRsParam: ""},
 		},
 		layer:          vpcmodel.NaclLayer,
 		checkForFilter: findRuleNonRelevantCIDR}
@@ -37,13 +43,14 @@ func newNACLRuleCIDROutOfRange(name string, configs map[string]*vpcmodel.VPCConf
 
 // SG rules that references CIDRs not in the vpc
 func newSGRuleCIDROutOfRange(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
 			name:        name,
 			description: "Security-group rules referencing CIDRs outside of the VPC address space",
 			enable:      true,
+			params:      map[string]string{allowedCIDRsParam: ""},
 		},
 		layer:          vpcmodel.SecurityGroupLayer,
 		checkForFilter: findRuleNonRelevantCIDR}
@@ -53,12 +60,20 @@ func newSGRuleCIDROutOfRange(name string, configs map[string]*vpcmodel.VPCConfig
 // functionality used by both SG and NACL lints
 ////////////////////////////////////////////////////////////////////////////////////////////
 
-func findRuleNonRelevantCIDR(configs map[string]*vpcmodel.VPCConfig, filterLayerName string) (res []finding, err error) {
+func findRuleNonRelevantCIDR(configs map[string]*vpcmodel.VPCConfig, filterLayerName string,
+	params map[string]string) (res []Finding, err error) {
+	allowedCIDRs := netset.NewIPBlock()
+	if params[allowedCIDRsParam] != "" {
+		allowedCIDRs, err = netset.IPBlockFromCidrList(strings.Split(strings.ReplaceAll(params[allowedCIDRsParam], " ", ""), ","))
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", allowedCIDRsParam, err)
+		}
+	}
 	for _, config := range configs {
 		if config.IsMultipleVPCsConfig {
 			continue // no use in executing lint on dummy vpcs
 		}
-		vpcAddressRange := config.VPC.AddressRange()
+		vpcAddressRange := config.VPC.AddressRange().Union(allowedCIDRs)
 		filterLayer := config.GetFilterTrafficResourceOfKind(filterLayerName)
 		rules, err := filterLayer.GetRules()
 		if err != nil {
@@ -85,11 +100,19 @@ func findRuleNonRelevantCIDR(configs map[string]*vpcmodel.VPCConfig, filterLayer
 // finding interface implementation for ruleNonRelevantCIDR
 //////////////////////////////////////////////////////////
 
-func (finding *ruleNonRelevantCIDR) vpc() []vpcmodel.VPCResourceIntf {
+func (finding *ruleNonRelevantCIDR) VPC() []vpcmodel.VPCResourceIntf {
 	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
 }
 
-func (finding *ruleNonRelevantCIDR) string() string {
+func (finding *ruleNonRelevantCIDR) Resources() []ResourceRef {
+	return []ResourceRef{NewFilterRef(finding.vpcResource, finding.rule.Filter)}
+}
+
+func (finding *ruleNonRelevantCIDR) Rule() *vpcmodel.RuleOfFilter {
+	return &finding.rule
+}
+
+func (finding *ruleNonRelevantCIDR) String() string {
 	rule := finding.rule
 	strPrefix := fmt.Sprintf("In VPC %q, %s %q ", finding.vpcResource.Name(), finding.rule.Filter.LayerName,
 		rule.Filter.FilterName)
@@ -110,11 +133,11 @@ type rulesNonRelevantCIDRJSON struct {
 	VpcAddressRange string                `json:"vpc_address_range"`
 }
 
-func (finding *ruleNonRelevantCIDR) toJSON() any {
+func (finding *ruleNonRelevantCIDR) ToJSON() any {
 	rule := finding.rule
 	table := vpcmodel.Filter{LayerName: rule.Filter.LayerName,
 		FilterName: rule.Filter.FilterName}
-	res := rulesNonRelevantCIDRJSON{VpcName: finding.vpc()[0].Name(), Rule: vpcmodel.RuleOfFilter{Filter: table,
+	res := rulesNonRelevantCIDRJSON{VpcName: finding.VPC()[0].Name(), Rule: vpcmodel.RuleOfFilter{Filter: table,
 		RuleIndex: rule.RuleIndex, RuleDesc: rule.RuleDesc},
 		VpcAddressRange: finding.vpcResource.AddressRange().String()}
 	return res
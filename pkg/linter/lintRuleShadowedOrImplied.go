@@ -8,6 +8,7 @@ package linter
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"strings"
@@ -29,7 +30,7 @@ type ruleRedundant struct {
 
 // NACL rules that are shadowed by higher priority rules
 func newNACLRuleShadowed(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -43,7 +44,7 @@ func newNACLRuleShadowed(name string, configs map[string]*vpcmodel.VPCConfig,
 
 // SG rules that are implied by other rules
 func newSGRuleImplied(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -81,7 +82,7 @@ func newSGRuleImplied(name string, configs map[string]*vpcmodel.VPCConfig,
 //
 //nolint:gocyclo // better not split into two function
 func findRuleSyntacticRedundant(configs map[string]*vpcmodel.VPCConfig,
-	filterLayerName string) (res []finding, err error) {
+	filterLayerName string, _ map[string]string) (res []Finding, err error) {
 	for _, config := range configs {
 		if config.IsMultipleVPCsConfig {
 			continue // no use in executing lint on dummy vpcs
@@ -183,11 +184,19 @@ func getTablesRulesAndAtomicBlocks(config *vpcmodel.VPCConfig, filterLayerName s
 //// finding interface implementation for ruleRedundant
 ////////////////////////////////////////////////////////////
 
-func (finding *ruleRedundant) vpc() []vpcmodel.VPCResourceIntf {
+func (finding *ruleRedundant) VPC() []vpcmodel.VPCResourceIntf {
 	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
 }
 
-func (finding *ruleRedundant) string() string {
+func (finding *ruleRedundant) Resources() []ResourceRef {
+	return []ResourceRef{NewFilterRef(finding.vpcResource, finding.rule.Filter)}
+}
+
+func (finding *ruleRedundant) Rule() *vpcmodel.RuleOfFilter {
+	return &finding.rule
+}
+
+func (finding *ruleRedundant) String() string {
 	rule := finding.rule
 	strResPrefix := fmt.Sprintf("In VPC %q, %s %q rule is ",
 		finding.vpcResource.Name(), finding.rule.Filter.LayerName, rule.Filter.FilterName)
@@ -225,22 +234,21 @@ func (finding *ruleRedundant) string() string {
 
 // for json:
 type ruleRedundantJSON struct {
-	Rule         vpcmodel.RuleOfFilter   `json:"vpc_name"`
-	VpcName      string                  `json:"rule_details"`
+	Rule         vpcmodel.RuleOfFilter   `json:"rule_details"`
+	VpcName      string                  `json:"vpc_name"`
 	ContainRules []vpcmodel.RuleOfFilter `json:"containing_rules"` // rules because of which this rule is redundant to their description
 }
 
-func (finding *ruleRedundant) toJSON() any {
+func (finding *ruleRedundant) ToJSON() any {
 	rule := finding.rule
 	table := vpcmodel.Filter{LayerName: rule.Filter.LayerName,
 		FilterName: rule.Filter.FilterName}
 	containRules := make([]vpcmodel.RuleOfFilter, len(finding.containRules))
-	i := 0
-	for _, rule := range finding.containRules {
-		containRules[i] = *rule
-		i++
+	for i, ruleIndex := range slices.Sorted(maps.Keys(finding.containRules)) {
+		containRules[i] = vpcmodel.RuleOfFilter{Filter: table, RuleIndex: ruleIndex,
+			RuleDesc: finding.containRules[ruleIndex].RuleDesc}
 	}
-	res := ruleRedundantJSON{VpcName: finding.vpc()[0].Name(), Rule: vpcmodel.RuleOfFilter{Filter: table,
+	res := ruleRedundantJSON{VpcName: finding.VPC()[0].Name(), Rule: vpcmodel.RuleOfFilter{Filter: table,
 		RuleIndex: rule.RuleIndex, RuleDesc: rule.RuleDesc}, ContainRules: containRules}
 	return res
 }
@@ -22,7 +22,7 @@ type splitRuleSubnet struct {
 
 // SG rules that are inconsistent w.r.t. subnets.
 func newSGSplitSubnet(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -36,7 +36,7 @@ func newSGSplitSubnet(name string, configs map[string]*vpcmodel.VPCConfig,
 
 // NACL rules that are inconsistent w.r.t. subnets.
 func newNACLSplitSubnet(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -52,7 +52,8 @@ func newNACLSplitSubnet(name string, configs map[string]*vpcmodel.VPCConfig,
 // functionality used by both filterRuleSplitSubnetLintNACL and filterRuleSplitSubnetLintSG
 ////////////////////////////////////////////////////////////////////////////////////////////
 
-func findSplitRulesSubnet(configs map[string]*vpcmodel.VPCConfig, filterLayerName string) (res []finding, err error) {
+func findSplitRulesSubnet(configs map[string]*vpcmodel.VPCConfig, filterLayerName string,
+	_ map[string]string) (res []Finding, err error) {
 	for _, config := range configs {
 		if config.IsMultipleVPCsConfig {
 			continue // no use in executing lint on dummy vpcs
@@ -93,11 +94,23 @@ func ruleSplitSubnet(subnet vpcmodel.Subnet, ruleIPBlocks [2]*netset.IPBlock) bo
 // finding interface implementation for splitRuleSubnet
 //////////////////////////////////////////////////////////
 
-func (finding *splitRuleSubnet) vpc() []vpcmodel.VPCResourceIntf {
+func (finding *splitRuleSubnet) VPC() []vpcmodel.VPCResourceIntf {
 	return []vpcmodel.VPCResourceIntf{finding.splitSubnets[0].VPC()}
 }
 
-func (finding *splitRuleSubnet) string() string {
+func (finding *splitRuleSubnet) Resources() []ResourceRef {
+	res := []ResourceRef{NewFilterRef(finding.splitSubnets[0].VPC(), finding.rule.Filter)}
+	for _, subnet := range finding.splitSubnets {
+		res = append(res, NewResourceRef(subnet))
+	}
+	return res
+}
+
+func (finding *splitRuleSubnet) Rule() *vpcmodel.RuleOfFilter {
+	return &finding.rule
+}
+
+func (finding *splitRuleSubnet) String() string {
 	rule := finding.rule
 	subnetsStrSlice := make([]string, len(finding.splitSubnets))
 	for i, subnet := range finding.splitSubnets {
@@ -110,7 +123,7 @@ func (finding *splitRuleSubnet) string() string {
 		subnetStr = "subnet " + subnetStr
 	}
 	return fmt.Sprintf("In VPC %q, %s %q rule splits %s.\n\tRule details: %s",
-		finding.vpc()[0].Name(), finding.rule.Filter.LayerName, rule.Filter.FilterName, subnetStr,
+		finding.VPC()[0].Name(), finding.rule.Filter.LayerName, rule.Filter.FilterName, subnetStr,
 		strings.ReplaceAll(rule.RuleDesc, "\n", ""))
 }
 
@@ -121,7 +134,7 @@ type splitRuleSubnetJSON struct {
 	SplitSubnets []subnetJSON          `json:"splitted_subnets"`
 }
 
-func (finding *splitRuleSubnet) toJSON() any {
+func (finding *splitRuleSubnet) ToJSON() any {
 	rule := finding.rule
 	splitSubnetsJSON := make([]subnetJSON, len(finding.splitSubnets))
 	for i, splitSubnet := range finding.splitSubnets {
@@ -129,7 +142,7 @@ func (finding *splitRuleSubnet) toJSON() any {
 	}
 	table := vpcmodel.Filter{LayerName: rule.Filter.LayerName,
 		FilterName: rule.Filter.FilterName}
-	res := splitRuleSubnetJSON{VpcName: finding.vpc()[0].Name(), Rule: vpcmodel.RuleOfFilter{Filter: table,
+	res := splitRuleSubnetJSON{VpcName: finding.VPC()[0].Name(), Rule: vpcmodel.RuleOfFilter{Filter: table,
 		RuleIndex: rule.RuleIndex, RuleDesc: rule.RuleDesc},
 		SplitSubnets: splitSubnetsJSON}
 	return res
@@ -0,0 +1,245 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+const (
+	// largeCIDRPrefixParam is the largest prefix length of a remote CIDR considered very large
+	largeCIDRPrefixParam   = "large-cidr-prefix"
+	defaultLargeCIDRPrefix = "8"
+	// widePortRangeParam is the smallest number of destination ports of a TCP or UDP range considered wide
+	widePortRangeParam   = "wide-port-range"
+	defaultWidePortRange = "1024"
+	// sensitiveTiersParam is a comma separated list of subnet names and CIDRs of the sensitive tiers, e.g. of the
+	// databases, whose endpoints should not be attached to overly permissive security groups
+	sensitiveTiersParam = "sensitive-tiers"
+
+	maxIPv4PrefixLength = 32
+)
+
+func newSGRuleOverlyPermissive(name string, configs map[string]*vpcmodel.VPCConfig,
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &filterLinter{
+		basicLinter: basicLinter{
+			configs:     configs,
+			name:        name,
+			description: "Overly permissive security group rules",
+			enable:      false,
+			params: map[string]string{largeCIDRPrefixParam: defaultLargeCIDRPrefix, widePortRangeParam: defaultWidePortRange,
+				sensitiveTiersParam: ""},
+		},
+		layer:          vpcmodel.SecurityGroupLayer,
+		checkForFilter: findOverlyPermissiveSGRules}
+}
+
+// an ingress rule allowing all protocols or wide port ranges from a very large remote, or an egress rule allowing
+// all traffic to a very large remote from a security group attached to endpoints of sensitive tiers
+type ruleOverlyPermissive struct {
+	rule        vpcmodel.RuleOfFilter
+	vpcResource vpcmodel.VPCResourceIntf
+	remote      *netset.IPBlock
+	attached    []vpcmodel.VPCResourceIntf // the endpoints the security group is attached to
+	sensitive   []vpcmodel.VPCResourceIntf // the attached endpoints in sensitive tiers
+}
+
+// the thresholds of the linter, parsed from its parameters
+type permissiveThresholds struct {
+	largeCIDRPrefix    int64
+	widePortRange      int64
+	sensitiveCIDRs     *netset.IPBlock
+	sensitiveSubnetSet map[string]bool
+}
+
+func parsePermissiveThresholds(params map[string]string) (*permissiveThresholds, error) {
+	largeCIDRPrefix, err := strconv.ParseInt(params[largeCIDRPrefixParam], 10, 64)
+	if err != nil || largeCIDRPrefix < 0 || largeCIDRPrefix > maxIPv4PrefixLength {
+		return nil, fmt.Errorf("parameter %s: illegal prefix length %q", largeCIDRPrefixParam, params[largeCIDRPrefixParam])
+	}
+	widePortRange, err := strconv.ParseInt(params[widePortRangeParam], 10, 64)
+	if err != nil || widePortRange < 1 {
+		return nil, fmt.Errorf("parameter %s: illegal number of ports %q", widePortRangeParam, params[widePortRangeParam])
+	}
+	res := &permissiveThresholds{largeCIDRPrefix: largeCIDRPrefix, widePortRange: widePortRange,
+		sensitiveCIDRs: netset.NewIPBlock(), sensitiveSubnetSet: map[string]bool{}}
+	for _, tier := range strings.Split(strings.ReplaceAll(params[sensitiveTiersParam], " ", ""), ",") {
+		if tier == "" {
+			continue
+		}
+		if cidr, errCidr := netset.IPBlockFromCidr(tier); errCidr == nil {
+			res.sensitiveCIDRs = res.sensitiveCIDRs.Union(cidr)
+		} else {
+			res.sensitiveSubnetSet[tier] = true
+		}
+	}
+	return res, nil
+}
+
+// isLargeRemote returns true if the remote contains a CIDR whose prefix length is at most the threshold
+func (t *permissiveThresholds) isLargeRemote(remote *netset.IPBlock) bool {
+	for _, cidr := range remote.SplitToCidrs() {
+		if prefixLength, err := cidr.PrefixLength(); err == nil && prefixLength <= t.largeCIDRPrefix {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWidePortRange returns true if the connection has a TCP or UDP range of at least threshold destination ports
+func (t *permissiveThresholds) hasWidePortRange(conn *netset.TransportSet) bool {
+	for _, partition := range conn.TCPUDPSet().Partitions() {
+		for _, dstPorts := range partition.S3.Intervals() {
+			if dstPorts.Size() >= t.widePortRange {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSensitive returns true if the endpoint is in one of the sensitive tiers
+func (t *permissiveThresholds) isSensitive(endpoint vpcmodel.VPCResourceIntf) bool {
+	internal, ok := endpoint.(vpcmodel.InternalNodeIntf)
+	if !ok {
+		return false
+	}
+	return internal.IPBlock().IsSubset(t.sensitiveCIDRs) || t.sensitiveSubnetSet[internal.Subnet().Name()]
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for filterLinter
+// ////////////////////////////////////////////////////////
+
+func findOverlyPermissiveSGRules(configs map[string]*vpcmodel.VPCConfig, filterLayerName string,
+	params map[string]string) (res []Finding, err error) {
+	thresholds, err := parsePermissiveThresholds(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if config.IsMultipleVPCsConfig {
+			continue // no use in executing lint on dummy vpcs
+		}
+		filterLayer := config.GetFilterTrafficResourceOfKind(filterLayerName)
+		rules, err := filterLayer.GetRules()
+		if err != nil {
+			return nil, err
+		}
+		filtersAttachedResources := filterLayer.GetFiltersAttachedResources()
+		for i := range rules {
+			remote := rules[i].DstCidr
+			if rules[i].IsIngress {
+				remote = rules[i].SrcCidr
+			}
+			if !thresholds.isLargeRemote(remote) {
+				continue
+			}
+			attached := filtersAttachedResources[rules[i].Filter]
+			sensitive := slices.DeleteFunc(slices.Clone(attached), func(endpoint vpcmodel.VPCResourceIntf) bool {
+				return !thresholds.isSensitive(endpoint)
+			})
+			isPermissive := rules[i].Conn.IsAll() || thresholds.hasWidePortRange(rules[i].Conn)
+			if !rules[i].IsIngress {
+				// egress to the internet is common practice, except for sensitive tiers
+				isPermissive = rules[i].Conn.IsAll() && len(sensitive) > 0
+			}
+			if isPermissive {
+				res = append(res, &ruleOverlyPermissive{rule: rules[i], vpcResource: config.VPC, remote: remote,
+					attached: attached, sensitive: sensitive})
+			}
+		}
+	}
+	return res, nil
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for ruleOverlyPermissive
+//////////////////////////////////////////////////////////
+
+func (finding *ruleOverlyPermissive) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *ruleOverlyPermissive) Resources() []ResourceRef {
+	res := []ResourceRef{NewFilterRef(finding.vpcResource, finding.rule.Filter)}
+	for _, endpoint := range finding.sensitive {
+		res = append(res, NewResourceRef(endpoint))
+	}
+	return res
+}
+
+func (finding *ruleOverlyPermissive) Rule() *vpcmodel.RuleOfFilter {
+	return &finding.rule
+}
+
+// Severity is error if the security group is attached to endpoints of sensitive tiers, info if it is not attached
+// to any endpoint and warning otherwise
+func (finding *ruleOverlyPermissive) Severity() Severity {
+	switch {
+	case len(finding.sensitive) > 0:
+		return SeverityError
+	case len(finding.attached) == 0:
+		return SeverityInfo
+	default:
+		return SeverityWarning
+	}
+}
+
+func endpointsNames(endpoints []vpcmodel.VPCResourceIntf) []string {
+	res := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		res[i] = endpoint.NameForAnalyzerOut(nil)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func (finding *ruleOverlyPermissive) String() string {
+	rule := finding.rule
+	direction := "from"
+	if !rule.IsIngress {
+		direction = "to"
+	}
+	conn := "all traffic"
+	if !rule.Conn.IsAll() {
+		conn = common.ShortString(rule.Conn)
+	}
+	res := fmt.Sprintf("In VPC %q, %s %q rule allows %s %s %s, and is applied to %d endpoint(s)",
+		finding.vpcResource.Name(), rule.Filter.LayerName, rule.Filter.FilterName, conn, direction,
+		finding.remote.ToCidrListString(), len(finding.attached))
+	if len(finding.sensitive) > 0 {
+		res += fmt.Sprintf(" including sensitive tiers endpoints %s", strings.Join(endpointsNames(finding.sensitive), ", "))
+	}
+	return res + fmt.Sprintf("\n\tRule details: %s", strings.TrimSpace(rule.RuleDesc))
+}
+
+// for json:
+type ruleOverlyPermissiveJSON struct {
+	VpcName   string                `json:"vpc_name"`
+	Rule      vpcmodel.RuleOfFilter `json:"rule_details"`
+	Remote    string                `json:"remote"`
+	Attached  []string              `json:"attached_endpoints"`
+	Sensitive []string              `json:"sensitive_endpoints"`
+}
+
+func (finding *ruleOverlyPermissive) ToJSON() any {
+	rule := vpcmodel.RuleOfFilter{Filter: finding.rule.Filter, RuleIndex: finding.rule.RuleIndex,
+		RuleDesc: finding.rule.RuleDesc}
+	return ruleOverlyPermissiveJSON{VpcName: finding.vpcResource.Name(), Rule: rule,
+		Remote: finding.remote.ToCidrListString(), Attached: endpointsNames(finding.attached),
+		Sensitive: endpointsNames(finding.sensitive)}
+}
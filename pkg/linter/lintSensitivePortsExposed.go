@@ -0,0 +1,269 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netp"
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+// sensitivePortsParam is a comma separated list of TCP ports and port ranges (e.g. 5432 or 8000-8080) that
+// should not be reachable from the Public Internet
+const sensitivePortsParam = "sensitive-ports"
+
+// ssh, telnet, smb, mssql, oracle, mysql, rdp, postgres, couchdb, redis, elasticsearch, memcached and mongodb
+const defaultSensitivePorts = "22,23,445,1433,1521,3306,3389,5432,5984,6379,9200,11211,27017"
+
+// sensitivePortsExposedLint: internal endpoints reachable from the Public Internet on sensitive ports
+type sensitivePortsExposedLint struct {
+	connectionLinter
+}
+
+func newSensitivePortsExposed(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &sensitivePortsExposedLint{
+		connectionLinter: connectionLinter{
+			basicLinter: basicLinter{
+				configs:     configs,
+				name:        name,
+				description: "Sensitive ports exposed to the Public Internet",
+				enable:      true,
+				params:      map[string]string{sensitivePortsParam: defaultSensitivePorts},
+			},
+			nodesConn: nodesConn}}
+}
+
+// an endpoint reachable from the Public Internet on sensitive ports, with the resources enabling it
+type sensitivePortsExposed struct {
+	vpcResource vpcmodel.VPCResourceIntf
+	endpoint    vpcmodel.VPCResourceIntf // a network interface, a reserved ip or a load balancer
+	sources     *netset.IPBlock          // the Public Internet addresses from which the endpoint is reachable
+	allSources  bool                     // whether the endpoint is reachable from the entire Public Internet
+	conn        *netset.TransportSet     // the connection on sensitive ports
+	routers     []vpcmodel.VPCResourceIntf
+	rules       []vpcmodel.RuleOfFilter
+}
+
+// sensitivePortsConn returns the TCP connection to the given comma separated list of ports and port ranges
+func sensitivePortsConn(ports string) (*netset.TransportSet, error) {
+	res := netset.NoTransports()
+	for _, portsRange := range strings.Split(strings.ReplaceAll(ports, " ", ""), ",") {
+		if portsRange == "" {
+			continue
+		}
+		minPortStr, maxPortStr, isRange := strings.Cut(portsRange, "-")
+		if !isRange {
+			maxPortStr = minPortStr
+		}
+		minPort, errMin := strconv.ParseInt(minPortStr, 10, 64)
+		maxPort, errMax := strconv.ParseInt(maxPortStr, 10, 64)
+		if errMin != nil || errMax != nil || minPort < netp.MinPort || maxPort > netp.MaxPort || minPort > maxPort {
+			return nil, fmt.Errorf("parameter %s: illegal port range %q", sensitivePortsParam, portsRange)
+		}
+		res = res.Union(netset.NewTCPTransport(netp.MinPort, netp.MaxPort, minPort, maxPort))
+	}
+	return res, nil
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for sensitivePortsExposedLint
+// ////////////////////////////////////////////////////////
+
+func (lint *sensitivePortsExposedLint) Check() error {
+	sensitiveConn, err := sensitivePortsConn(lint.params[sensitivePortsParam])
+	if err != nil {
+		return err
+	}
+	for uid, nodesConn := range lint.nodesConn {
+		config := lint.configs[uid]
+		if config.IsMultipleVPCsConfig {
+			continue // the Public Internet is reachable only through the routers of each vpc
+		}
+		findings := map[vpcmodel.VPCResourceIntf]*sensitivePortsExposed{}
+		for src, srcMap := range nodesConn.AllowedConnsCombinedResponsive {
+			srcNode, ok := src.(vpcmodel.Node)
+			if !ok || !srcNode.IsPublicInternet() {
+				continue
+			}
+			for dst, conn := range srcMap {
+				exposedConn := conn.AllConn().Intersect(sensitiveConn)
+				if exposedConn.IsEmpty() {
+					continue
+				}
+				if _, ok := findings[dst]; !ok {
+					findings[dst] = &sensitivePortsExposed{vpcResource: config.VPC, endpoint: dst,
+						sources: netset.NewIPBlock(), conn: netset.NoTransports()}
+				}
+				if errSource := findings[dst].addSource(config, srcNode, exposedConn); errSource != nil {
+					return errSource
+				}
+			}
+		}
+		allPublicInternet := publicInternetAddresses(config)
+		for _, thisFinding := range findings {
+			thisFinding.allSources = thisFinding.sources.Equal(allPublicInternet)
+			slices.SortFunc(thisFinding.rules, compareRules)
+			lint.addFinding(thisFinding)
+		}
+	}
+	return nil
+}
+
+// publicInternetAddresses returns the addresses of the Public Internet nodes of the given config
+func publicInternetAddresses(config *vpcmodel.VPCConfig) *netset.IPBlock {
+	return externalAddresses(config, true)
+}
+
+// externalAddresses returns the addresses of the Public Internet or the Service Network nodes of the given config
+func externalAddresses(config *vpcmodel.VPCConfig, publicInternet bool) *netset.IPBlock {
+	res := netset.NewIPBlock()
+	for _, node := range config.Nodes {
+		if node.IsExternal() && node.IsPublicInternet() == publicInternet {
+			res = res.Union(node.IPBlock())
+		}
+	}
+	return res
+}
+
+// addSource adds to the finding the connection on sensitive ports from the given Public Internet node,
+// along with the routers and rules enabling it; the traffic to a load balancer is enabled by the load balancer
+func (finding *sensitivePortsExposed) addSource(config *vpcmodel.VPCConfig, src vpcmodel.Node,
+	conn *netset.TransportSet) error {
+	finding.sources = finding.sources.Union(src.IPBlock())
+	finding.conn = finding.conn.Union(conn)
+	var dstNodes []vpcmodel.Node
+	lb, isLB := finding.endpoint.(vpcmodel.LoadBalancer)
+	if isLB {
+		finding.addRouter(lb)
+		dstNodes = lb.Nodes()
+	} else if node, ok := finding.endpoint.(vpcmodel.Node); ok {
+		dstNodes = []vpcmodel.Node{node}
+	}
+	for _, dstNode := range dstNodes {
+		router, rules, err := config.ConnectionEnablers(src, dstNode, conn)
+		if err != nil {
+			return err
+		}
+		// the routers of the private ips of a load balancer are represented by the load balancer
+		if router != nil && !isLB {
+			finding.addRouter(router)
+		}
+		for i := range rules {
+			if !slices.ContainsFunc(finding.rules, func(rule vpcmodel.RuleOfFilter) bool {
+				return rule.Filter == rules[i].Filter && rule.RuleIndex == rules[i].RuleIndex
+			}) {
+				finding.rules = append(finding.rules, rules[i])
+			}
+		}
+	}
+	return nil
+}
+
+// compareRules orders rules by their layer, table and index
+func compareRules(r1, r2 vpcmodel.RuleOfFilter) int {
+	if r1.Filter.LayerName != r2.Filter.LayerName {
+		return strings.Compare(r1.Filter.LayerName, r2.Filter.LayerName)
+	}
+	if r1.Filter.FilterName != r2.Filter.FilterName {
+		return strings.Compare(r1.Filter.FilterName, r2.Filter.FilterName)
+	}
+	return r1.RuleIndex - r2.RuleIndex
+}
+
+func (finding *sensitivePortsExposed) addRouter(router vpcmodel.VPCResourceIntf) {
+	if !slices.ContainsFunc(finding.routers, func(r vpcmodel.VPCResourceIntf) bool { return r.UID() == router.UID() }) {
+		finding.routers = append(finding.routers, router)
+	}
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for sensitivePortsExposed
+//////////////////////////////////////////////////////////
+
+func (finding *sensitivePortsExposed) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.vpcResource}
+}
+
+func (finding *sensitivePortsExposed) Resources() []ResourceRef {
+	res := []ResourceRef{NewResourceRef(finding.endpoint)}
+	for _, router := range finding.routers {
+		res = append(res, NewResourceRef(router))
+	}
+	for i := range finding.rules {
+		filterRef := NewFilterRef(finding.vpcResource, finding.rules[i].Filter)
+		if !slices.Contains(res, filterRef) {
+			res = append(res, filterRef)
+		}
+	}
+	return res
+}
+
+func (finding *sensitivePortsExposed) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *sensitivePortsExposed) sourcesStr() string {
+	if finding.allSources {
+		return "the Public Internet"
+	}
+	return "Public Internet addresses " + strings.Join(finding.sources.ListToPrint(), ",")
+}
+
+func (finding *sensitivePortsExposed) routersStr() []string {
+	res := make([]string, len(finding.routers))
+	for i, router := range finding.routers {
+		res[i] = fmt.Sprintf("%s %q", router.Kind(), router.Name())
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (finding *sensitivePortsExposed) String() string {
+	res := fmt.Sprintf("In VPC %q, %s is reachable from %s on sensitive ports %s", finding.vpcResource.Name(),
+		finding.endpoint.NameForAnalyzerOut(nil), finding.sourcesStr(), common.ShortString(finding.conn))
+	if len(finding.routers) > 0 {
+		res += " through " + strings.Join(finding.routersStr(), ", ")
+	}
+	if len(finding.rules) > 0 {
+		res += "\n\tAllowing rules:"
+		for i := range finding.rules {
+			res += fmt.Sprintf("\n\t\t%s %q: %s", finding.rules[i].Filter.LayerName, finding.rules[i].Filter.FilterName,
+				strings.TrimSpace(finding.rules[i].RuleDesc))
+		}
+	}
+	return res
+}
+
+// for json:
+type sensitivePortsExposedJSON struct {
+	VpcName  string                  `json:"vpc_name"`
+	Endpoint string                  `json:"endpoint"`
+	Sources  []string                `json:"sources"`
+	Conn     netset.Details          `json:"exposed_connection"`
+	Routers  []string                `json:"routers"`
+	Rules    []vpcmodel.RuleOfFilter `json:"allowing_rules"`
+}
+
+func (finding *sensitivePortsExposed) ToJSON() any {
+	rules := make([]vpcmodel.RuleOfFilter, len(finding.rules))
+	for i := range finding.rules {
+		rules[i] = vpcmodel.RuleOfFilter{Filter: finding.rules[i].Filter, RuleIndex: finding.rules[i].RuleIndex,
+			RuleDesc: finding.rules[i].RuleDesc}
+	}
+	return sensitivePortsExposedJSON{VpcName: finding.vpcResource.Name(), Endpoint: finding.endpoint.NameForAnalyzerOut(nil),
+		Sources: finding.sources.ListToPrint(), Conn: netset.ToJSON(finding.conn), Routers: finding.routersStr(),
+		Rules: rules}
+}
@@ -19,7 +19,7 @@ type overlappingSubnetsLint struct {
 }
 
 func newSubnetCIDROverlap(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &overlappingSubnetsLint{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -39,7 +39,7 @@ type overlapSubnets struct {
 // lint interface implementation for overlapSubnets
 // ////////////////////////////////////////////////////////
 
-func (lint *overlappingSubnetsLint) check() error {
+func (lint *overlappingSubnetsLint) Check() error {
 	allSubnets := []vpcmodel.Subnet{}
 	for _, config := range lint.configs {
 		if config.IsMultipleVPCsConfig {
@@ -68,11 +68,19 @@ func (lint *overlappingSubnetsLint) check() error {
 // finding interface implementation for overlapSubnets
 //////////////////////////////////////////////////////////
 
-func (finding *overlapSubnets) vpc() []vpcmodel.VPCResourceIntf {
+func (finding *overlapSubnets) VPC() []vpcmodel.VPCResourceIntf {
 	return []vpcmodel.VPCResourceIntf{finding.overlapSubnets[0].VPC(), finding.overlapSubnets[1].VPC()}
 }
 
-func (finding *overlapSubnets) string() string {
+func (finding *overlapSubnets) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.overlapSubnets[0]), NewResourceRef(finding.overlapSubnets[1])}
+}
+
+func (finding *overlapSubnets) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *overlapSubnets) String() string {
 	subnet1 := finding.overlapSubnets[0]
 	subnet2 := finding.overlapSubnets[1]
 	return fmt.Sprintf("VPC %q's %s and VPC %q's %s overlap", subnet1.VPC().Name(), subnetStr(subnet1),
@@ -95,7 +103,7 @@ type subnetJSON struct {
 	VpcName string `json:"vpc_name,omitempty"`
 }
 
-func (finding *overlapSubnets) toJSON() any {
+func (finding *overlapSubnets) ToJSON() any {
 	overlapsSubnetsJSON := make([]subnetJSON, 2)
 	for i := range finding.overlapSubnets {
 		overlapsSubnetsJSON[i] = subnetJSON{Name: finding.overlapSubnets[i].Name(),
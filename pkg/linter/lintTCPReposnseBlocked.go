@@ -22,7 +22,7 @@ type blockedTCPResponseLint struct {
 }
 
 func newTCPResponseBlocked(name string, configs map[string]*vpcmodel.VPCConfig,
-	nodesConn map[string]*vpcmodel.VPCConnectivity) linter {
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &blockedTCPResponseLint{
 		connectionLinter: connectionLinter{
 			basicLinter: basicLinter{
@@ -44,7 +44,7 @@ type blockedTCPResponseConn struct {
 // /////////////////////////////////////////////////////////
 // lint interface implementation for overlapSubnets
 // ////////////////////////////////////////////////////////
-func (lint *blockedTCPResponseLint) check() error {
+func (lint *blockedTCPResponseLint) Check() error {
 	for _, nodesConn := range lint.nodesConn {
 		for _, line := range nodesConn.GroupedConnectivity.GroupedLines {
 			tcpRspDisable := line.CommonProperties.Conn.TCPRspDisable
@@ -60,7 +60,7 @@ func (lint *blockedTCPResponseLint) check() error {
 // finding interface implementation for overlapSubnets
 //////////////////////////////////////////////////////////
 
-func (finding *blockedTCPResponseConn) vpc() []vpcmodel.VPCResourceIntf {
+func (finding *blockedTCPResponseConn) VPC() []vpcmodel.VPCResourceIntf {
 	return []vpcmodel.VPCResourceIntf{getVPCFromEndpointElem(finding.src), getVPCFromEndpointElem(finding.dst)}
 }
 
@@ -72,7 +72,21 @@ func getVPCFromEndpointElem(ep vpcmodel.EndpointElem) vpcmodel.VPCResourceIntf {
 	return nil
 }
 
-func (finding *blockedTCPResponseConn) string() string {
+func (finding *blockedTCPResponseConn) Resources() []ResourceRef {
+	res := []ResourceRef{}
+	for _, ep := range []vpcmodel.EndpointElem{finding.src, finding.dst} {
+		if vpcResource, ok := ep.(vpcmodel.VPCResourceIntf); ok {
+			res = append(res, NewResourceRef(vpcResource))
+		}
+	}
+	return res
+}
+
+func (finding *blockedTCPResponseConn) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *blockedTCPResponseConn) String() string {
 	vpcSrcName := finding.getVpcName(0)
 	vpcDstName := finding.getVpcName(1)
 	srcToDstStr := fmt.Sprintf("from \"%v%s\" to \"%v%s\"",
@@ -83,8 +97,8 @@ func (finding *blockedTCPResponseConn) string() string {
 }
 
 func (finding *blockedTCPResponseConn) getVpcName(i int) string {
-	if finding.vpc()[i] != nil { // nil if external address
-		return finding.vpc()[i].Name() + deliminator
+	if finding.VPC()[i] != nil { // nil if external address
+		return finding.VPC()[i].Name() + deliminator
 	}
 	return ""
 }
@@ -96,10 +110,8 @@ type blockedTCPResponseConnJSON struct {
 	TCPRspDisable netset.Details `json:"tcp_non_responsive"`
 }
 
-func (finding *blockedTCPResponseConn) toJSON() any {
-	vpcSrcName := finding.vpc()[0].Name()
-	vpcDstName := finding.vpc()[1].Name()
-	res := blockedTCPResponseConnJSON{Src: vpcSrcName + deliminator + finding.src.NameForAnalyzerOut(nil),
-		Dst: vpcDstName + deliminator + finding.dst.NameForAnalyzerOut(nil), TCPRspDisable: netset.ToJSON(finding.tcpRspDisable)}
+func (finding *blockedTCPResponseConn) ToJSON() any {
+	res := blockedTCPResponseConnJSON{Src: finding.getVpcName(0) + finding.src.NameForAnalyzerOut(nil),
+		Dst: finding.getVpcName(1) + finding.dst.NameForAnalyzerOut(nil), TCPRspDisable: netset.ToJSON(finding.tcpRspDisable)}
 	return res
 }
@@ -0,0 +1,248 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
+)
+
+func newPrefixFilterUnmatched(name string, configs map[string]*vpcmodel.VPCConfig,
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &transitConnLinter{
+		basicLinter: basicLinter{
+			configs:     configs,
+			name:        name,
+			description: "Transit gateway prefix filters not matching any address prefix of the VPC",
+			enable:      true,
+		},
+		checkForConn: findUnmatchedPrefixFilters}
+}
+
+func newPrefixFilterShadowed(name string, configs map[string]*vpcmodel.VPCConfig,
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &transitConnLinter{
+		basicLinter: basicLinter{
+			configs:     configs,
+			name:        name,
+			description: "Transit gateway prefix filters shadowed by earlier filters",
+			enable:      true,
+		},
+		checkForConn: findShadowedPrefixFilters}
+}
+
+func newSubnetDefaultDenied(name string, configs map[string]*vpcmodel.VPCConfig,
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
+	return &transitConnLinter{
+		basicLinter: basicLinter{
+			configs:     configs,
+			name:        name,
+			description: "Subnets not reachable through a transit gateway due to a default deny prefix filter",
+			enable:      true,
+		},
+		checkForConn: findDefaultDeniedSubnets}
+}
+
+// a prefix filter of a transit connection
+type prefixFilterInConn struct {
+	router      vpcmodel.TransitConnectionsRouter
+	transitConn *vpcmodel.TransitConnection
+	filterIndex int
+}
+
+// a prefix filter that is not the first filter matching any of the address prefixes of the VPC
+type prefixFilterUnmatched struct {
+	prefixFilterInConn
+}
+
+// a prefix filter all of whose matched routes are matched by an earlier filter
+type prefixFilterShadowed struct {
+	prefixFilterInConn
+	shadowing []int // the indexes of the earlier filters that match all the routes this filter matches
+}
+
+// a subnet of the VPC not reachable through the transit gateway, since its address prefix is not advertised
+// by the default deny action of the transit connection
+type subnetDefaultDenied struct {
+	prefixFilterInConn
+	addressPrefix *vpcmodel.AddressPrefixFilter
+	subnet        vpcmodel.Subnet
+}
+
+// shadowingFilters returns the indexes of the filters preceding the filter of the given index that cover it
+func shadowingFilters(transitConn *vpcmodel.TransitConnection, filterIndex int) []int {
+	res := []int{}
+	for i := range filterIndex {
+		if transitConn.PrefixFilters[i].Covers(transitConn.PrefixFilters[filterIndex]) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// /////////////////////////////////////////////////////////
+// lint interface implementation for transitConnLinter
+// ////////////////////////////////////////////////////////
+
+// findUnmatchedPrefixFilters does not report shadowed filters, which are reported by tgw-prefix-filter-shadowed
+func findUnmatchedPrefixFilters(_ map[string]*vpcmodel.VPCConfig, router vpcmodel.TransitConnectionsRouter,
+	transitConn *vpcmodel.TransitConnection) (res []Finding) {
+	matched := map[int]bool{}
+	for _, ap := range transitConn.AddressPrefixes {
+		matched[ap.FilterIndex] = true
+	}
+	for filterIndex := range transitConn.PrefixFilters {
+		if !matched[filterIndex] && len(shadowingFilters(transitConn, filterIndex)) == 0 {
+			res = append(res, &prefixFilterUnmatched{prefixFilterInConn{router: router, transitConn: transitConn,
+				filterIndex: filterIndex}})
+		}
+	}
+	return res
+}
+
+func findShadowedPrefixFilters(_ map[string]*vpcmodel.VPCConfig, router vpcmodel.TransitConnectionsRouter,
+	transitConn *vpcmodel.TransitConnection) (res []Finding) {
+	for filterIndex := range transitConn.PrefixFilters {
+		if shadowing := shadowingFilters(transitConn, filterIndex); len(shadowing) > 0 {
+			res = append(res, &prefixFilterShadowed{prefixFilterInConn: prefixFilterInConn{router: router,
+				transitConn: transitConn, filterIndex: filterIndex}, shadowing: shadowing})
+		}
+	}
+	return res
+}
+
+func findDefaultDeniedSubnets(configs map[string]*vpcmodel.VPCConfig, router vpcmodel.TransitConnectionsRouter,
+	transitConn *vpcmodel.TransitConnection) (res []Finding) {
+	config, ok := configs[transitConn.VPC.UID()]
+	if !ok || transitConn.DefaultPermit {
+		return nil
+	}
+	for _, ap := range transitConn.AddressPrefixes {
+		if ap.FilterIndex != vpcmodel.DefaultPrefixFilter {
+			continue // an address prefix denied by an explicit filter is intentionally not advertised
+		}
+		for _, subnet := range config.Subnets {
+			if subnet.AddressRange().IsSubset(ap.AddressPrefix) {
+				res = append(res, &subnetDefaultDenied{prefixFilterInConn: prefixFilterInConn{router: router,
+					transitConn: transitConn, filterIndex: vpcmodel.DefaultPrefixFilter}, addressPrefix: ap, subnet: subnet})
+			}
+		}
+	}
+	return res
+}
+
+//////////////////////////////////////////////////////////////////
+// finding interface implementation shared by prefix filter findings
+//////////////////////////////////////////////////////////////////
+
+func (finding *prefixFilterInConn) VPC() []vpcmodel.VPCResourceIntf {
+	return []vpcmodel.VPCResourceIntf{finding.transitConn.VPC}
+}
+
+func (finding *prefixFilterInConn) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.router)}
+}
+
+func (finding *prefixFilterInConn) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+// the prefix filter with its transit connection and transit gateway
+func (finding *prefixFilterInConn) filterStr() string {
+	return fmt.Sprintf("prefix filter %d of transit connection %q of transit gateway %q", finding.filterIndex,
+		finding.transitConn.Name, finding.router.Name())
+}
+
+func (finding *prefixFilterInConn) filterDescription(filterIndex int) string {
+	return finding.transitConn.PrefixFilters[filterIndex].Description
+}
+
+// for json:
+type prefixFilterJSON struct {
+	Index       int    `json:"index"`
+	Description string `json:"description"`
+}
+
+type prefixFilterInConnJSON struct {
+	VpcName           string             `json:"vpc_name"`
+	TransitGateway    string             `json:"transit_gateway"`
+	TransitConnection string             `json:"transit_connection"`
+	Filter            prefixFilterJSON   `json:"prefix_filter"`
+	ShadowingFilters  []prefixFilterJSON `json:"shadowing_filters,omitempty"`
+	AddressPrefix     string             `json:"address_prefix,omitempty"`
+	Subnet            string             `json:"subnet,omitempty"`
+}
+
+func (finding *prefixFilterInConn) toJSON() *prefixFilterInConnJSON {
+	res := &prefixFilterInConnJSON{VpcName: finding.transitConn.VPC.Name(), TransitGateway: finding.router.Name(),
+		TransitConnection: finding.transitConn.Name, Filter: prefixFilterJSON{Index: finding.filterIndex}}
+	if finding.filterIndex != vpcmodel.DefaultPrefixFilter {
+		res.Filter.Description = finding.filterDescription(finding.filterIndex)
+	}
+	return res
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for prefixFilterUnmatched
+//////////////////////////////////////////////////////////
+
+func (finding *prefixFilterUnmatched) String() string {
+	return fmt.Sprintf("In VPC %q, %s does not match any address prefix of the VPC\n\tFilter details: %s",
+		finding.transitConn.VPC.Name(), finding.filterStr(), finding.filterDescription(finding.filterIndex))
+}
+
+func (finding *prefixFilterUnmatched) ToJSON() any {
+	return finding.toJSON()
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for prefixFilterShadowed
+//////////////////////////////////////////////////////////
+
+func (finding *prefixFilterShadowed) String() string {
+	shadowing := make([]string, len(finding.shadowing))
+	for i, filterIndex := range finding.shadowing {
+		shadowing[i] = finding.filterDescription(filterIndex)
+	}
+	return fmt.Sprintf("In VPC %q, %s is shadowed by earlier filters\n\tFilter details: %s\n\tShadowing filters:\n\t\t%s",
+		finding.transitConn.VPC.Name(), finding.filterStr(), finding.filterDescription(finding.filterIndex),
+		strings.Join(shadowing, "\n\t\t"))
+}
+
+func (finding *prefixFilterShadowed) ToJSON() any {
+	res := finding.toJSON()
+	for _, filterIndex := range finding.shadowing {
+		res.ShadowingFilters = append(res.ShadowingFilters,
+			prefixFilterJSON{Index: filterIndex, Description: finding.filterDescription(filterIndex)})
+	}
+	return res
+}
+
+///////////////////////////////////////////////////////////
+// finding interface implementation for subnetDefaultDenied
+//////////////////////////////////////////////////////////
+
+func (finding *subnetDefaultDenied) Resources() []ResourceRef {
+	return []ResourceRef{NewResourceRef(finding.router), NewResourceRef(finding.subnet)}
+}
+
+func (finding *subnetDefaultDenied) String() string {
+	return fmt.Sprintf("In VPC %q, subnet %q is not reachable through transit gateway %q, since its address prefix %s "+
+		"is denied by the default prefix filter of transit connection %q", finding.transitConn.VPC.Name(),
+		finding.subnet.Name(), finding.router.Name(), finding.addressPrefix.AddressPrefix.ToCidrListString(),
+		finding.transitConn.Name)
+}
+
+func (finding *subnetDefaultDenied) ToJSON() any {
+	res := finding.toJSON()
+	res.Filter.Description = "default prefix, action: deny"
+	res.AddressPrefix = finding.addressPrefix.AddressPrefix.ToCidrListString()
+	res.Subnet = finding.subnet.Name()
+	return res
+}
@@ -13,7 +13,7 @@ import (
 )
 
 func newNACLUnattachedLint(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -26,7 +26,7 @@ func newNACLUnattachedLint(name string, configs map[string]*vpcmodel.VPCConfig,
 }
 
 func newSGUnattachedLint(name string, configs map[string]*vpcmodel.VPCConfig,
-	_ map[string]*vpcmodel.VPCConnectivity) linter {
+	_ map[string]*vpcmodel.VPCConnectivity) Linter {
 	return &filterLinter{
 		basicLinter: basicLinter{
 			configs:     configs,
@@ -50,7 +50,8 @@ type nonConnectedTable struct {
 // ////////////////////////////////////////////////////////
 
 // todo: followup https://github.com/np-guard/vpc-network-config-analyzer/issues/718
-func findUnattachedTables(configs map[string]*vpcmodel.VPCConfig, filterLayerName string) (res []finding, err error) {
+func findUnattachedTables(configs map[string]*vpcmodel.VPCConfig, filterLayerName string,
+	_ map[string]string) (res []Finding, err error) {
 	for _, config := range configs {
 		if config.IsMultipleVPCsConfig {
 			continue // no use in executing this lint on dummy vpcs
@@ -71,22 +72,30 @@ func findUnattachedTables(configs map[string]*vpcmodel.VPCConfig, filterLayerNam
 // finding interface implementation for nonConnectedTable
 //////////////////////////////////////////////////////////
 
-func (finding *nonConnectedTable) vpc() []vpcmodel.VPCResourceIntf {
+func (finding *nonConnectedTable) VPC() []vpcmodel.VPCResourceIntf {
 	return []vpcmodel.VPCResourceIntf{finding.vpcOfTable}
 }
 
-func (finding *nonConnectedTable) string() string {
-	return fmt.Sprintf("In VPC %q, %s %q has no resources attached to it", finding.vpc()[0].Name(),
+func (finding *nonConnectedTable) Resources() []ResourceRef {
+	return []ResourceRef{NewFilterRef(finding.vpcOfTable, finding.table)}
+}
+
+func (finding *nonConnectedTable) Rule() *vpcmodel.RuleOfFilter {
+	return nil
+}
+
+func (finding *nonConnectedTable) String() string {
+	return fmt.Sprintf("In VPC %q, %s %q has no resources attached to it", finding.VPC()[0].Name(),
 		finding.layerName, finding.table.FilterName)
 }
 
 type nonConnectedTableJSON struct {
-	vpcName   string
-	layerName string
-	tableName string
+	VpcName   string `json:"vpc_name"`
+	LayerName string `json:"layer_name"`
+	TableName string `json:"table_name"`
 }
 
-func (finding *nonConnectedTable) toJSON() any {
-	return nonConnectedTableJSON{vpcName: finding.vpc()[0].Name(),
-		layerName: finding.layerName, tableName: finding.table.FilterName}
+func (finding *nonConnectedTable) ToJSON() any {
+	return nonConnectedTableJSON{VpcName: finding.VPC()[0].Name(),
+		LayerName: finding.layerName, TableName: finding.table.FilterName}
 }
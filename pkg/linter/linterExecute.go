@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package linter
 
 import (
+	"encoding/json"
 	"fmt"
 	"maps"
 	"slices"
@@ -16,14 +17,19 @@ import (
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
 )
 
-const delimBetweenLintsChars = 200
+const (
+	delimBetweenLintsChars = 200
+	numFindingToPrint      = 3
+)
 
-// linterGenerator is a function that generate a linter.
+// LinterGenerator is a function that generates a linter with the given name.
 // we need a list of generators, and their names, so we holds a map from a linter name to its generator.
-// when creating a new linter, this is the list of linters that should be updated:
-type linterGenerator func(string, map[string]*vpcmodel.VPCConfig, map[string]*vpcmodel.VPCConnectivity) linter
+// when creating a new built-in linter, this is the list of linters that should be updated;
+// custom linters are added to it with RegisterLinter
+type LinterGenerator func(name string, configs map[string]*vpcmodel.VPCConfig,
+	nodesConn map[string]*vpcmodel.VPCConnectivity) Linter
 
-var linterGenerators = map[string]linterGenerator{
+var linterGenerators = map[string]LinterGenerator{
 	"nacl-split-subnet":           newNACLSplitSubnet,
 	"sg-split-subnet":             newSGSplitSubnet,
 	"subnet-cidr-overlap":         newSubnetCIDROverlap,
@@ -34,21 +40,62 @@ var linterGenerators = map[string]linterGenerator{
 	"tcp-response-blocked":        newTCPResponseBlocked,
 	"nacl-rule-shadowed":          newNACLRuleShadowed,
 	"sg-rule-implied":             newSGRuleImplied,
+	"sensitive-ports-exposed":     newSensitivePortsExposed,
+	"lb-member-unreachable":       newLBMemberUnreachable,
+	"route-shadowed":              newRouteShadowed,
+	"route-next-hop-unknown":      newRouteNextHopUnknown,
+	"route-drop-allowed-traffic":  newRouteDropAllowed,
+	"route-next-hop-blocked":      newRouteNextHopBlocked,
+	"tgw-prefix-filter-unmatched": newPrefixFilterUnmatched,
+	"tgw-prefix-filter-shadowed":  newPrefixFilterShadowed,
+	"tgw-subnet-default-denied":   newSubnetDefaultDenied,
+	"isolated-endpoints":          newIsolatedEndpoints,
+	"udp-icmp-response-blocked":   newNonTCPResponseBlocked,
+	"sg-rule-overly-permissive":   newSGRuleOverlyPermissive,
+	"nacl-rule-blocked-by-sg":     newNACLRuleBlockedBySG,
+	"sg-rule-denied-by-nacl":      newSGRuleDeniedByNACL,
+}
+
+// RegisterLinter adds a custom linter, which is then enabled, disabled, configured and reported as the built-in
+// linters are; should be called before the lint command is created, e.g. from an init function of a wrapper binary
+func RegisterLinter(name string, generator LinterGenerator) error {
+	if name == "" || strings.ContainsAny(name, ", ") {
+		return fmt.Errorf("illegal linter name %q", name)
+	}
+	if generator == nil {
+		return fmt.Errorf("linter %s has no generator", name)
+	}
+	if IsValidLintersNames(name) {
+		return fmt.Errorf("linter %s is already registered", name)
+	}
+	linterGenerators[name] = generator
+	return nil
 }
 
 func ValidLintersNames() string {
-	return strings.Join(slices.Collect(maps.Keys(linterGenerators)), ",")
+	return strings.Join(slices.Sorted(maps.Keys(linterGenerators)), ",")
 }
 func IsValidLintersNames(name string) bool {
 	_, ok := linterGenerators[name]
 	return ok
 }
+
+// configuredLinter is a linter along with its configuration and results in a single lint execution
+type configuredLinter struct {
+	Linter
+	enabled      bool
+	severity     Severity
+	suppressions []*Suppression
+	findings     []Finding // the findings that are not suppressed
+	suppressed   []Finding
+}
+
+type Linters []*configuredLinter
+
 func generateLinters(configs map[string]*vpcmodel.VPCConfig, nodeConn map[string]*vpcmodel.VPCConnectivity) Linters {
-	res := make([]linter, len(linterGenerators))
-	i := 0
-	for name, generator := range linterGenerators {
-		res[i] = generator(name, configs, nodeConn)
-		i++
+	res := make(Linters, 0, len(linterGenerators))
+	for _, name := range slices.Sorted(maps.Keys(linterGenerators)) {
+		res = append(res, &configuredLinter{Linter: linterGenerators[name](name, configs, nodeConn)})
 	}
 	return res
 }
@@ -66,21 +113,37 @@ func computeConnectivity(configs map[string]*vpcmodel.VPCConfig) (map[string]*vp
 	return nodesConn, nil
 }
 
-// LinterExecute performs the lint analysis and then prints the string result; should be redundant once lint is
-// integrated in the general flow
+// LinterExecute performs the lint analysis and then prints the result, as text or as json by outFormat, and
+// writes it to outFile if given; should be redundant once lint is integrated in the general flow.
+// Returns a FindingsError if there are findings with severity at least the fail-on severity of lintConfig
 func LinterExecute(configs map[string]*vpcmodel.VPCConfig, printAllFindings bool,
-	enableList, disableList []string) (resString string, err error) {
-	linters, err := linterAnalysis(configs, enableList, disableList)
+	enableList, disableList []string, lintConfig *LintConfig,
+	outFormat vpcmodel.OutFormat, outFile string) (resString string, err error) {
+	linters, err := linterAnalysis(configs, enableList, disableList, lintConfig)
 	if err != nil {
 		return "", err
 	}
-	resString = linters.String(printAllFindings)
+	if outFormat == vpcmodel.JSON {
+		resString, err = linters.JSONString()
+		if err != nil {
+			return "", err
+		}
+	} else {
+		resString = linters.String(printAllFindings)
+	}
 	fmt.Println(resString)
+	if _, err = vpcmodel.WriteToFile(resString, outFile); err != nil {
+		return resString, err
+	}
+	if severity, found := linters.highestSeverity(); found && severity.rank() >= lintConfig.failOn().rank() {
+		return resString, &FindingsError{Severity: severity}
+	}
 	return resString, nil
 }
 
 // linterAnalysis executes linters one by one and collects their results
-func linterAnalysis(configs map[string]*vpcmodel.VPCConfig, enableList, disableList []string) (linters Linters, err error) {
+func linterAnalysis(configs map[string]*vpcmodel.VPCConfig, enableList, disableList []string,
+	lintConfig *LintConfig) (linters Linters, err error) {
 	nodesConn, err := computeConnectivity(configs)
 	if err != nil {
 		return nil, err
@@ -88,28 +151,141 @@ func linterAnalysis(configs map[string]*vpcmodel.VPCConfig, enableList, disableL
 
 	linters = generateLinters(configs, nodesConn)
 	for _, thisLinter := range linters {
-		name := thisLinter.lintName()
-		enable := thisLinter.enableByDefault()
+		name := thisLinter.Name()
+		enable := thisLinter.EnableByDefault()
 		enable = enable || slices.Contains(enableList, name)
 		enable = enable && !slices.Contains(disableList, name)
 		if !enable {
 			continue
 		}
-		err := thisLinter.check()
-		if err != nil {
+		thisLinter.enabled = true
+		if err = thisLinter.configure(lintConfig.linterConfig(name)); err != nil {
 			return nil, err
 		}
+		if err = thisLinter.Check(); err != nil {
+			return nil, fmt.Errorf("linter %s: %w", name, err)
+		}
+		thisLinter.suppress()
 	}
 	return linters, nil
 }
 
+// configure sets the severity, parameters and suppressions of the linter
+func (lint *configuredLinter) configure(config *LinterConfig) error {
+	if config == nil {
+		return nil
+	}
+	lint.severity = config.Severity
+	params := lint.Params()
+	for param, value := range config.Params {
+		if _, ok := params[param]; !ok {
+			return fmt.Errorf("linter %s does not have parameter %s", lint.Name(), param)
+		}
+		params[param] = value
+	}
+	lint.suppressions = config.Suppressions
+	return nil
+}
+
+// suppress splits the findings of the linter by whether they match a suppression
+func (lint *configuredLinter) suppress() {
+	for _, thisFinding := range lint.Findings() {
+		if slices.ContainsFunc(lint.suppressions, func(s *Suppression) bool { return s.suppresses(thisFinding) }) {
+			lint.suppressed = append(lint.suppressed, thisFinding)
+		} else {
+			lint.findings = append(lint.findings, thisFinding)
+		}
+	}
+}
+
+func (lint *configuredLinter) lintSeverity() Severity {
+	if lint.severity == "" {
+		return defaultSeverity
+	}
+	return lint.severity
+}
+
+// findingSeverity returns the severity of the given finding of the linter
+func (lint *configuredLinter) findingSeverity(f Finding) Severity {
+	if withSeverity, ok := f.(FindingWithSeverity); ok && lint.severity == "" {
+		return withSeverity.Severity()
+	}
+	return lint.lintSeverity()
+}
+
+// findingString returns the string of the given finding, prefixed by its severity if it is derived from its details
+func (lint *configuredLinter) findingString(f Finding) string {
+	if _, ok := f.(FindingWithSeverity); ok && lint.severity == "" {
+		return fmt.Sprintf("[%s] %s", lint.findingSeverity(f), f.String())
+	}
+	return f.String()
+}
+
+// sortedFindings returns the findings of the linter ordered from the highest severity, and then by their string
+func (lint *configuredLinter) sortedFindings() []Finding {
+	return slices.SortedFunc(slices.Values(lint.findings), func(f1, f2 Finding) int {
+		if rank1, rank2 := lint.findingSeverity(f1).rank(), lint.findingSeverity(f2).rank(); rank1 != rank2 {
+			return rank2 - rank1
+		}
+		return strings.Compare(f1.String(), f2.String())
+	})
+}
+
+func (lint *configuredLinter) string(printAll bool) string {
+	findingsResAll := make([]string, len(lint.findings))
+	for i, thisFinding := range lint.sortedFindings() {
+		findingsResAll[i] = lint.findingString(thisFinding)
+	}
+	var suffix string
+	var findingRes []string
+	if !printAll && len(lint.findings) > numFindingToPrint {
+		findingRes = findingsResAll[:numFindingToPrint]
+		suffix = fmt.Sprintf("\n... (%d more)\n", len(lint.findings)-numFindingToPrint)
+	} else {
+		findingRes = findingsResAll
+	}
+	if len(lint.suppressed) > 0 {
+		suffix += fmt.Sprintf("\n(%d suppressed)\n", len(lint.suppressed))
+	}
+	header := fmt.Sprintf("%q issues (%s):\n", lint.Description(), lint.reportedSeverity())
+	header += strings.Repeat("~", len(header)-1) + "\n"
+	return header + strings.Join(findingRes, "\n") + suffix
+}
+
+// reportedSeverity returns the severity of the linter as reported in the text and json outputs: the highest
+// severity of its (non suppressed) findings, or its configured severity if it has no findings
+func (lint *configuredLinter) reportedSeverity() Severity {
+	if severity, found := lint.highestSeverity(); found {
+		return severity
+	}
+	return lint.lintSeverity()
+}
+
+// highestSeverity returns the highest severity of the (non suppressed) findings of the linter, if any
+func (lint *configuredLinter) highestSeverity() (res Severity, found bool) {
+	for _, thisFinding := range lint.findings {
+		if severity := lint.findingSeverity(thisFinding); !found || severity.rank() > res.rank() {
+			res, found = severity, true
+		}
+	}
+	return res, found
+}
+
+// highestSeverity returns the highest severity of the (non suppressed) findings of the linters, if any
+func (linters Linters) highestSeverity() (res Severity, found bool) {
+	for _, thisLinter := range linters {
+		if severity, linterFound := thisLinter.highestSeverity(); linterFound && (!found || severity.rank() > res.rank()) {
+			res, found = severity, true
+		}
+	}
+	return res, found
+}
+
 func (linters Linters) String(printAllFindings bool) (resString string) {
 	strPerLint := []string{}
 	for _, thisLinter := range linters {
-		lintFindings := thisLinter.getFindings()
-		if len(lintFindings) > 0 {
-			thisLintStr := thisLinter.string(thisLinter.lintDescription(), printAllFindings)
-			strPerLint = append(strPerLint, thisLintStr)
+		if len(thisLinter.findings) > 0 {
+			strPerLint = append(strPerLint, thisLinter.string(printAllFindings))
 		}
 	}
 	sort.Strings(strPerLint)
@@ -117,3 +293,59 @@ func (linters Linters) String(printAllFindings bool) (resString string) {
 	resString = strings.Join(strPerLint, "\n"+delimBetweenLints+"\n\n")
 	return resString
 }
+
+// for json:
+type lintResultsJSON struct {
+	Linters []linterJSON `json:"linters"`
+}
+
+type linterJSON struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Severity    Severity      `json:"severity"`
+	Findings    []findingJSON `json:"findings"`
+	Suppressed  int           `json:"suppressed"`
+}
+
+type findingJSON struct {
+	Severity  Severity      `json:"severity,omitempty"` // set if derived from the finding's details
+	Resources []ResourceRef `json:"resources"`
+	Rule      *ruleRefJSON  `json:"rule,omitempty"`
+	Details   any           `json:"details"`
+}
+
+type ruleRefJSON struct {
+	Layer       string `json:"layer"`
+	Table       string `json:"table"`
+	RuleIndex   int    `json:"rule_index"`
+	Description string `json:"rule_description"`
+}
+
+// JSONString returns the results of the enabled linters as json, ordered by the linters' names
+func (linters Linters) JSONString() (string, error) {
+	res := lintResultsJSON{Linters: []linterJSON{}}
+	for _, thisLinter := range linters {
+		if !thisLinter.enabled {
+			continue
+		}
+		thisLinterJSON := linterJSON{Name: thisLinter.Name(), Description: thisLinter.Description(),
+			Severity: thisLinter.reportedSeverity(), Findings: []findingJSON{}, Suppressed: len(thisLinter.suppressed)}
+		for _, thisFinding := range thisLinter.sortedFindings() {
+			thisFindingJSON := findingJSON{Resources: thisFinding.Resources(), Details: thisFinding.ToJSON()}
+			if _, ok := thisFinding.(FindingWithSeverity); ok && thisLinter.severity == "" {
+				thisFindingJSON.Severity = thisLinter.findingSeverity(thisFinding)
+			}
+			if rule := thisFinding.Rule(); rule != nil {
+				thisFindingJSON.Rule = &ruleRefJSON{Layer: rule.Filter.LayerName,
+					Table: rule.Filter.FilterName, RuleIndex: rule.RuleIndex, Description: strings.TrimSpace(rule.RuleDesc)}
+			}
+			thisLinterJSON.Findings = append(thisLinterJSON.Findings, thisFindingJSON)
+		}
+		res.Linters = append(res.Linters, thisLinterJSON)
+	}
+	out, err := json.MarshalIndent(res, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
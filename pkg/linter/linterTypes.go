@@ -7,40 +7,86 @@ SPDX-License-Identifier: Apache-2.0
 package linter
 
 import (
-	"fmt"
-	"sort"
-	"strings"
-
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/vpcmodel"
 )
 
-const numFindingToPrint = 3
+// Linter is a single check of the VPC configs; the built-in linters implement it, and custom linters
+// implementing it may be added with RegisterLinter
+type Linter interface {
+	Check() error              // runs the check, collecting its findings
+	Findings() []Finding       // returns all findings detected by the linter
+	Name() string              // this lint Name, by which it is enabled, disabled and configured
+	Description() string       // description of the lint, heading its findings
+	EnableByDefault() bool     // whether the lint runs unless disabled
+	Params() map[string]string // supported parameters of the lint, mapped to their values; set by the lint config before Check
+}
 
-type Linters []linter
+// Finding is a single issue detected by a Linter
+type Finding interface {
+	VPC() []vpcmodel.VPCResourceIntf // the vpcs of the finding
+	Resources() []ResourceRef        // the resources the finding refers to, matched by suppressions
+	Rule() *vpcmodel.RuleOfFilter    // the rule the finding refers to, nil if none
+	String() string
+	ToJSON() any
+}
+
+// FindingWithSeverity is a Finding whose severity is derived from its details, e.g. from the resources it affects;
+// a severity set for its linter in the lint config overrides it
+type FindingWithSeverity interface {
+	Finding
+	Severity() Severity
+}
+
+// ResourceRef is a structured reference to a resource a finding refers to
+type ResourceRef struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	UID      string `json:"uid,omitempty"`
+	VPC      string `json:"vpc,omitempty"`
+	Instance string `json:"instance,omitempty"` // name of the vsi of a network interface
+}
+
+// NewResourceRef returns the reference to the given resource
+func NewResourceRef(resource vpcmodel.VPCResourceIntf) ResourceRef {
+	res := ResourceRef{Kind: resource.Kind(), Name: resource.Name(), UID: resource.UID()}
+	if resource.VPC() != nil {
+		res.VPC = resource.VPC().Name()
+	}
+	if vsiNode, ok := resource.(interface{ VsiName() string }); ok {
+		res.Instance = vsiNode.VsiName()
+	}
+	return res
+}
 
-type linter interface {
-	check() error
-	getFindings() []finding                       // returns all findings detected by the linter
-	addFinding(f finding)                         // add a single finding
-	lintName() string                             // this lint Name
-	lintDescription() string                      // this string Name
-	string(lintDesc string, printAll bool) string // string with this lint's finding
-	toJSON() []any                                // this lint finding in JSON
-	enableByDefault() bool                        //
+// NewFilterRef returns the reference to a nacl or security group of the given vpc
+func NewFilterRef(vpc vpcmodel.VPCResourceIntf, filter vpcmodel.Filter) ResourceRef {
+	res := ResourceRef{Kind: filter.LayerName, Name: filter.FilterName}
+	if vpc != nil {
+		res.VPC = vpc.Name()
+	}
+	return res
 }
 
-type finding interface {
-	vpc() []vpcmodel.VPCResourceIntf
-	string() string
-	toJSON() any
+// names returns the names by which the resource may be referred to
+func (r *ResourceRef) names() []string {
+	res := []string{r.Name}
+	if r.UID != "" {
+		res = append(res, r.UID)
+	}
+	if r.Instance != "" {
+		res = append(res, r.Instance)
+	}
+	return res
 }
 
+// basicLinter implements the common parts of the Linter interface for the built-in linters
 type basicLinter struct {
 	configs     map[string]*vpcmodel.VPCConfig
-	findings    []finding
+	findings    []Finding
 	name        string
 	description string
 	enable      bool
+	params      map[string]string // supported parameters of the lint, mapped to their values
 }
 
 type connectionLinter struct {
@@ -48,67 +94,96 @@ type connectionLinter struct {
 	nodesConn map[string]*vpcmodel.VPCConnectivity
 }
 
-func (lint *basicLinter) lintName() string {
+func (lint *basicLinter) Name() string {
 	return lint.name
 }
 
-func (lint *basicLinter) lintDescription() string {
+func (lint *basicLinter) Description() string {
 	return lint.description
 }
 
-func (lint *basicLinter) addFinding(f finding) {
+func (lint *basicLinter) addFinding(f Finding) {
 	lint.findings = append(lint.findings, f)
 }
-func (lint *basicLinter) addFindings(f []finding) {
+func (lint *basicLinter) addFindings(f []Finding) {
 	lint.findings = append(lint.findings, f...)
 }
 
-func (lint *basicLinter) getFindings() []finding {
+func (lint *basicLinter) Findings() []Finding {
 	return lint.findings
 }
 
-func (lint *basicLinter) enableByDefault() bool {
+func (lint *basicLinter) EnableByDefault() bool {
 	return lint.enable
 }
 
-func (lint *basicLinter) string(lintDesc string, printAll bool) string {
-	findingsResAll := make([]string, len(lint.findings))
-	for i, thisFinding := range lint.findings {
-		findingsResAll[i] = thisFinding.string()
-	}
-	sort.Strings(findingsResAll)
-	var suffix string
-	var findingRes []string
-	if !printAll && len(lint.findings) > numFindingToPrint {
-		findingRes = findingsResAll[:numFindingToPrint]
-		suffix = fmt.Sprintf("\n... (%d more)\n", len(lint.findings)-numFindingToPrint)
-	} else {
-		findingRes = findingsResAll
-	}
-	header := fmt.Sprintf("%q issues:\n", lintDesc)
-	header += strings.Repeat("~", len(header)-1) + "\n"
-	return header + strings.Join(findingRes, "\n") + suffix
-}
-
-func (lint *basicLinter) toJSON() []any {
-	res := make([]any, len(lint.findings))
-	for i, thisFinding := range lint.findings {
-		res[i] = thisFinding.toJSON()
-	}
-	return res
+func (lint *basicLinter) Params() map[string]string {
+	return lint.params
 }
 
 type filterLinter struct {
 	basicLinter
 	layer          string
-	checkForFilter func(map[string]*vpcmodel.VPCConfig, string) ([]finding, error)
+	checkForFilter func(configs map[string]*vpcmodel.VPCConfig, layer string, params map[string]string) ([]Finding, error)
 }
 
-func (fLint *filterLinter) check() error {
-	findings, err := fLint.checkForFilter(fLint.configs, fLint.layer)
+func (fLint *filterLinter) Check() error {
+	findings, err := fLint.checkForFilter(fLint.configs, fLint.layer, fLint.params)
 	if err != nil {
 		return err
 	}
 	fLint.addFindings(findings)
 	return nil
 }
+
+// routingTableLinter checks each routing table of each vpc separately
+type routingTableLinter struct {
+	connectionLinter
+	checkForTable func(config *vpcmodel.VPCConfig, nodesConn *vpcmodel.VPCConnectivity,
+		table vpcmodel.RoutingTable) ([]Finding, error)
+}
+
+func (rLint *routingTableLinter) Check() error {
+	for uid, config := range rLint.configs {
+		if config.IsMultipleVPCsConfig {
+			continue // routing tables are defined per vpc
+		}
+		for _, resource := range config.RoutingTables {
+			table, ok := resource.(vpcmodel.RoutingTable)
+			if !ok {
+				continue
+			}
+			findings, err := rLint.checkForTable(config, rLint.nodesConn[uid], table)
+			if err != nil {
+				return err
+			}
+			rLint.addFindings(findings)
+		}
+	}
+	return nil
+}
+
+// transitConnLinter checks each transit connection of each transit gateway separately
+type transitConnLinter struct {
+	basicLinter
+	checkForConn func(configs map[string]*vpcmodel.VPCConfig, router vpcmodel.TransitConnectionsRouter,
+		transitConn *vpcmodel.TransitConnection) []Finding
+}
+
+func (tLint *transitConnLinter) Check() error {
+	// a transit gateway is a routing resource of each of the configs of the vpcs it connects, and of their multi-vpc config
+	checked := map[string]bool{}
+	for _, config := range tLint.configs {
+		for _, resource := range config.RoutingResources {
+			router, ok := resource.(vpcmodel.TransitConnectionsRouter)
+			if !ok || checked[router.UID()] {
+				continue
+			}
+			checked[router.UID()] = true
+			for _, transitConn := range router.TransitConnections() {
+				tLint.addFindings(tLint.checkForConn(tLint.configs, router, transitConn))
+			}
+		}
+	}
+	return nil
+}
@@ -44,6 +44,10 @@ type VPCResource struct {
 	Region       string
 	// the VPC to which this resource belongs to
 	VPCRef VPCResourceIntf `json:"-"`
+	// the tags of the resource, as "key:value" strings (or plain strings for IBM user tags without a value)
+	ResourceTags []string `json:"-"`
+	// the user-defined grouping label of the resource, if any (see GroupingLabels)
+	groupingLabel *groupingLabel
 }
 
 func (n *VPCResource) Name() string {
@@ -89,6 +93,18 @@ func (n *VPCResource) RegionName() string {
 	return n.Region
 }
 
+func (n *VPCResource) Tags() []string {
+	return n.ResourceTags
+}
+
+func (n *VPCResource) label() *groupingLabel {
+	return n.groupingLabel
+}
+
+func (n *VPCResource) setLabel(label *groupingLabel) {
+	n.groupingLabel = label
+}
+
 func (n *VPCResource) NameAndUID() string {
 	return n.Name() + leftParentheses + n.UID() + rightParentheses
 }
@@ -223,6 +239,21 @@ type LoadBalancer interface {
 	GetLoadBalancerRule(src, dst Node) LoadBalancerRule
 	SetAbstractionInfo(*AbstractionInfo)
 	AbstractionInfo() *AbstractionInfo
+	// Pools returns the pools of backend servers of the load balancer
+	Pools() []*LoadBalancerPool
+}
+
+// LoadBalancerPool is a pool of backend servers (aka pool members) of a load balancer
+type LoadBalancerPool struct {
+	Name     string
+	Protocol string // the protocol of the pool: http, https, tcp or udp
+	Members  []*LoadBalancerPoolMember
+}
+
+// LoadBalancerPoolMember is a backend server of a load balancer pool, receiving the pool's traffic on Port
+type LoadBalancerPoolMember struct {
+	Node Node
+	Port int64
 }
 
 type miscConnectivityRule interface {
@@ -325,7 +356,12 @@ type FilterTrafficResource interface {
 	GetFiltersAttachedResources() FiltersAttachedResources
 	ReferencedIPblocks() []*netset.IPBlock
 	ConnectivityMap() (map[string]*IPbasedConnectivityResult, error)
-	GetConnectivityOutputPerEachElemSeparately() string
+	// ConnectivityPerEachElemSeparately computes the connectivity of each element to which this layer's filters apply,
+	// implied by these filters alone; e.g. of each subnet by its nacl
+	ConnectivityPerEachElemSeparately() ([]*SingleSubnetConnectivity, error)
+	// ConnectivityPerFilter computes, for each filter of this layer, the connectivity implied by that filter alone
+	// on the resources attached to it
+	ConnectivityPerFilter() ([]*FilterConnectivity, error)
 }
 
 // RoutingResource routing resource enables connectivity from src to destination via that resource
@@ -349,3 +385,88 @@ type RoutingResource interface {
 	// IsMultipleVPCs() - is the router for connections between VPCs
 	IsMultipleVPCs() bool
 }
+
+// RoutingTable is a custom routing table, whose routes determine the next hop of traffic by its destination;
+// an egress routing table applies to traffic leaving the subnets attached to it, and an ingress routing table
+// applies to traffic arriving at the VPC
+type RoutingTable interface {
+	VPCResourceIntf
+	// Routes returns the routes of the routing table
+	Routes() []*Route
+	// AttachedSubnets returns the subnets whose egress traffic is routed by the table; empty for ingress routing tables
+	AttachedSubnets() []Subnet
+}
+
+// RouteAction is the action a route applies to traffic matching its destination
+type RouteAction string
+
+const (
+	RouteDeliver     RouteAction = "deliver"      // routes the traffic to the next hop
+	RouteDelegate    RouteAction = "delegate"     // routes the traffic by the system implicit routing table
+	RouteDrop        RouteAction = "drop"         // drops the traffic
+	RouteDelegateVPC RouteAction = "delegate_vpc" // delegates to the system built-in routes, ignoring internet-bound routes
+)
+
+// Route is a route of a routing table; among the routes of a zone whose destination contains the address of a packet,
+// the route with the longest prefix is selected, and for equal prefixes the route with the highest priority
+// (the smallest value)
+type Route struct {
+	Name        string
+	Action      RouteAction
+	Zone        string
+	Priority    int
+	Destination *netset.IPBlock
+	DestPrefix  int64           // the prefix length of Destination
+	NextHop     *netset.IPBlock // the next hop address, only for RouteDeliver
+	Description string
+}
+
+// Precedes returns true if r is selected over other for the addresses in the destinations of both
+func (r *Route) Precedes(other *Route) bool {
+	return r.DestPrefix > other.DestPrefix || (r.DestPrefix == other.DestPrefix && r.Priority < other.Priority)
+}
+
+// DefaultPrefixFilter is the index of the default prefix filter of a transit connection
+const DefaultPrefixFilter = -1
+
+// TransitConnectionsRouter is a router connecting VPCs by transit connections, e.g. a transit gateway
+type TransitConnectionsRouter interface {
+	RoutingResource
+	// TransitConnections returns the transit connections of the VPCs connected by the router
+	TransitConnections() []*TransitConnection
+}
+
+// TransitConnection is a connection of a VPC to a transit gateway, whose prefix filters determine which of the
+// VPC's address prefixes are advertised to the transit gateway
+type TransitConnection struct {
+	Name string
+	VPC  VPCResourceIntf
+	// PrefixFilters are ordered by evaluation; an address prefix is advertised by the action of the first matching filter,
+	// or by DefaultPermit if none matches
+	PrefixFilters []*PrefixFilter
+	DefaultPermit bool
+	// AddressPrefixes are the address prefixes of the VPC, with the prefix filters determining whether they are advertised
+	AddressPrefixes []*AddressPrefixFilter
+}
+
+// PrefixFilter matches the routes contained in Prefix, whose prefix length is between MinLength and MaxLength
+type PrefixFilter struct {
+	Prefix      *netset.IPBlock
+	MinLength   int64
+	MaxLength   int64
+	Permit      bool
+	Description string
+}
+
+// Covers returns true if every route matched by other is matched by pf
+func (pf *PrefixFilter) Covers(other *PrefixFilter) bool {
+	return other.Prefix.IsSubset(pf.Prefix) && pf.MinLength <= other.MinLength && other.MaxLength <= pf.MaxLength
+}
+
+// AddressPrefixFilter is an address prefix of a VPC along with the index of the prefix filter of the transit connection
+// matching it, or DefaultPrefixFilter
+type AddressPrefixFilter struct {
+	AddressPrefix *netset.IPBlock
+	FilterIndex   int
+	Permit        bool
+}
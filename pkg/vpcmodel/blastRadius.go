@@ -0,0 +1,314 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+)
+
+// Functions for the computation of the blast radius of a (compromised) endpoint:
+// the set of internal endpoints transitively reachable from it, hop by hop, over the computed connectivity.
+// Connectivity between VPCs is considered via the multi-vpc (tgw) configs
+
+const (
+	mdBlastRadiusHeader = "| endpoint | hops | path |\n|----------|------|------|"
+	hopsStr             = "hops"
+	singleHopStr        = "hop"
+)
+
+// blastRadiusHop is a single hop in a path from the blast radius source: a connection from src to dst
+type blastRadiusHop struct {
+	src  Node
+	dst  Node
+	conn *detailedConn
+}
+
+// blastRadiusEndpoint is an endpoint reachable from the blast radius source, along with a shortest path to it
+type blastRadiusEndpoint struct {
+	endpoint Node
+	path     []*blastRadiusHop
+}
+
+// BlastRadiusAnalysis holds the endpoints reachable from a given source, restricted to a given connection
+type BlastRadiusAnalysis struct {
+	src       string
+	srcNodes  []Node
+	connQuery *netset.TransportSet
+	reachable []*blastRadiusEndpoint
+	// configs of the reachable endpoints, used for printing their names
+	nodeToConfig map[string]*VPCConfig
+}
+
+// blastRadiusGraph is the connectivity graph between internal nodes, over all configs;
+// nodes are identified by their UIDs
+type blastRadiusGraph struct {
+	nodes        map[string]Node
+	edges        map[string]map[string]*detailedConn
+	nodeToConfig map[string]*VPCConfig
+}
+
+// ComputeBlastRadius computes the internal endpoints transitively reachable from src via connections
+// contained in connQuery; connQuery nil stands for all connections.
+// src may be given as any input that is legal for explainability (e.g. vsi name, internal address)
+func (c *MultipleVPCConfigs) ComputeBlastRadius(src string, connQuery *netset.TransportSet) (*BlastRadiusAnalysis, error) {
+	if connQuery == nil {
+		connQuery = netset.AllTransports()
+	}
+	graph, err := c.newBlastRadiusGraph(connQuery)
+	if err != nil {
+		return nil, err
+	}
+	srcNodes, err := c.getBlastRadiusSrcNodes(src)
+	if err != nil {
+		return nil, err
+	}
+	res := &BlastRadiusAnalysis{src: src, srcNodes: srcNodes, connQuery: connQuery, nodeToConfig: graph.nodeToConfig}
+	res.reachable = graph.shortestPaths(srcNodes)
+	return res, nil
+}
+
+// newBlastRadiusGraph computes the connectivity of all configs and unifies it into a single graph
+// whose edges are the connections between internal nodes, restricted to connQuery
+func (c *MultipleVPCConfigs) newBlastRadiusGraph(connQuery *netset.TransportSet) (*blastRadiusGraph, error) {
+	res := &blastRadiusGraph{nodes: map[string]Node{}, edges: map[string]map[string]*detailedConn{},
+		nodeToConfig: map[string]*VPCConfig{}}
+	for _, vpcConfig := range c.Configs() {
+		vpcConn, err := vpcConfig.GetVPCNetworkConnectivity(false, NoGroupingNoConsistencyEdges)
+		if err != nil {
+			return nil, err
+		}
+		for src, srcMap := range vpcConn.AllowedConnsCombinedResponsive {
+			for dst, conn := range srcMap {
+				srcNode, srcIsNode := src.(Node)
+				dstNode, dstIsNode := dst.(Node)
+				if !srcIsNode || !dstIsNode || !srcNode.IsInternal() || !dstNode.IsInternal() {
+					continue
+				}
+				res.addEdge(vpcConfig, srcNode, dstNode, conn.intersect(connQuery))
+			}
+		}
+	}
+	return res, nil
+}
+
+func (g *blastRadiusGraph) addEdge(c *VPCConfig, src, dst Node, conn *detailedConn) {
+	if conn.isEmpty() {
+		return
+	}
+	// a node's name is printed w.r.t. its single vpc config, if there is one
+	for _, node := range []Node{src, dst} {
+		if existing, ok := g.nodeToConfig[node.UID()]; !ok || (existing.IsMultipleVPCsConfig && !c.IsMultipleVPCsConfig) {
+			g.nodes[node.UID()] = node
+			g.nodeToConfig[node.UID()] = c
+		}
+	}
+	if _, ok := g.edges[src.UID()]; !ok {
+		g.edges[src.UID()] = map[string]*detailedConn{}
+	}
+	if existing, ok := g.edges[src.UID()][dst.UID()]; ok {
+		conn = existing.union(conn)
+	}
+	g.edges[src.UID()][dst.UID()] = conn
+}
+
+// shortestPaths returns the nodes reachable from srcNodes, each with a shortest path to it from one of srcNodes.
+// The paths are computed by a BFS whose neighbors are visited in a deterministic (sorted) order
+func (g *blastRadiusGraph) shortestPaths(srcNodes []Node) []*blastRadiusEndpoint {
+	visited := map[string]*blastRadiusEndpoint{}
+	queue := []string{}
+	for _, src := range srcNodes {
+		visited[src.UID()] = &blastRadiusEndpoint{endpoint: src}
+		queue = append(queue, src.UID())
+	}
+	res := []*blastRadiusEndpoint{}
+	for len(queue) > 0 {
+		current := visited[queue[0]]
+		queue = queue[1:]
+		neighbors := make([]string, 0, len(g.edges[current.endpoint.UID()]))
+		for dst := range g.edges[current.endpoint.UID()] {
+			neighbors = append(neighbors, dst)
+		}
+		sort.Strings(neighbors)
+		for _, dst := range neighbors {
+			if _, ok := visited[dst]; ok {
+				continue
+			}
+			hop := &blastRadiusHop{src: current.endpoint, dst: g.nodes[dst], conn: g.edges[current.endpoint.UID()][dst]}
+			reached := &blastRadiusEndpoint{endpoint: g.nodes[dst], path: append(append([]*blastRadiusHop{}, current.path...), hop)}
+			visited[dst] = reached
+			queue = append(queue, dst)
+			res = append(res, reached)
+		}
+	}
+	return res
+}
+
+// getBlastRadiusSrcNodes returns the internal nodes of the blast radius source, as found in the configs
+func (c *MultipleVPCConfigs) getBlastRadiusSrcNodes(src string) ([]Node, error) {
+	nodesUIDs := map[string]Node{}
+	var errs []error
+	for _, vpcConfig := range c.Configs() {
+		nodes, errType, err := getNodesFromInputString(vpcConfig, src)
+		if err != nil {
+			if errType == fatalErr {
+				return nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		for _, node := range nodes {
+			if !node.IsInternal() {
+				return nil, fmt.Errorf("blast radius source %s must be internal", src)
+			}
+			nodesUIDs[node.UID()] = node
+		}
+	}
+	if len(nodesUIDs) == 0 {
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+		return nil, errors.New("no configs to analyze")
+	}
+	res := make([]Node, 0, len(nodesUIDs))
+	for _, node := range nodesUIDs {
+		res = append(res, node)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].UID() < res[j].UID() })
+	return res, nil
+}
+
+// sortedReachable returns the reachable endpoints sorted by number of hops, and then by name
+func (b *BlastRadiusAnalysis) sortedReachable() []*blastRadiusEndpoint {
+	res := append([]*blastRadiusEndpoint{}, b.reachable...)
+	sort.SliceStable(res, func(i, j int) bool {
+		if len(res[i].path) != len(res[j].path) {
+			return len(res[i].path) < len(res[j].path)
+		}
+		return b.nodeName(res[i].endpoint) < b.nodeName(res[j].endpoint)
+	})
+	return res
+}
+
+func (b *BlastRadiusAnalysis) nodeName(n Node) string {
+	return n.NameForAnalyzerOut(b.nodeToConfig[n.UID()])
+}
+
+func hopsNumStr(hops int) string {
+	if hops == 1 {
+		return fmt.Sprintf("%d %s", hops, singleHopStr)
+	}
+	return fmt.Sprintf("%d %s", hops, hopsStr)
+}
+
+func (b *BlastRadiusAnalysis) header() string {
+	return fmt.Sprintf("Blast radius of %s for %s\n", b.src, common.LongString(b.connQuery))
+}
+
+func (b *BlastRadiusAnalysis) hasStatelessConns() bool {
+	for _, reached := range b.reachable {
+		for _, hop := range reached.path {
+			if !hop.conn.TCPRspDisable.IsEmpty() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (b *BlastRadiusAnalysis) String() string {
+	if len(b.reachable) == 0 {
+		return b.header() + fmt.Sprintf("No endpoint is reachable from %s\n", b.src)
+	}
+	lines := []string{}
+	for _, reached := range b.sortedReachable() {
+		line := fmt.Sprintf("%s (%s):\n", b.nodeName(reached.endpoint), hopsNumStr(len(reached.path)))
+		for _, hop := range reached.path {
+			line += "\t" + getConnectionStr(b.nodeName(hop.src), b.nodeName(hop.dst), hop.conn.string(), "")
+		}
+		lines = append(lines, line)
+	}
+	return b.header() + strings.Join(lines, emptyString)
+}
+
+func (b *BlastRadiusAnalysis) mdString() string {
+	lines := []string{"# " + strings.TrimSuffix(b.header(), newLine), mdBlastRadiusHeader}
+	for _, reached := range b.sortedReachable() {
+		hops := make([]string, len(reached.path))
+		for i, hop := range reached.path {
+			hops[i] = fmt.Sprintf("%s => %s : %s", b.nodeName(hop.src), b.nodeName(hop.dst), hop.conn.string())
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %d | %s |", b.nodeName(reached.endpoint), len(reached.path),
+			strings.Join(hops, "<br>")))
+	}
+	return strings.Join(lines, newLine) + newLine
+}
+
+type blastRadiusHopJSON struct {
+	Src                Node           `json:"src"`
+	Dst                Node           `json:"dst"`
+	Conn               netset.Details `json:"conn"`
+	UnidirectionalConn netset.Details `json:"unidirectional_conn,omitempty"`
+}
+
+type blastRadiusEndpointJSON struct {
+	Endpoint Node                 `json:"endpoint"`
+	Hops     int                  `json:"hops"`
+	Path     []blastRadiusHopJSON `json:"path"`
+}
+
+type blastRadiusJSON struct {
+	Src       []Node                    `json:"src"`
+	Conn      netset.Details            `json:"conn"`
+	Reachable []blastRadiusEndpointJSON `json:"reachable"`
+}
+
+func (b *BlastRadiusAnalysis) toJSON() blastRadiusJSON {
+	reachable := []blastRadiusEndpointJSON{}
+	for _, reached := range b.sortedReachable() {
+		path := make([]blastRadiusHopJSON, len(reached.path))
+		for i, hop := range reached.path {
+			path[i] = blastRadiusHopJSON{Src: hop.src, Dst: hop.dst,
+				Conn: netset.ToJSON(hop.conn.nonTCPAndResponsiveTCPComponent())}
+			if !hop.conn.TCPRspDisable.IsEmpty() {
+				path[i].UnidirectionalConn = netset.ToJSON(hop.conn.TCPRspDisable)
+			}
+		}
+		reachable = append(reachable, blastRadiusEndpointJSON{Endpoint: reached.endpoint, Hops: len(reached.path), Path: path})
+	}
+	return blastRadiusJSON{Src: b.srcNodes, Conn: netset.ToJSON(b.connQuery), Reachable: reachable}
+}
+
+// blastRadiusOutputFormatter is the formatter of the blast radius analysis, for json, md and txt formats.
+// blastRadiusOutputFormatter implements the interface OutputFormatter; since the blast radius is computed
+// over all configs, its output is not split per vpc
+type blastRadiusOutputFormatter struct {
+	outFormat   OutFormat
+	blastRadius *BlastRadiusAnalysis
+}
+
+func (bf *blastRadiusOutputFormatter) WriteOutput(_ *MultipleVPCConfigs, _ map[string]*VPCConnectivity,
+	_ map[string]*VPCsubnetConnectivity, _ *diffBetweenCfgs,
+	outFile string, _ bool, uc OutputUseCase, _ *Explanation, _ bool) (string, error) {
+	switch bf.outFormat {
+	case Text:
+		return WriteToFile(bf.blastRadius.String()+
+			getAsteriskDetails(uc, bf.blastRadius.hasStatelessConns(), false, bf.outFormat), outFile)
+	case MD:
+		return WriteToFile(bf.blastRadius.mdString()+
+			getAsteriskDetails(uc, bf.blastRadius.hasStatelessConns(), false, bf.outFormat), outFile)
+	case JSON:
+		return writeJSON(bf.blastRadius.toJSON(), outFile)
+	}
+	return "", errors.New("unsupported output format for blast radius")
+}
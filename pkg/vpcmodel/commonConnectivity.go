@@ -34,6 +34,19 @@ func allTCPconn() *netset.TransportSet {
 		netp.MinPort, netp.MaxPort)
 }
 
+func allUDPconn() *netset.TransportSet {
+	return newUDPConn(netp.MinPort, netp.MaxPort,
+		netp.MinPort, netp.MaxPort)
+}
+
+func icmpEchoConn() *netset.TransportSet {
+	return netset.NewICMPTransport(netp.Echo, netp.Echo, int64(netp.MinICMPCode), int64(netp.MinICMPCode))
+}
+
+func icmpEchoReplyConn() *netset.TransportSet {
+	return netset.NewICMPTransport(netp.EchoReply, netp.EchoReply, int64(netp.MinICMPCode), int64(netp.MinICMPCode))
+}
+
 // PartitionTCPNonTCP given a connection returns its TCP and non-TCP sub-connections
 func partitionTCPNonTCP(conn *netset.TransportSet) (tcp, nonTCP *netset.TransportSet) {
 	tcpFractionOfConn := allTCPconn().Intersect(conn)
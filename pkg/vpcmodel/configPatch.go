@@ -0,0 +1,163 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigChangeOp is the type of change applied by a ConfigChange
+type ConfigChangeOp string
+
+const (
+	AddSGRule      ConfigChangeOp = "add-sg-rule"      // add NewRule to SG
+	RemoveSGRule   ConfigChangeOp = "remove-sg-rule"   // remove the rule of SG whose id is RuleID
+	AttachSG       ConfigChangeOp = "attach-sg"        // attach SG to the network interfaces of Instance
+	DetachSG       ConfigChangeOp = "detach-sg"        // detach SG from the network interfaces of Instance
+	AddNACLRule    ConfigChangeOp = "add-nacl-rule"    // add NewRule to NACL, before the rule named Before (or first)
+	RemoveNACLRule ConfigChangeOp = "remove-nacl-rule" // remove the rule of NACL whose name is RuleName
+	SetSubnetNACL  ConfigChangeOp = "set-subnet-nacl"  // move Subnet to NACL
+	AttachPGW      ConfigChangeOp = "attach-pgw"       // attach public gateway PGW to Subnet
+	DetachPGW      ConfigChangeOp = "detach-pgw"       // detach the public gateway of Subnet
+)
+
+var configChangeOps = []ConfigChangeOp{AddSGRule, RemoveSGRule, AttachSG, DetachSG, AddNACLRule, RemoveNACLRule,
+	SetSubnetNACL, AttachPGW, DetachPGW}
+
+// ConfigPatch is a list of changes to apply on a loaded config, for a what-if analysis:
+// the connectivity of the patched config is compared to that of the original config.
+// Resources are referenced by their names
+type ConfigPatch struct {
+	Changes []*ConfigChange `yaml:"changes" json:"changes"`
+}
+
+// ConfigChange is a single change of a config; the fields relevant to each op are as documented in ConfigChangeOp
+type ConfigChange struct {
+	Op       ConfigChangeOp `yaml:"op" json:"op"`
+	SG       string         `yaml:"sg,omitempty" json:"sg,omitempty"`
+	NACL     string         `yaml:"nacl,omitempty" json:"nacl,omitempty"`
+	Subnet   string         `yaml:"subnet,omitempty" json:"subnet,omitempty"`
+	Instance string         `yaml:"instance,omitempty" json:"instance,omitempty"`
+	PGW      string         `yaml:"pgw,omitempty" json:"pgw,omitempty"`
+	RuleID   string         `yaml:"rule_id,omitempty" json:"rule_id,omitempty"`
+	RuleName string         `yaml:"rule_name,omitempty" json:"rule_name,omitempty"`
+	Before   string         `yaml:"before,omitempty" json:"before,omitempty"`
+	NewRule  *PatchRule     `yaml:"new_rule,omitempty" json:"new_rule,omitempty"`
+}
+
+// PatchRule is a rule added by a ConfigChange.
+// Remote and Local are relevant to SG rules: remote is a cidr, an ip address or a SG name; local is a cidr or an ip address.
+// Name, Action, Source and Destination are relevant to NACL rules; SrcPortMin and SrcPortMax are relevant to NACL TCP/UDP rules
+type PatchRule struct {
+	Name        string `yaml:"name,omitempty" json:"name,omitempty"`
+	Action      string `yaml:"action,omitempty" json:"action,omitempty"`
+	Direction   string `yaml:"direction" json:"direction"`
+	Protocol    string `yaml:"protocol" json:"protocol"`
+	Remote      string `yaml:"remote,omitempty" json:"remote,omitempty"`
+	Local       string `yaml:"local,omitempty" json:"local,omitempty"`
+	Source      string `yaml:"source,omitempty" json:"source,omitempty"`
+	Destination string `yaml:"destination,omitempty" json:"destination,omitempty"`
+	SrcPortMin  *int64 `yaml:"src_port_min,omitempty" json:"src_port_min,omitempty"`
+	SrcPortMax  *int64 `yaml:"src_port_max,omitempty" json:"src_port_max,omitempty"`
+	DstPortMin  *int64 `yaml:"dst_port_min,omitempty" json:"dst_port_min,omitempty"`
+	DstPortMax  *int64 `yaml:"dst_port_max,omitempty" json:"dst_port_max,omitempty"`
+	ICMPType    *int64 `yaml:"icmp_type,omitempty" json:"icmp_type,omitempty"`
+	ICMPCode    *int64 `yaml:"icmp_code,omitempty" json:"icmp_code,omitempty"`
+}
+
+// ReadConfigPatch reads a ConfigPatch from a yaml or json file
+func ReadConfigPatch(fileName string) (*ConfigPatch, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	res := &ConfigPatch{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(res); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing patch file %s: %w", fileName, err)
+	}
+	for i, change := range res.Changes {
+		if !slices.Contains(configChangeOps, change.Op) {
+			return nil, fmt.Errorf("change %d of patch file %s has an unknown op %q", i, fileName, change.Op)
+		}
+		if missing := change.missingField(); missing != emptyString {
+			return nil, fmt.Errorf("change %d (%s) of patch file %s is missing the field %s", i, change.Op, fileName, missing)
+		}
+	}
+	return res, nil
+}
+
+// a field of a ConfigChange (or of its new rule) with its yaml key
+type patchField struct {
+	key   string
+	value string
+}
+
+// requiredFields returns the fields required by the op of the change
+func (c *ConfigChange) requiredFields() []patchField {
+	sg, nacl, subnet := patchField{"sg", c.SG}, patchField{"nacl", c.NACL}, patchField{"subnet", c.Subnet}
+	switch c.Op {
+	case AddSGRule:
+		return []patchField{sg}
+	case RemoveSGRule:
+		return []patchField{sg, {"rule_id", c.RuleID}}
+	case AttachSG, DetachSG:
+		return []patchField{sg, {"instance", c.Instance}}
+	case AddNACLRule:
+		return []patchField{nacl}
+	case RemoveNACLRule:
+		return []patchField{nacl, {"rule_name", c.RuleName}}
+	case SetSubnetNACL:
+		return []patchField{subnet, nacl}
+	case AttachPGW:
+		return []patchField{subnet, {"pgw", c.PGW}}
+	case DetachPGW:
+		return []patchField{subnet}
+	}
+	return nil
+}
+
+// requiredRuleFields returns the fields of the new rule required by the op of the change,
+// or nil if the op does not add a rule
+func (c *ConfigChange) requiredRuleFields() []patchField {
+	if c.Op != AddSGRule && c.Op != AddNACLRule {
+		return nil
+	}
+	rule := c.NewRule
+	res := []patchField{{"direction", rule.Direction}, {"protocol", rule.Protocol}}
+	if c.Op == AddSGRule {
+		return append(res, patchField{"remote", rule.Remote})
+	}
+	return append(res, patchField{"source", rule.Source}, patchField{"destination", rule.Destination})
+}
+
+// missingField returns the key of the first field required by the op of the change that is not set,
+// or an empty string if all are set
+func (c *ConfigChange) missingField() string {
+	for _, field := range c.requiredFields() {
+		if field.value == emptyString {
+			return field.key
+		}
+	}
+	if (c.Op == AddSGRule || c.Op == AddNACLRule) && c.NewRule == nil {
+		return "new_rule"
+	}
+	for _, field := range c.requiredRuleFields() {
+		if field.value == emptyString {
+			return "new_rule." + field.key
+		}
+	}
+	return emptyString
+}
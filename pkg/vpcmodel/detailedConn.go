@@ -31,6 +31,11 @@ type detailedConn struct {
 	allConn       *netset.TransportSet // entire connection
 	TCPRspDisable *netset.TransportSet // non-responsive TCP connection between <src, dst>; complementary of tcpRspEnable
 	// connection is defined to be responsive if nonTCP is empty
+
+	// UDP connection and ICMP echo requests between <src, dst> whose response (the UDP connection with swapped ports
+	// and the ICMP echo reply, respectively) is blocked by the stateless filters; a subset of nonTCP.
+	// It is not reflected in the reports, in which the responsiveness of the connection refers to TCP only
+	nonTCPRspDisable *netset.TransportSet
 }
 
 // operation on detailedConn
@@ -44,9 +49,21 @@ func newDetailedConn(tspRspConn, otherConn, allConn *netset.TransportSet) *detai
 		TCPRspDisable: (allConn.Subtract(otherConn)).Subtract(tspRspConn),
 		nonTCP:        otherConn,
 		allConn:       allConn,
+
+		nonTCPRspDisable: NoConns(),
 	}
 }
 
+// AllConn returns the entire connection
+func (d *detailedConn) AllConn() *netset.TransportSet {
+	return d.allConn
+}
+
+// NonTCPRspDisable returns the UDP connection and ICMP echo requests whose response is blocked
+func (d *detailedConn) NonTCPRspDisable() *netset.TransportSet {
+	return d.nonTCPRspDisable
+}
+
 func emptyDetailedConn() *detailedConn {
 	return newDetailedConn(NoConns(), NoConns(), NoConns())
 }
@@ -79,7 +96,7 @@ func (d *detailedConn) isEmpty() bool {
 	return d.allConn.IsEmpty()
 }
 
-// Equal all components of two detailedConn are equal
+// Equal all components of two detailedConn are equal, except for nonTCPRspDisable which is not reflected in the reports
 func (d *detailedConn) equal(other *detailedConn) bool {
 	return d.tcpRspEnable.Equal(other.tcpRspEnable) && d.nonTCP.Equal(other.nonTCP) &&
 		d.allConn.Equal(other.allConn)
@@ -91,7 +108,9 @@ func (d *detailedConn) union(other *detailedConn) *detailedConn {
 	rspConn := d.tcpRspEnable.Union(other.tcpRspEnable)
 	otherConn := d.nonTCP.Union(other.nonTCP)
 	conn := d.allConn.Union(other.allConn)
-	return newDetailedConn(rspConn, otherConn, conn)
+	res := newDetailedConn(rspConn, otherConn, conn)
+	res.nonTCPRspDisable = d.nonTCPRspDisable.Union(other.nonTCPRspDisable)
+	return res
 }
 
 // subtract of two detailedConn: subtraction of tcpRspEnable, nonTCP and allConn
@@ -100,7 +119,17 @@ func (d *detailedConn) subtract(other *detailedConn) *detailedConn {
 	rspConn := d.tcpRspEnable.Subtract(other.tcpRspEnable)
 	otherConn := d.nonTCP.Subtract(other.nonTCP)
 	conn := d.allConn.Subtract(other.allConn)
-	return newDetailedConn(rspConn, otherConn, conn)
+	res := newDetailedConn(rspConn, otherConn, conn)
+	res.nonTCPRspDisable = d.nonTCPRspDisable.Intersect(otherConn)
+	return res
+}
+
+// intersect of a detailedConn with a connection: intersection of tcpRspEnable, nonTCP and allConn with conn
+// (TCPRspDisable is computed based on these)
+func (d *detailedConn) intersect(conn *netset.TransportSet) *detailedConn {
+	res := newDetailedConn(d.tcpRspEnable.Intersect(conn), d.nonTCP.Intersect(conn), d.allConn.Intersect(conn))
+	res.nonTCPRspDisable = d.nonTCPRspDisable.Intersect(conn)
+	return res
 }
 
 func (d *detailedConn) hasTCPComponent() bool {
@@ -145,13 +174,29 @@ func (d *detailedConn) connStrPerConnectionType(nonTCPAndResponsiveTCP bool) str
 // computeDetailedConn computes the detailedConn object, given input `srcToDst`
 // that represents a src-to-dst connection, and `dstToSrc` that represents dst-to-src connection.
 func computeDetailedConn(srcToDst, dstToSrc *netset.TransportSet) *detailedConn {
+	var res *detailedConn
 	connTCP := srcToDst.Intersect(allTCPconn())
 	if connTCP.IsEmpty() {
-		return detailedConnForTCPRsp(NoConns(), srcToDst)
+		res = detailedConnForTCPRsp(NoConns(), srcToDst)
+	} else {
+		tcpSecondDirection := dstToSrc.Intersect(allTCPconn())
+		// flip src/dst ports before intersection
+		tcpSecondDirectionFlipped := tcpSecondDirection.SwapPorts()
+		// tcp connection responsive subset
+		res = detailedConnForTCPRsp(connTCP.Intersect(tcpSecondDirectionFlipped), srcToDst)
+	}
+	res.nonTCPRspDisable = computeNonTCPRspDisable(srcToDst, dstToSrc)
+	return res
+}
+
+// computeNonTCPRspDisable returns the UDP connection and ICMP echo requests of `srcToDst` whose response is
+// not allowed by `dstToSrc`: UDP with flipped src/dst ports, and ICMP echo reply, respectively
+func computeNonTCPRspDisable(srcToDst, dstToSrc *netset.TransportSet) *netset.TransportSet {
+	connUDP := srcToDst.Intersect(allUDPconn())
+	udpResponsive := connUDP.Intersect(dstToSrc.Intersect(allUDPconn()).SwapPorts())
+	res := connUDP.Subtract(udpResponsive)
+	if echo := srcToDst.Intersect(icmpEchoConn()); !echo.IsEmpty() && !icmpEchoReplyConn().IsSubset(dstToSrc) {
+		res = res.Union(echo)
 	}
-	tcpSecondDirection := dstToSrc.Intersect(allTCPconn())
-	// flip src/dst ports before intersection
-	tcpSecondDirectionFlipped := tcpSecondDirection.SwapPorts()
-	// tcp connection responsive subset
-	return detailedConnForTCPRsp(connTCP.Intersect(tcpSecondDirectionFlipped), srcToDst)
+	return res
 }
@@ -0,0 +1,182 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+	"github.com/np-guard/models/pkg/spec"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/drawio"
+)
+
+// ViewLevel is the lowest level of the network shown on the drawio/svg/html map;
+// the levels below it are collapsed, and their connections are aggregated
+type ViewLevel int
+
+const (
+	EndpointViewLevel ViewLevel = iota // the full map
+	SubnetViewLevel                    // the endpoints are collapsed into their subnets
+	ZoneViewLevel                      // the subnets of each zone are collapsed into a single square
+	VpcViewLevel                       // the zones of each vpc are collapsed into a single square
+)
+
+// collapsedSquare represents all the subnets and endpoints of a zone (in ZoneViewLevel) or of a vpc (in VpcViewLevel)
+type collapsedSquare struct {
+	vpc     VPCResourceIntf
+	zone    string            // empty in VpcViewLevel
+	subnets []Subnet          // the collapsed subnets
+	nodes   map[Subnet][]Node // the collapsed endpoints of each subnet
+}
+
+func (c *collapsedSquare) Name() string {
+	if c.zone == "" {
+		return c.vpc.Name()
+	}
+	return c.zone
+}
+func (c *collapsedSquare) NameForAnalyzerOut(*VPCConfig) string { return c.Name() }
+func (c *collapsedSquare) UID() string                          { return c.vpc.UID() + "/" + c.zone }
+func (c *collapsedSquare) IsExternal() bool                     { return false }
+func (c *collapsedSquare) ShowOnSubnetMode() bool               { return true }
+func (c *collapsedSquare) SynthesisResourceName() string        { return "" }
+func (c *collapsedSquare) SynthesisKind() spec.ResourceType     { return "" }
+func (c *collapsedSquare) Kind() string {
+	if c.zone == "" {
+		return "Collapsed VPC"
+	}
+	return "Collapsed Zone"
+}
+
+func (c *collapsedSquare) summary() string {
+	nNodes := 0
+	for _, nodes := range c.nodes {
+		nNodes += len(nodes)
+	}
+	return fmt.Sprintf("%d subnets, %d endpoints", len(c.subnets), nNodes)
+}
+
+// content lists the collapsed subnets, each with its endpoints
+func (c *collapsedSquare) content() []string {
+	res := make([]string, len(c.subnets))
+	for i, subnet := range c.subnets {
+		names := make([]string, len(c.nodes[subnet]))
+		for j, node := range c.nodes[subnet] {
+			names[j] = node.Name()
+		}
+		slices.Sort(names)
+		res[i] = fmt.Sprintf("%s (%s): %s", subnet.Name(), subnet.CIDR(), strings.Join(names, ", "))
+	}
+	slices.Sort(res)
+	return res
+}
+
+func (c *collapsedSquare) GenerateDrawioTreeNode(gen *DrawioGenerator) drawio.TreeNodeInterface {
+	zoneTn := drawio.NewZoneTreeNode(gen.TreeNode(c.vpc).(*drawio.VpcTreeNode), c.zone)
+	if c.zone == "" {
+		// the collapsed square of a vpc is located in a zone, that is not shown
+		zoneTn.SetNotShownInDrawio()
+	}
+	tn := drawio.NewSubnetTreeNode(zoneTn, c.Name(), c.summary(), "")
+	tn.SetCollapsedContent(c.content())
+	return tn
+}
+
+func (d *DrawioOutputFormatter) isCollapsed() bool {
+	return d.viewLevel != EndpointViewLevel
+}
+
+// collapsesSubnets() returns true if the subnets are collapsed, in which case the map holds collapsedSquares instead of subnets
+func (d *DrawioOutputFormatter) collapsesSubnets() bool {
+	return d.viewLevel == ZoneViewLevel || d.viewLevel == VpcViewLevel
+}
+
+// createCollapsedSquares() creates the collapsedSquares of all the vpcs, with the subnets and endpoints collapsed into them
+func (d *DrawioOutputFormatter) createCollapsedSquares() {
+	d.collapsedSquares = map[string]*collapsedSquare{}
+	for _, vpcConfig := range d.cConfigs.Configs() {
+		if vpcConfig.IsMultipleVPCsConfig {
+			continue
+		}
+		d.gen.TreeNode(vpcConfig.VPC)
+		for _, subnet := range vpcConfig.Subnets {
+			square := d.subnetCollapsedSquare(subnet)
+			if square == nil {
+				square = &collapsedSquare{vpc: vpcConfig.VPC, nodes: map[Subnet][]Node{}}
+				if d.viewLevel == ZoneViewLevel {
+					square.zone = subnet.ZoneName()
+				}
+				d.collapsedSquares[d.collapsedSquareKey(subnet)] = square
+			}
+			square.subnets = append(square.subnets, subnet)
+		}
+		for _, node := range vpcConfig.Nodes {
+			if internalNode, ok := node.(InternalNodeIntf); ok && !node.IsExternal() {
+				if square := d.subnetCollapsedSquare(internalNode.Subnet()); square != nil {
+					square.nodes[internalNode.Subnet()] = append(square.nodes[internalNode.Subnet()], node)
+				}
+			}
+		}
+	}
+	// the squares are created in the order of their uids, to get a stable layout:
+	squares := slices.Collect(maps.Values(d.collapsedSquares))
+	slices.SortFunc(squares, func(a, b *collapsedSquare) int { return strings.Compare(a.UID(), b.UID()) })
+	for _, square := range squares {
+		d.gen.TreeNode(square)
+	}
+}
+
+func (d *DrawioOutputFormatter) collapsedSquareKey(subnet Subnet) string {
+	if d.viewLevel == ZoneViewLevel {
+		return subnet.VPC().UID() + "/" + subnet.ZoneName()
+	}
+	return subnet.VPC().UID()
+}
+
+func (d *DrawioOutputFormatter) subnetCollapsedSquare(subnet Subnet) *collapsedSquare {
+	return d.collapsedSquares[d.collapsedSquareKey(subnet)]
+}
+
+// collapsedElems() returns the elements representing an endpoint element on the collapsed map:
+// external elements are not collapsed, and internal elements are replaced by their subnets or by their collapsedSquares
+func (d *DrawioOutputFormatter) collapsedElems(ep EndpointElem) []EndpointElem {
+	if !d.isCollapsed() || ep.IsExternal() {
+		return []EndpointElem{ep}
+	}
+	res := []EndpointElem{}
+	for _, resource := range endpointElemResources(ep) {
+		var subnet Subnet
+		switch r := resource.(type) {
+		case Subnet:
+			subnet = r
+		case InternalNodeIntf:
+			subnet = r.Subnet()
+		default:
+			// resources that are not in a single subnet (e.g. abstracted load balancers) are not shown on collapsed maps
+			continue
+		}
+		var elem EndpointElem = subnet
+		if d.collapsesSubnets() {
+			if square := d.subnetCollapsedSquare(subnet); square != nil {
+				elem = square
+			}
+		}
+		if !slices.Contains(res, elem) {
+			res = append(res, elem)
+		}
+	}
+	return res
+}
+
+// collapsedEdgeLabel() returns the label of an aggregated edge of a collapsed map, from the union of its connections
+func collapsedEdgeLabel(conn *netset.TransportSet) string {
+	return strings.ReplaceAll(common.LongString(conn), "protocol: ", "")
+}
@@ -7,6 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package vpcmodel
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/np-guard/models/pkg/interval"
+	"github.com/np-guard/models/pkg/netp"
+	"github.com/np-guard/models/pkg/netset"
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/drawio"
 )
 
@@ -37,6 +43,7 @@ func NewDrawioGenerator(cloudName string, lbAbstraction bool, uc OutputUseCase)
 	gen.network = drawio.NewNetworkTreeNode()
 	gen.publicNetwork = drawio.NewPublicNetworkTreeNode(gen.network)
 	gen.cloud = drawio.NewCloudTreeNode(gen.network, cloudName)
+	gen.cloud.SetUID(cloudName)
 	gen.treeNodes = map[FormattableResource]drawio.TreeNodeInterface{}
 	gen.lbAbstraction = lbAbstraction
 	gen.uc = uc
@@ -54,6 +61,10 @@ func (gen *DrawioGenerator) TreeNode(res FormattableResource) drawio.TreeNodeInt
 			if gen.treeNodes[res] != nil && gen.treeNodes[res].Kind() == "" {
 				gen.treeNodes[res].SetKind(res.Kind())
 			}
+			// the uid is written to the drawio file, so the file can seed the layout of a later run:
+			if uidRes, ok := res.(interface{ UID() string }); ok && gen.treeNodes[res] != nil {
+				gen.treeNodes[res].SetUID(uidRes.UID())
+			}
 		}
 	}
 	return gen.treeNodes[res]
@@ -130,5 +141,35 @@ func (g *groupedExternalNodes) GenerateDrawioTreeNode(gen *DrawioGenerator) draw
 func (e *edgeInfo) GenerateDrawioTreeNode(gen *DrawioGenerator) drawio.TreeNodeInterface {
 	srcTn := gen.TreeNode(e.src)
 	dstTn := gen.TreeNode(e.dst)
-	return drawio.NewConnectivityLineTreeNode(gen.Network(), srcTn, dstTn, e.directed, e.label)
+	tn := drawio.NewConnectivityLineTreeNode(gen.Network(), srcTn, dstTn, e.directed, e.label)
+	tn.SetConn(drawioConn(e.conn))
+	return tn
+}
+
+// drawioConn returns the protocols and destination ports of a connection, for the filters of the html viewer
+func drawioConn(conn *netset.TransportSet) []drawio.ConnProtocol {
+	if conn == nil {
+		return nil
+	}
+	res := []drawio.ConnProtocol{}
+	for protocol, protocolConn := range map[netp.ProtocolString]*netset.TransportSet{
+		netp.ProtocolStringTCP: netset.AllTCPTransport(), netp.ProtocolStringUDP: netset.AllUDPTransport()} {
+		dstPorts := interval.NewCanonicalSet()
+		for _, cube := range conn.Intersect(protocolConn).TCPUDPSet().Partitions() {
+			dstPorts = dstPorts.Union(cube.S3)
+		}
+		if dstPorts.IsEmpty() {
+			continue
+		}
+		connProtocol := drawio.ConnProtocol{Protocol: string(protocol)}
+		for _, portsInterval := range dstPorts.Intervals() {
+			connProtocol.DstPorts = append(connProtocol.DstPorts, drawio.PortRange{Min: portsInterval.Start(), Max: portsInterval.End()})
+		}
+		res = append(res, connProtocol)
+	}
+	if !conn.ICMPSet().IsEmpty() {
+		res = append(res, drawio.ConnProtocol{Protocol: string(netp.ProtocolStringICMP)})
+	}
+	slices.SortFunc(res, func(a, b drawio.ConnProtocol) int { return strings.Compare(a.Protocol, b.Protocol) })
+	return res
 }
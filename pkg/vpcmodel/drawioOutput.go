@@ -11,6 +11,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/np-guard/models/pkg/netset"
 	"github.com/np-guard/models/pkg/spec"
 	common "github.com/np-guard/vpc-network-config-analyzer/pkg/common"
 	"github.com/np-guard/vpc-network-config-analyzer/pkg/drawio"
@@ -21,6 +22,7 @@ type edgeInfo struct {
 	dst      EndpointElem
 	label    string
 	directed bool
+	conn     *netset.TransportSet
 }
 
 func (e *edgeInfo) IsExternal() bool {
@@ -39,6 +41,8 @@ func (e *edgeInfo) SynthesisKind() spec.ResourceType {
 // 1. collect all the connectivity edges to a map of (src,dst,label) -> isDirected. also mark the nodes that has connections
 // 2. create the treeNodes of the NodeSets, filters. routers and nodes
 // 3. create the edges from the map we created in stage (1). also sets the routers to the edges
+// in a collapsed map (see ViewLevel), the nodes are replaced by their subnets or by the collapsedSquares,
+// and the edges between them are aggregated
 
 type DrawioOutputFormatter struct {
 	cConfigs        *MultipleVPCConfigs
@@ -50,13 +54,23 @@ type DrawioOutputFormatter struct {
 	uc              OutputUseCase
 	outFormat       OutFormat
 	lbAbstraction   bool
+	viewLevel       ViewLevel
+	layoutSeed      drawio.LayoutSeed
+	// collapsedSquares - map from a vpc uid (or vpc uid and zone) to its collapsedSquare, in ZoneViewLevel and VpcViewLevel
+	collapsedSquares map[string]*collapsedSquare
+	// subnetsGroups - map from the uid of a part of a group of nodes in several subnets, to the part, see shownElems()
+	subnetsGroups map[string]*groupedEndpointsElems
 }
 
-func newDrawioOutputFormatter(outFormat OutFormat, lbAbstraction bool) *DrawioOutputFormatter {
+func newDrawioOutputFormatter(outFormat OutFormat, lbAbstraction bool, viewLevel ViewLevel,
+	layoutSeed drawio.LayoutSeed) *DrawioOutputFormatter {
 	d := DrawioOutputFormatter{}
 	d.outFormat = outFormat
+	d.viewLevel = viewLevel
+	d.layoutSeed = layoutSeed
 	d.nodeRouters = map[drawio.TreeNodeInterface]drawio.IconTreeNodeInterface{}
 	d.multiVpcRouters = map[string]drawio.IconTreeNodeInterface{}
+	d.subnetsGroups = map[string]*groupedEndpointsElems{}
 	d.lbAbstraction = lbAbstraction
 	return &d
 }
@@ -69,13 +83,23 @@ func (d *DrawioOutputFormatter) init(
 	d.vpcConns = vpcConns
 	d.gConns = gConns
 	d.uc = uc
-	d.gen = NewDrawioGenerator(cConfigs.CloudName(), d.lbAbstraction, uc)
+	if d.isCollapsed() {
+		// a collapsed map is drawn in subnets mode, its squares are either subnets or collapsedSquares
+		d.uc = AllSubnets
+	}
+	d.gen = NewDrawioGenerator(cConfigs.CloudName(), d.lbAbstraction, d.uc)
+	d.gen.Network().SetLayoutSeed(d.layoutSeed)
 }
 
 func (d *DrawioOutputFormatter) createDrawioTree() {
-	d.createNodeSets()
-	d.createNodes()
-	d.createFilters()
+	if d.collapsesSubnets() {
+		d.createCollapsedSquares()
+		d.createPublicNetworkIcon()
+	} else {
+		d.createNodeSets()
+		d.createNodes()
+		d.createFilters()
+	}
 	d.createRouters()
 	if d.gConns != nil {
 		d.createEdges()
@@ -157,6 +181,10 @@ func (d *DrawioOutputFormatter) createRouters() {
 				// MultipleVPCs routers might exist at a non MultipleVPCs config (and vice versa?), it should be ignored
 				continue
 			}
+			if d.collapsesSubnets() && !vpcConfig.IsMultipleVPCsConfig {
+				// the routers of a single vpc are collapsed with its subnets
+				continue
+			}
 			if rTn := d.gen.TreeNode(r); rTn != nil {
 				if vpcConfig.IsMultipleVPCsConfig {
 					d.multiVpcRouters[vpcResourceID] = rTn.(drawio.IconTreeNodeInterface)
@@ -177,16 +205,19 @@ func (d *DrawioOutputFormatter) createRouters() {
 	}
 }
 
-func (d *DrawioOutputFormatter) lineRouter(line *groupedConnLine, vpcResourceID string) drawio.IconTreeNodeInterface {
+func (d *DrawioOutputFormatter) lineRouter(src, dst EndpointElem, vpcResourceID string) drawio.IconTreeNodeInterface {
 	if d.cConfigs.Config(vpcResourceID).IsMultipleVPCsConfig {
 		return d.multiVpcRouters[vpcResourceID]
 	}
+	if d.collapsesSubnets() {
+		return nil
+	}
 	var routeredEP EndpointElem
 	switch {
-	case line.Dst.IsExternal() && endpointElemResources(line.Dst)[0].(Node).IsPublicInternet():
-		routeredEP = line.Src
-	case line.Src.IsExternal() && endpointElemResources(line.Src)[0].(Node).IsPublicInternet():
-		routeredEP = line.Dst
+	case dst.IsExternal() && endpointElemResources(dst)[0].(Node).IsPublicInternet():
+		routeredEP = src
+	case src.IsExternal() && endpointElemResources(src)[0].(Node).IsPublicInternet():
+		routeredEP = dst
 	default:
 		return nil
 	}
@@ -202,6 +233,49 @@ func (d *DrawioOutputFormatter) lineRouter(line *groupedConnLine, vpcResourceID
 	return nil
 }
 
+// shownElems() returns the elements representing an endpoint element on the map:
+// on a collapsed map, see collapsedElems(). otherwise, a group of nodes in several subnets (grouped by a user-defined label)
+// is split to its parts in each subnet, since a group of icons is drawn inside a subnet
+func (d *DrawioOutputFormatter) shownElems(ep EndpointElem) []EndpointElem {
+	if d.isCollapsed() {
+		return d.collapsedElems(ep)
+	}
+	grouped, ok := ep.(*groupedEndpointsElems)
+	if !ok {
+		return []EndpointElem{ep}
+	}
+	subnetsParts := map[string]groupedEndpointsElems{}
+	subnetsOrder := []string{}
+	for _, elem := range *grouped {
+		node, isInternalNode := elem.(InternalNodeIntf)
+		if !isInternalNode {
+			return []EndpointElem{ep}
+		}
+		subnetUID := node.Subnet().UID()
+		if _, ok := subnetsParts[subnetUID]; !ok {
+			subnetsOrder = append(subnetsOrder, subnetUID)
+		}
+		subnetsParts[subnetUID] = append(subnetsParts[subnetUID], elem)
+	}
+	if len(subnetsOrder) == 1 {
+		return []EndpointElem{ep}
+	}
+	res := make([]EndpointElem, len(subnetsOrder))
+	for i, subnetUID := range subnetsOrder {
+		part := subnetsParts[subnetUID]
+		if len(part) == 1 {
+			res[i] = part[0]
+			continue
+		}
+		// the same part of several groups is represented by the same element:
+		if _, ok := d.subnetsGroups[part.UID()]; !ok {
+			d.subnetsGroups[part.UID()] = &part
+		}
+		res[i] = d.subnetsGroups[part.UID()]
+	}
+	return res
+}
+
 // createEdges() has three steps:
 // 1. union edges that have the same src/dst/direction with different labels to one edge
 // 2. union two edges with opposite direction and the same labels to one edge
@@ -214,11 +288,30 @@ func (d *DrawioOutputFormatter) createEdges() {
 		router drawio.IconTreeNodeInterface
 	}
 	edgeLabels := map[edgeKeyForLabels][]string{}
+	edgeConns := map[edgeKeyForLabels]*netset.TransportSet{}
 	for vpcResourceID, vpcConn := range d.gConns {
 		for _, line := range vpcConn.GroupedLines {
-			router := d.lineRouter(line, vpcResourceID)
-			k := edgeKeyForLabels{line.Src, line.Dst, router}
-			edgeLabels[k] = append(edgeLabels[k], line.ConnLabel(false))
+			for _, src := range d.shownElems(line.Src) {
+				for _, dst := range d.shownElems(line.Dst) {
+					if src == dst {
+						// a connection inside a collapsed square is not shown
+						continue
+					}
+					router := d.lineRouter(src, dst, vpcResourceID)
+					k := edgeKeyForLabels{src, dst, router}
+					edgeLabels[k] = append(edgeLabels[k], line.ConnLabel(false))
+					if edgeConns[k] == nil {
+						edgeConns[k] = NoConns()
+					}
+					edgeConns[k] = edgeConns[k].Union(line.CommonProperties.Conn.allConn)
+				}
+			}
+		}
+	}
+	if d.isCollapsed() {
+		// the connection labels of an aggregated edge are merged to the label of the union of the connections:
+		for k, conn := range edgeConns {
+			edgeLabels[k] = []string{collapsedEdgeLabel(conn)}
 		}
 	}
 	// 2.union for opposite direction:
@@ -243,7 +336,12 @@ func (d *DrawioOutputFormatter) createEdges() {
 	}
 	// 3. create TreeNodes:
 	for e, directed := range isEdgeDirected {
-		ei := &edgeInfo{e.src, e.dst, e.label, directed}
+		conns := edgeConns[edgeKeyForLabels{e.src, e.dst, e.router}]
+		if !directed {
+			// an undirected edge carries the connections of both directions:
+			conns = conns.Union(edgeConns[edgeKeyForLabels{e.dst, e.src, e.router}])
+		}
+		ei := &edgeInfo{e.src, e.dst, e.label, directed, conns}
 		eTn := d.gen.TreeNode(ei)
 		if eTn != nil && e.router != nil {
 			eTn.(*drawio.ConnectivityTreeNode).SetRouter(e.router)
@@ -333,8 +431,9 @@ type ArchDrawioOutputFormatter struct {
 	DrawioOutputFormatter
 }
 
-func newArchDrawioOutputFormatter(outFormat OutFormat, lbAbstraction bool) *ArchDrawioOutputFormatter {
-	return &ArchDrawioOutputFormatter{*newDrawioOutputFormatter(outFormat, lbAbstraction)}
+func newArchDrawioOutputFormatter(outFormat OutFormat, lbAbstraction bool, viewLevel ViewLevel,
+	layoutSeed drawio.LayoutSeed) *ArchDrawioOutputFormatter {
+	return &ArchDrawioOutputFormatter{*newDrawioOutputFormatter(outFormat, lbAbstraction, viewLevel, layoutSeed)}
 }
 func (d *ArchDrawioOutputFormatter) WriteOutput(cConfigs *MultipleVPCConfigs,
 	conn map[string]*VPCConnectivity,
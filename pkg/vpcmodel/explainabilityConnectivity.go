@@ -9,6 +9,7 @@ package vpcmodel
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/np-guard/models/pkg/netset"
 )
@@ -88,6 +89,7 @@ type Explanation struct {
 	// [required due to computation with disjoint ip-blocks]
 	groupedLines    []*groupedConnLine
 	allRulesDetails *rulesDetails // all rules of the VPCConfig with details; used by printing functionality
+	remediation     *remediation  // proposed rule additions enabling the queried connection; nil if not requested
 }
 
 // ExplainConnectivity returns Explanation object, that explains connectivity of a single <src, dst> couple given by the user
@@ -146,7 +148,7 @@ func explainConnectivityForVPC(c *VPCConfig, src, dst string, srcNodes, dstNodes
 	// computes rulesDetails which contains a list of all rules of the VPCConfig; these are used by explain printing
 	// functionality. we compute it here so that it is computed only once
 	return &Explanation{c, connQuery, &rulesAndDetails, src, dst, srcNodes, dstNodes,
-		hasIksNode, groupedLines.GroupedLines, allRulesDetails}, nil
+		hasIksNode, groupedLines.GroupedLines, allRulesDetails, nil}, nil
 }
 
 // computeExplainRules computes the egress and ingress rules contributing to the (existing or missing) connection <src, dst>
@@ -373,6 +375,50 @@ func getRulesOfConnection(c *VPCConfig, src, dst Node,
 	return allowRulesOfConnection, denyRulesOfConnection, nil
 }
 
+// ConnectionEnablers returns the resources enabling the connection conn from src to dst: the routing resource
+// between them (e.g. a fip or a tgw), nil if there is none, and the allow rules of the nacls and sgs enabling it,
+// ordered by layer, table and rule index
+func (c *VPCConfig) ConnectionEnablers(src, dst Node, conn *netset.TransportSet) (router RoutingResource,
+	rules []RuleOfFilter, err error) {
+	router, _, err = c.getRoutingResource(src, dst)
+	if err != nil {
+		return nil, nil, err
+	}
+	allowRules, _, err := getRulesOfConnection(c, src, dst, conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, layer := range []string{NaclLayer, SecurityGroupLayer} {
+		filterLayer := c.GetFilterTrafficResourceOfKind(layer)
+		if filterLayer == nil {
+			continue
+		}
+		layerRules, errRules := filterLayer.GetRules()
+		if errRules != nil {
+			return nil, nil, errRules
+		}
+		enablingRules := []RuleOfFilter{}
+		for _, rulesPerLayer := range []rulesInLayers{allowRules.egressRules, allowRules.ingressRules} {
+			for _, rulesInTable := range rulesPerLayer[layer] {
+				for i := range layerRules {
+					if layerRules[i].Filter.FilterIndex == rulesInTable.TableIndex &&
+						slices.Contains(rulesInTable.Rules, layerRules[i].RuleIndex) {
+						enablingRules = append(enablingRules, layerRules[i])
+					}
+				}
+			}
+		}
+		slices.SortFunc(enablingRules, func(r1, r2 RuleOfFilter) int {
+			if r1.Filter.FilterName != r2.Filter.FilterName {
+				return strings.Compare(r1.Filter.FilterName, r2.Filter.FilterName)
+			}
+			return r1.RuleIndex - r2.RuleIndex
+		})
+		rules = append(rules, enablingRules...)
+	}
+	return router, rules, nil
+}
+
 func (rules rulesInLayers) updateRulesPerLayerIfNonEmpty(layer string, rulesFilter *[]RulesInTable) {
 	if len(*rulesFilter) > 0 {
 		rules[layer] = *rulesFilter
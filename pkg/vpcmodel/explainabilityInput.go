@@ -25,6 +25,7 @@ type ExplanationArgs struct {
 	dstMinPort int64
 	dstMaxPort int64
 	Detail     bool
+	Remediate  bool // propose rule additions enabling the queried connection
 }
 
 func (e *ExplanationArgs) Src() string {
@@ -93,7 +93,11 @@ func endPointInterpretation(c *VPCConfig, userInput string, nodes []Node) string
 // String main printing function for the Explanation struct - returns a string with the explanation
 func (explanation *Explanation) String(verbose bool) string {
 	if explanation.c == nil { // no VPCConfig - missing cross-VPC router (tgw)
-		return explainMissingCrossVpcRouter(explanation.src, explanation.dst, explanation.connQuery)
+		missingRouterStr := explainMissingCrossVpcRouter(explanation.src, explanation.dst, explanation.connQuery)
+		if explanation.remediation != nil {
+			missingRouterStr += newLine + explanation.remediationStr()
+		}
+		return missingRouterStr
 	}
 	linesStr := make([]string, len(explanation.groupedLines))
 	groupedLines := explanation.groupedLines
@@ -107,7 +111,15 @@ func (explanation *Explanation) String(verbose bool) string {
 		iksNodeComment = "* Analysis of the connectivity of cluster worker nodes is under the assumption that the " +
 			"only security groups applied to them are the VPC default and the IKS generated SG\n"
 	}
-	return strings.Join(linesStr, newLine) + newLine + iksNodeComment
+	return strings.Join(linesStr, newLine) + newLine + iksNodeComment + explanation.remediationStr()
+}
+
+// remediationStr returns the remediation section of the explanation, if remediation was requested
+func (explanation *Explanation) remediationStr() string {
+	if explanation.remediation == nil {
+		return emptyString
+	}
+	return explanation.remediation.String()
 }
 
 // missing cross vpc router
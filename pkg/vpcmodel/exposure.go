@@ -0,0 +1,372 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+)
+
+// Functions for the computation of the exposure of internal endpoints to external networks
+// (Public Internet and Service Network)
+
+const (
+	inboundExposureHeader  = "Inbound exposure (connections from external networks to internal endpoints):\n"
+	outboundExposureHeader = "Outbound exposure (connections from internal endpoints to external networks):\n"
+	noExposureMessage      = "No internal endpoint is exposed to external networks\n"
+	inboundStr             = "inbound"
+	outboundStr            = "outbound"
+	mdExposureHeader       = "| direction | src | dst | conn | via | rules |\n" +
+		"|-----------|-----|-----|------|-----|-------|"
+)
+
+// exposureLine captures the connectivity between a single internal endpoint and a group of external nodes,
+// all sharing the same connection, the same router (fip/pgw/igw/lb) and the same filter rules enabling the connection
+type exposureLine struct {
+	endpoint  Node
+	external  *groupedExternalNodes
+	isInbound bool // true if the connection is from the external nodes to the endpoint
+	conn      *detailedConn
+	router    VPCResourceIntf // a RoutingResource, or a public load balancer
+	// rules of the endpoint's filters enabling the connection; ingress rules for inbound, egress for outbound
+	rules rulesInLayers
+}
+
+// exposureAnalysis holds the exposure of a single VPCConfig's internal endpoints to external networks
+type exposureAnalysis struct {
+	c            *VPCConfig
+	lines        []*exposureLine
+	rulesDetails *rulesDetails
+}
+
+// newExposureAnalysis computes from the given (non abstracted) connectivity all connections between internal
+// endpoints and external networks, in both directions.
+// the pool members of a public load balancer are exposed through the load balancer, to the Public Internet
+// addresses from which its private IPs are reachable.
+// lines of the same endpoint, direction, connection, router and rules are merged to one line, with
+// the union of external nodes
+func newExposureAnalysis(c *VPCConfig, conn *VPCConnectivity) (*exposureAnalysis, error) {
+	allRulesDetails, err := newRulesDetails(c)
+	if err != nil {
+		return nil, err
+	}
+	res := &exposureAnalysis{c: c, rulesDetails: allRulesDetails}
+	linesByKey := map[string]*exposureLine{}
+	addLine := func(line *exposureLine) {
+		key := line.key(res)
+		if existing, ok := linesByKey[key]; ok {
+			existing.addExternalNodes(line.external)
+			return
+		}
+		linesByKey[key] = line
+		res.lines = append(res.lines, line)
+	}
+	for src, srcMap := range conn.AllowedConnsCombinedResponsive {
+		for dst, extConn := range srcMap {
+			srcNode, srcIsNode := src.(Node)
+			dstNode, dstIsNode := dst.(Node)
+			if !srcIsNode || !dstIsNode || extConn.isEmpty() || srcNode.IsInternal() == dstNode.IsInternal() {
+				continue
+			}
+			line, err := res.newExposureLine(srcNode, dstNode, extConn)
+			if err != nil {
+				return nil, err
+			}
+			addLine(line)
+		}
+	}
+	// lines of the pool members are computed from the final lines of the private IPs of the load balancers
+	membersLines := []*exposureLine{}
+	for _, line := range res.lines {
+		lb, isLB := line.router.(LoadBalancer)
+		if !isLB {
+			continue
+		}
+		lines, err := res.poolMembersLines(conn, lb, line)
+		if err != nil {
+			return nil, err
+		}
+		membersLines = append(membersLines, lines...)
+	}
+	for _, line := range membersLines {
+		addLine(line)
+	}
+	return res, nil
+}
+
+// loadBalancerOfNode returns the load balancer whose private IP is the given node, nil if there is none
+func (c *VPCConfig) loadBalancerOfNode(node Node) LoadBalancer {
+	for _, lb := range c.LoadBalancers {
+		for _, lbNode := range lb.Nodes() {
+			if lbNode.UID() == node.UID() {
+				return lb
+			}
+		}
+	}
+	return nil
+}
+
+// poolMembersLines returns the inbound exposure lines of the pool members reachable from the private IP of
+// the given exposure line of a public load balancer; the connection of each is the one from the private IP
+// to the member, enabled by the member's ingress rules
+func (e *exposureAnalysis) poolMembersLines(conn *VPCConnectivity, lb LoadBalancer,
+	privateIPLine *exposureLine) ([]*exposureLine, error) {
+	res := []*exposureLine{}
+	for _, pool := range lb.Pools() {
+		for _, member := range pool.Members {
+			memberConn, ok := conn.AllowedConnsCombinedResponsive[privateIPLine.endpoint][member.Node]
+			if !ok || memberConn.isEmpty() {
+				continue
+			}
+			allowRules, _, err := getRulesOfConnection(e.c, privateIPLine.endpoint, member.Node, memberConn.allConn)
+			if err != nil {
+				return nil, err
+			}
+			external := slices.Clone(*privateIPLine.external)
+			res = append(res, &exposureLine{endpoint: member.Node, external: &external, isInbound: true,
+				conn: memberConn, router: lb, rules: allowRules.ingressRules})
+		}
+	}
+	return res, nil
+}
+
+// addExternalNodes adds to the line's external nodes those of the given group it does not contain yet
+func (l *exposureLine) addExternalNodes(external *groupedExternalNodes) {
+	for _, node := range *external {
+		if !slices.Contains(*l.external, node) {
+			*l.external = append(*l.external, node)
+		}
+	}
+}
+
+func (e *exposureAnalysis) newExposureLine(src, dst Node, conn *detailedConn) (*exposureLine, error) {
+	isInbound := dst.IsInternal()
+	endpoint, external := src, dst
+	if isInbound {
+		endpoint, external = dst, src
+	}
+	var router VPCResourceIntf
+	routingResource, _, err := e.c.getRoutingResource(src, dst)
+	if err != nil {
+		return nil, err
+	}
+	if routingResource != nil {
+		router = routingResource
+	}
+	// the traffic from the Public Internet to the private IPs of a public load balancer is enabled by the load balancer
+	if lb := e.c.loadBalancerOfNode(endpoint); lb != nil && isInbound && external.IsPublicInternet() {
+		router = lb
+	}
+	allowRules, _, err := getRulesOfConnection(e.c, src, dst, conn.allConn)
+	if err != nil {
+		return nil, err
+	}
+	rules := allowRules.egressRules
+	if isInbound {
+		rules = allowRules.ingressRules
+	}
+	externalNode, ok := external.(*ExternalNetwork)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type of external node %s", external.NameForAnalyzerOut(e.c))
+	}
+	return &exposureLine{endpoint: endpoint, external: &groupedExternalNodes{externalNode}, isInbound: isInbound,
+		conn: conn, router: router, rules: rules}, nil
+}
+
+// key of an exposure line, lines with the same key are merged to one line
+func (l *exposureLine) key(e *exposureAnalysis) string {
+	externalKind := serviceNetworkNodeName
+	if (*l.external)[0].IsPublicInternet() {
+		externalKind = publicInternetNodeName
+	}
+	return strings.Join([]string{l.endpoint.UID(), l.directionStr(), externalKind, l.conn.string(),
+		l.routerStr(e.c), l.rulesStr(e.rulesDetails)}, semicolon)
+}
+
+func (l *exposureLine) directionStr() string {
+	if l.isInbound {
+		return inboundStr
+	}
+	return outboundStr
+}
+
+func (l *exposureLine) srcAndDst() (src, dst EndpointElem) {
+	if l.isInbound {
+		return l.external, l.endpoint
+	}
+	return l.endpoint, l.external
+}
+
+// srcAndDstJSON is as srcAndDst, with the grouped external nodes represented by a single external network
+// whose cidr is the list of the merged cidrs of the group
+func (l *exposureLine) srcAndDstJSON() (src, dst EndpointElem) {
+	external := &ExternalNetwork{ResourceType: l.external.resourceType(), CidrStr: l.external.String()}
+	if l.isInbound {
+		return external, l.endpoint
+	}
+	return l.endpoint, external
+}
+
+// routerStr returns the kind and name of the router enabling the connection (e.g. FloatingIP fip1 52.1.2.3)
+func (l *exposureLine) routerStr(c *VPCConfig) string {
+	if l.router == nil {
+		return emptyString
+	}
+	res := []string{l.router.Kind()}
+	name, externalIP := l.router.Name(), emptyString
+	if routingResource, ok := l.router.(RoutingResource); ok {
+		name, externalIP = routingResource.NameForAnalyzerOut(c), routingResource.ExternalIP()
+	}
+	for _, str := range []string{name, externalIP} {
+		if str != emptyString {
+			res = append(res, str)
+		}
+	}
+	return strings.Join(res, space)
+}
+
+// rulesStr returns the detailed list of the rules enabling the connection, in order of evaluation
+func (l *exposureLine) rulesStr(allRulesDetails *rulesDetails) string {
+	strSlice := []string{}
+	for _, layer := range getLayersToPrint(allFiltersRelevant(), l.isInbound) {
+		if rulesInLayer, ok := l.rules[layer]; ok {
+			strSlice = append(strSlice, allRulesDetails.stringDetailsOfLayer(layer, rulesInLayer))
+		}
+	}
+	return strings.Join(strSlice, emptyString)
+}
+
+// rulesListStr returns the list of the rules enabling the connection as a one line string
+func (l *exposureLine) rulesListStr(allRulesDetails *rulesDetails) string {
+	strSlice := []string{}
+	for _, layer := range getLayersToPrint(allFiltersRelevant(), l.isInbound) {
+		for _, rulesInTable := range l.rules[layer] {
+			for _, rule := range allRulesDetails.rulesJSON(layer, rulesInTable.TableIndex, rulesInTable.Rules) {
+				strSlice = append(strSlice, rule.Layer+space+rule.Table+": "+rule.Description)
+			}
+		}
+	}
+	return strings.Join(strSlice, "<br>")
+}
+
+func allFiltersRelevant() map[string]bool {
+	return map[string]bool{NaclLayer: true, SecurityGroupLayer: true}
+}
+
+func (l *exposureLine) string(c *VPCConfig, allRulesDetails *rulesDetails) string {
+	src, dst := l.srcAndDst()
+	rulesHeader := "\tEgress rules:\n"
+	if l.isInbound {
+		rulesHeader = "\tIngress rules:\n"
+	}
+	return getConnectionStr(src.NameForAnalyzerOut(c), dst.NameForAnalyzerOut(c), l.conn.string(), "") +
+		"\tvia " + l.routerStr(c) + newLine + rulesHeader + l.rulesStr(allRulesDetails)
+}
+
+func (e *exposureAnalysis) hasStatelessConns() bool {
+	for _, line := range e.lines {
+		if !line.conn.TCPRspDisable.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *exposureAnalysis) String() string {
+	if len(e.lines) == 0 {
+		return noExposureMessage
+	}
+	inbound, outbound := []string{}, []string{}
+	for _, line := range e.lines {
+		if line.isInbound {
+			inbound = append(inbound, line.string(e.c, e.rulesDetails))
+		} else {
+			outbound = append(outbound, line.string(e.c, e.rulesDetails))
+		}
+	}
+	sort.Strings(inbound)
+	sort.Strings(outbound)
+	res := []string{}
+	if len(inbound) > 0 {
+		res = append(res, inboundExposureHeader+strings.Join(inbound, newLine))
+	}
+	if len(outbound) > 0 {
+		res = append(res, outboundExposureHeader+strings.Join(outbound, newLine))
+	}
+	return strings.Join(res, newLine)
+}
+
+func (e *exposureAnalysis) mdLines() []string {
+	lines := make([]string, len(e.lines))
+	for i, line := range e.lines {
+		src, dst := line.srcAndDst()
+		lines[i] = fmt.Sprintf("| %s | %s | %s | %s | %s | %s |", line.directionStr(), src.NameForAnalyzerOut(e.c),
+			dst.NameForAnalyzerOut(e.c), line.conn.string(), line.routerStr(e.c), line.rulesListStr(e.rulesDetails))
+	}
+	return lines
+}
+
+type exposureLineJSON struct {
+	Direction          string         `json:"direction"`
+	Src                EndpointElem   `json:"src"`
+	Dst                EndpointElem   `json:"dst"`
+	Conn               netset.Details `json:"conn"`
+	UnidirectionalConn netset.Details `json:"unidirectional_conn,omitempty"`
+	Router             string         `json:"router"`
+	Rules              []ruleJSON     `json:"rules"`
+}
+
+type allExposure struct {
+	Exposure []exposureLineJSON `json:"exposure"`
+}
+
+func (e *exposureAnalysis) toJSON() allExposure {
+	lines := make([]exposureLineJSON, len(e.lines))
+	for i, line := range e.lines {
+		src, dst := line.srcAndDstJSON()
+		rules := []ruleJSON{}
+		for _, layer := range getLayersToPrint(allFiltersRelevant(), line.isInbound) {
+			for _, rulesInTable := range line.rules[layer] {
+				rules = append(rules, e.rulesDetails.rulesJSON(layer, rulesInTable.TableIndex, rulesInTable.Rules)...)
+			}
+		}
+		lines[i] = exposureLineJSON{Direction: line.directionStr(), Src: src, Dst: dst,
+			Conn: netset.ToJSON(line.conn.nonTCPAndResponsiveTCPComponent()), Router: line.routerStr(e.c), Rules: rules}
+		if !line.conn.TCPRspDisable.IsEmpty() {
+			lines[i].UnidirectionalConn = netset.ToJSON(line.conn.TCPRspDisable)
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Direction != lines[j].Direction {
+			return lines[i].Direction < lines[j].Direction
+		}
+		if lines[i].Src.NameForAnalyzerOut(nil) != lines[j].Src.NameForAnalyzerOut(nil) {
+			return lines[i].Src.NameForAnalyzerOut(nil) < lines[j].Src.NameForAnalyzerOut(nil)
+		}
+		if lines[i].Dst.NameForAnalyzerOut(nil) != lines[j].Dst.NameForAnalyzerOut(nil) {
+			return lines[i].Dst.NameForAnalyzerOut(nil) < lines[j].Dst.NameForAnalyzerOut(nil)
+		}
+		if lines[i].Router != lines[j].Router {
+			return lines[i].Router < lines[j].Router
+		}
+		return rulesJSONStr(lines[i].Rules) < rulesJSONStr(lines[j].Rules)
+	})
+	return allExposure{Exposure: lines}
+}
+
+// rulesJSONStr returns the list of the given rules as a string, to order lines that differ only in their rules
+func rulesJSONStr(rules []ruleJSON) string {
+	strSlice := make([]string, len(rules))
+	for i, rule := range rules {
+		strSlice[i] = fmt.Sprintf("%s %s %d", rule.Layer, rule.Table, rule.RuleIndex)
+	}
+	return strings.Join(strSlice, comma)
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+)
+
+// Functions for the report of the effective connectivity of each filter (nacl/sg) separately,
+// independent of any pair of endpoints
+
+const (
+	notAttachedStr  = "not attached to any resource"
+	mdFiltersHeader = "| filter | applied to | direction | remote | conn | allow rules | deny rules |\n" +
+		"|--------|------------|-----------|--------|------|-------------|------------|"
+)
+
+// FilterConnectivity is the connectivity implied by a single filter alone on some of the resources attached to it;
+// e.g. on a subnet for a nacl, or on the members of a security group sharing the same connectivity
+type FilterConnectivity struct {
+	Filter     Filter
+	Resources  []VPCResourceIntf
+	LocalRange string // if not empty, the connectivity is only of this range within the resources
+	Ingress    []*RemoteConnectivity
+	Egress     []*RemoteConnectivity
+}
+
+// filterReport is the connectivity of a single filter, per groups of the resources attached to it
+type filterReport struct {
+	layer        string // the layer kind, e.g. NaclLayer
+	filter       Filter
+	attached     []VPCResourceIntf
+	connectivity []*FilterConnectivity
+}
+
+// filtersAnalysis holds the effective connectivity of all filters of a single VPCConfig
+type filtersAnalysis struct {
+	c            *VPCConfig
+	filters      []*filterReport
+	rulesDetails *rulesDetails
+}
+
+// newFiltersAnalysis computes the connectivity of each nacl and each sg of c, on each of its attached resources;
+// only remotes with allowed connections are kept
+func newFiltersAnalysis(c *VPCConfig) (*filtersAnalysis, error) {
+	allRulesDetails, err := newRulesDetails(c)
+	if err != nil {
+		return nil, err
+	}
+	res := &filtersAnalysis{c: c, rulesDetails: allRulesDetails}
+	for _, layer := range []string{NaclLayer, SecurityGroupLayer} {
+		filterLayer := c.GetFilterTrafficResourceOfKind(layer)
+		if filterLayer == nil {
+			continue
+		}
+		layerFilters := map[Filter]*filterReport{}
+		for filter, attached := range filterLayer.GetFiltersAttachedResources() {
+			layerFilters[filter] = &filterReport{layer: layer, filter: filter, attached: sortedResources(c, attached)}
+		}
+		connectivity, err := filterLayer.ConnectivityPerFilter()
+		if err != nil {
+			return nil, err
+		}
+		for _, filterConnectivity := range connectivity {
+			report, ok := layerFilters[filterConnectivity.Filter]
+			if !ok {
+				return nil, fmt.Errorf("connectivity of %s %s which is not in its layer", filterConnectivity.Filter.LayerName,
+					filterConnectivity.Filter.FilterName)
+			}
+			filterConnectivity.Resources = sortedResources(c, filterConnectivity.Resources)
+			filterConnectivity.Ingress = allowedRemotes(filterConnectivity.Ingress)
+			filterConnectivity.Egress = allowedRemotes(filterConnectivity.Egress)
+			report.connectivity = append(report.connectivity, filterConnectivity)
+		}
+		layerReports := make([]*filterReport, 0, len(layerFilters))
+		for _, report := range layerFilters {
+			sort.Slice(report.connectivity, func(i, j int) bool {
+				iNames, jNames := resourcesNames(c, report.connectivity[i].Resources), resourcesNames(c, report.connectivity[j].Resources)
+				if iNames != jNames {
+					return iNames < jNames
+				}
+				return report.connectivity[i].LocalRange < report.connectivity[j].LocalRange
+			})
+			layerReports = append(layerReports, report)
+		}
+		sort.Slice(layerReports, func(i, j int) bool {
+			if layerReports[i].filter.FilterName != layerReports[j].filter.FilterName {
+				return layerReports[i].filter.FilterName < layerReports[j].filter.FilterName
+			}
+			return layerReports[i].filter.FilterIndex < layerReports[j].filter.FilterIndex
+		})
+		res.filters = append(res.filters, layerReports...)
+	}
+	return res, nil
+}
+
+// allowedRemotes returns the remotes with allowed connections, sorted by their ip-blocks
+func allowedRemotes(remotes []*RemoteConnectivity) []*RemoteConnectivity {
+	res := []*RemoteConnectivity{}
+	for _, remote := range remotes {
+		if !remote.Conn.IsEmpty() {
+			res = append(res, remote)
+		}
+	}
+	sortRemotes(res)
+	return res
+}
+
+func sortedResources(c *VPCConfig, resources []VPCResourceIntf) []VPCResourceIntf {
+	res := slices.Clone(resources)
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].NameForAnalyzerOut(c) < res[j].NameForAnalyzerOut(c)
+	})
+	return res
+}
+
+func resourcesNames(c *VPCConfig, resources []VPCResourceIntf) string {
+	names := make([]string, len(resources))
+	for i, resource := range resources {
+		names[i] = resource.NameForAnalyzerOut(c)
+	}
+	return strings.Join(names, comma)
+}
+
+func (f *filterReport) name() string {
+	return f.filter.LayerName + space + f.filter.FilterName
+}
+
+// appliedToStr returns the resources to which the connectivity applies, along with the local range if relevant
+func (fc *FilterConnectivity) appliedToStr(c *VPCConfig) string {
+	res := resourcesNames(c, fc.Resources)
+	if fc.LocalRange != emptyString {
+		res += " (local range " + fc.LocalRange + ")"
+	}
+	return res
+}
+
+// rulesDescriptions returns the descriptions of the given rules of the report's filter, sorted by rules indexes
+func (fa *filtersAnalysis) rulesDescriptions(report *filterReport, rules []int) []string {
+	rulesJSON := fa.rulesDetails.rulesJSON(report.layer, report.filter.FilterIndex, rules)
+	res := make([]string, len(rulesJSON))
+	for i := range rulesJSON {
+		res[i] = rulesJSON[i].Description
+	}
+	return res
+}
+
+func (fa *filtersAnalysis) remotesStr(report *filterReport, header string, remotes []*RemoteConnectivity) string {
+	if len(remotes) == 0 {
+		return "\t\t" + header + " No Connections\n"
+	}
+	res := "\t\t" + header + newLine
+	for _, remote := range remotes {
+		res += "\t\t\t" + remoteConnectivityStr(remote) + newLine
+		for _, rule := range fa.rulesDescriptions(report, remote.AllowRules) {
+			res += "\t\t\t\tallow rule: " + rule + newLine
+		}
+		for _, rule := range fa.rulesDescriptions(report, remote.DenyRules) {
+			res += "\t\t\t\tdeny rule: " + rule + newLine
+		}
+	}
+	return res
+}
+
+// String returns, per filter, its attached resources and the ingress and egress connectivity it allows on them,
+// with the rules contributing to each allowed connection
+func (fa *filtersAnalysis) String() string {
+	filtersStr := make([]string, len(fa.filters))
+	for i, report := range fa.filters {
+		filterStr := report.name() + newLine
+		if len(report.attached) == 0 {
+			filterStr += "\t" + notAttachedStr + newLine
+		} else {
+			filterStr += "\tattached to: " + resourcesNames(fa.c, report.attached) + newLine
+		}
+		for _, filterConnectivity := range report.connectivity {
+			filterStr += "\tconnectivity of " + filterConnectivity.appliedToStr(fa.c) + ":\n" +
+				fa.remotesStr(report, "Ingress:", filterConnectivity.Ingress) +
+				fa.remotesStr(report, "Egress:", filterConnectivity.Egress)
+		}
+		filtersStr[i] = filterStr
+	}
+	return strings.Join(filtersStr, newLine)
+}
+
+func (fa *filtersAnalysis) mdLines() []string {
+	lines := []string{}
+	for _, report := range fa.filters {
+		if len(report.attached) == 0 {
+			lines = append(lines, fmt.Sprintf("| %s | %s | | | | | |", report.name(), notAttachedStr))
+			continue
+		}
+		for _, filterConnectivity := range report.connectivity {
+			for _, direction := range []string{ingressStr, egressStr} {
+				remotes := filterConnectivity.Ingress
+				if direction == egressStr {
+					remotes = filterConnectivity.Egress
+				}
+				for _, remote := range remotes {
+					lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |", report.name(),
+						filterConnectivity.appliedToStr(fa.c), direction, remote.Remote.ToIPRanges(), common.LongString(remote.Conn),
+						strings.Join(fa.rulesDescriptions(report, remote.AllowRules), "<br>"),
+						strings.Join(fa.rulesDescriptions(report, remote.DenyRules), "<br>")))
+				}
+			}
+		}
+	}
+	return lines
+}
+
+type filterConnectivityJSON struct {
+	AppliedTo  []string                 `json:"applied_to"`
+	LocalRange string                   `json:"local_range,omitempty"`
+	Ingress    []remoteConnectivityJSON `json:"ingress"`
+	Egress     []remoteConnectivityJSON `json:"egress"`
+}
+
+type filterReportJSON struct {
+	Layer        string                   `json:"layer"`
+	Name         string                   `json:"name"`
+	AttachedTo   []groupMemberJSON        `json:"attached_to"`
+	Connectivity []filterConnectivityJSON `json:"connectivity"`
+}
+
+type allFiltersConnectivity struct {
+	Filters []filterReportJSON `json:"filters_connectivity"`
+}
+
+func (fa *filtersAnalysis) remotesJSON(report *filterReport, remotes []*RemoteConnectivity) []remoteConnectivityJSON {
+	res := make([]remoteConnectivityJSON, len(remotes))
+	for i, remote := range remotes {
+		res[i] = newRemoteConnectivityJSON(remote,
+			fa.rulesDetails.rulesJSON(report.layer, report.filter.FilterIndex, remote.AllowRules),
+			fa.rulesDetails.rulesJSON(report.layer, report.filter.FilterIndex, remote.DenyRules))
+	}
+	return res
+}
+
+func (fa *filtersAnalysis) toJSON() allFiltersConnectivity {
+	res := make([]filterReportJSON, len(fa.filters))
+	for i, report := range fa.filters {
+		attachedTo := make([]groupMemberJSON, len(report.attached))
+		for j, resource := range report.attached {
+			attachedTo[j] = getMemberJSON(resource)
+		}
+		connectivity := make([]filterConnectivityJSON, len(report.connectivity))
+		for j, filterConnectivity := range report.connectivity {
+			appliedTo := make([]string, len(filterConnectivity.Resources))
+			for k, resource := range filterConnectivity.Resources {
+				appliedTo[k] = resource.NameForAnalyzerOut(fa.c)
+			}
+			connectivity[j] = filterConnectivityJSON{AppliedTo: appliedTo, LocalRange: filterConnectivity.LocalRange,
+				Ingress: fa.remotesJSON(report, filterConnectivity.Ingress), Egress: fa.remotesJSON(report, filterConnectivity.Egress)}
+		}
+		res[i] = filterReportJSON{Layer: report.filter.LayerName, Name: report.filter.FilterName, AttachedTo: attachedTo,
+			Connectivity: connectivity}
+	}
+	return allFiltersConnectivity{Filters: res}
+}
@@ -9,6 +9,7 @@ package vpcmodel
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 
@@ -221,13 +222,27 @@ func endpointElemResources(e EndpointElem) []VPCResourceIntf {
 type groupedEndpointsElems []EndpointElem
 
 func (g *groupedEndpointsElems) Name() string {
-	return listEndpointElemStrWithConfig(*g, EndpointElem.NameForAnalyzerOut)
+	membersNames := listEndpointElemStrWithConfig(*g, EndpointElem.NameForAnalyzerOut)
+	if label := endpointLabel(g); label != nil {
+		return label.groupName(len(*g), membersNames)
+	}
+	return membersNames
 }
 
 func (g *groupedEndpointsElems) SynthesisResourceName() string {
+	if g.isNamedByLabel() {
+		// the name of a label is unique within its vpc
+		return (*g)[0].(VPCResourceIntf).VPC().Name() + Deliminator + g.Name()
+	}
 	return g.Name()
 }
 
+// isNamedByLabel returns true if the group is all the resources of a user-defined label, and thus named by the label
+func (g *groupedEndpointsElems) isNamedByLabel() bool {
+	label := endpointLabel(g)
+	return label != nil && label.members == len(*g)
+}
+
 func (g *groupedEndpointsElems) AsNamesList() []string {
 	names := make([]string, len(*g))
 	for i, ep := range *g {
@@ -237,6 +252,17 @@ func (g *groupedEndpointsElems) AsNamesList() []string {
 	return names
 }
 
+// synthesisNamesList returns the sorted synthesis names of the members of the group; several members may have the
+// same synthesis name, e.g. the reserved IPs of a vpe
+func (g *groupedEndpointsElems) synthesisNamesList() []string {
+	names := make([]string, len(*g))
+	for i, ep := range *g {
+		names[i] = ep.SynthesisResourceName()
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
 func (g *groupedEndpointsElems) SynthesisKind() spec.ResourceType {
 	return spec.ResourceTypeSegment
 }
@@ -253,7 +279,7 @@ func (g *groupedEndpointsElems) NameForAnalyzerOut(c *VPCConfig) string {
 		}
 	}
 	// add the vpc prefix only once for grouped elements which are always of the same VPC
-	if prefix != "" && len(*g) > 1 {
+	if prefix != "" && len(*g) > 1 && endpointLabel(g) == nil {
 		return prefix + "[" + g.Name() + "]"
 	}
 	return prefix + g.Name()
@@ -339,6 +365,16 @@ func getSubnetOrVPCUID(ep EndpointElem) string {
 	return UID
 }
 
+// given an endpoint representing a vsi or a subnet, or a group of them,
+// returns the scope within which it can be grouped with other endpoints:
+// its user-defined label if it has one (see GroupingLabels), otherwise its subnet or vpc as by getSubnetOrVPCUID
+func getGroupingScope(ep EndpointElem) string {
+	if label := endpointLabel(ep); label != nil {
+		return label.scope()
+	}
+	return getSubnetOrVPCUID(ep)
+}
+
 // group public internet ranges for vsis/subnets connectivity lines
 // internal (vsi/subnets) are added as is
 func (g *GroupConnLines) groupExternalAddresses(vsi, addConsistencyEdgesExternal bool) error {
@@ -354,7 +390,7 @@ func (g *GroupConnLines) groupExternalAddresses(vsi, addConsistencyEdgesExternal
 			// tcp responsive and non tcp component of the connection
 			if !conns.nonTCPAndResponsiveTCPComponent().IsEmpty() {
 				responsiveTCPAndNonTCP := &detailedConn{allConn: conns.nonTCPAndResponsiveTCPComponent(), nonTCP: conns.nonTCP,
-					tcpRspEnable: conns.tcpRspEnable, TCPRspDisable: NoConns()}
+					tcpRspEnable: conns.tcpRspEnable, TCPRspDisable: NoConns(), nonTCPRspDisable: conns.nonTCPRspDisable}
 				err := g.addLineToExternalGrouping(&res, src, dst, &groupedCommonProperties{Conn: responsiveTCPAndNonTCP,
 					groupingStrKey: conns.connStrPerConnectionType(true)})
 				if err != nil {
@@ -364,7 +400,7 @@ func (g *GroupConnLines) groupExternalAddresses(vsi, addConsistencyEdgesExternal
 			// tcp non-responsive component of the connection
 			if !conns.TCPRspDisable.IsEmpty() {
 				nonResponsiveTCP := &detailedConn{allConn: conns.TCPRspDisable, nonTCP: NoConns(), tcpRspEnable: NoConns(),
-					TCPRspDisable: conns.TCPRspDisable}
+					TCPRspDisable: conns.TCPRspDisable, nonTCPRspDisable: NoConns()}
 				err := g.addLineToExternalGrouping(&res, src, dst, &groupedCommonProperties{Conn: nonResponsiveTCP,
 					groupingStrKey: conns.connStrPerConnectionType(false)})
 				if err != nil {
@@ -505,14 +541,14 @@ func (g *GroupConnLines) groupLinesByKey(srcGrouping, groupVsi bool) (res []*gro
 // 1. Name of indexed endpoint (see #412) and its connection; the latter includes responsive/non-responsive details
 // 2. We do not want to group together vsis from different subnets for vsis analysis
 // or subnets of different vpcs for subnets analysis; thus the grouping is also by subnets/vpcs
-// of grouping targets
+// of grouping targets, or by their user-defined labels when these are given
 // e.g. :
 // v2 => v3
 // v2 => v3
 // can be grouped to
 // v1, v2 => v3 given that v1, v2 share the same subnet
 func getKeyOfGroupConnLines(grpIndex, grpTarget EndpointElem, connectionString string) string {
-	keyComponents := []string{grpIndex.UID(), connectionString, getSubnetOrVPCUID(grpTarget)}
+	keyComponents := []string{grpIndex.UID(), connectionString, getGroupingScope(grpTarget)}
 	return strings.Join(keyComponents, semicolon)
 }
 
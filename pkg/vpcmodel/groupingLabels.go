@@ -0,0 +1,187 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const tagKeyValueSeparator = ":"
+
+// GroupingLabels assigns user-defined labels, such as application tiers ("web", "db"), to the endpoints and subnets of the configs.
+// the label of a resource is one of:
+// 1. the value of a cloud resource tag with a given key (AWS tags, IBM user tags of the form key:value)
+// 2. the match of a regex on the resource name (or its first capturing group, if the regex has one)
+// 3. the label mapped to the resource name or uid in a mapping file
+// with grouping, endpoints (subnets) of the same label within a vpc are grouped together, rather than
+// endpoints of the same subnet (subnets of the same vpc), and the groups are named by their labels
+type GroupingLabels struct {
+	tagKey    string
+	nameRegex *regexp.Regexp
+	mapping   map[string]string // from resource name or uid to its label
+}
+
+// groupingLabel is the label of a group of resources of the same vpc, shared by all of them
+type groupingLabel struct {
+	name   string
+	vpcUID string
+	// number of resources with this label, a group of all of them is named by the label alone
+	members int
+}
+
+// labeledResource is implemented by the resources embedding VPCResource
+type labeledResource interface {
+	label() *groupingLabel
+	setLabel(label *groupingLabel)
+}
+
+func NewGroupingLabelsByTag(tagKey string) *GroupingLabels {
+	return &GroupingLabels{tagKey: tagKey}
+}
+
+func NewGroupingLabelsByRegex(expr string) (*GroupingLabels, error) {
+	nameRegex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grouping regex %s: %w", expr, err)
+	}
+	return &GroupingLabels{nameRegex: nameRegex}, nil
+}
+
+// NewGroupingLabelsFromFile reads a json mapping file, from each label to the names or uids of its resources, e.g.:
+// {"web": ["vsi1", "vsi2"], "db": ["db-vsi"]}
+func NewGroupingLabelsFromFile(fileName string) (*GroupingLabels, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	labelToResources := map[string][]string{}
+	if errParse := json.Unmarshal(content, &labelToResources); errParse != nil {
+		return nil, fmt.Errorf("failed to parse grouping mapping file %s: %w", fileName, errParse)
+	}
+	mapping := map[string]string{}
+	for label, resources := range labelToResources {
+		for _, resource := range resources {
+			if otherLabel, ok := mapping[resource]; ok && otherLabel != label {
+				return nil, fmt.Errorf("grouping mapping file %s maps %s to both %s and %s", fileName, resource, otherLabel, label)
+			}
+			mapping[resource] = label
+		}
+	}
+	return &GroupingLabels{mapping: mapping}, nil
+}
+
+// resourceNames returns the names by which a resource is labeled; a network interface is labeled also by its vsi name
+func resourceNames(resource VPCResourceIntf) []string {
+	names := []string{resource.Name(), resource.UID()}
+	if vsiNode, ok := resource.(interface{ VsiName() string }); ok {
+		names = append([]string{vsiNode.VsiName()}, names...)
+	}
+	return names
+}
+
+// labelName returns the label of a resource, or an empty string if it has none
+func (l *GroupingLabels) labelName(resource VPCResourceIntf) string {
+	switch {
+	case l.tagKey != "":
+		if tagged, ok := resource.(interface{ Tags() []string }); ok {
+			for _, tag := range tagged.Tags() {
+				if value, found := strings.CutPrefix(tag, l.tagKey+tagKeyValueSeparator); found {
+					return value
+				}
+			}
+		}
+	case l.nameRegex != nil:
+		for _, name := range resourceNames(resource) {
+			if match := l.nameRegex.FindStringSubmatch(name); match != nil {
+				if len(match) > 1 && match[1] != "" {
+					return match[1]
+				}
+				return match[0]
+			}
+		}
+	default:
+		for _, name := range resourceNames(resource) {
+			if label, ok := l.mapping[name]; ok {
+				return label
+			}
+		}
+	}
+	return ""
+}
+
+// SetGroupingLabels assigns the labels to the internal nodes and the subnets of the configs;
+// nodes and subnets are labeled separately, since they are grouped in different analyses
+func (c *MultipleVPCConfigs) SetGroupingLabels(labels *GroupingLabels) {
+	nodesLabels, subnetsLabels := map[string]*groupingLabel{}, map[string]*groupingLabel{}
+	labeledNodes, labeledSubnets := map[string]bool{}, map[string]bool{}
+	for _, config := range c.Configs() {
+		for _, node := range config.Nodes {
+			if node.IsInternal() {
+				labels.assign(node, nodesLabels, labeledNodes)
+			}
+		}
+		for _, subnet := range config.Subnets {
+			labels.assign(subnet, subnetsLabels, labeledSubnets)
+		}
+	}
+}
+
+// assign sets the label of a resource; a resource which is in several configs is counted once
+func (l *GroupingLabels) assign(resource VPCResourceIntf, vpcLabels map[string]*groupingLabel, labeledUIDs map[string]bool) {
+	labeled, ok := resource.(labeledResource)
+	name := l.labelName(resource)
+	if !ok || name == "" || resource.VPC() == nil {
+		return
+	}
+	key := resource.VPC().UID() + semicolon + name
+	if _, ok := vpcLabels[key]; !ok {
+		vpcLabels[key] = &groupingLabel{name: name, vpcUID: resource.VPC().UID()}
+	}
+	if !labeledUIDs[resource.UID()] {
+		labeledUIDs[resource.UID()] = true
+		vpcLabels[key].members++
+	}
+	labeled.setLabel(vpcLabels[key])
+}
+
+// endpointLabel returns the label of an endpoint, or of a group whose endpoints all have the same label; otherwise nil
+func endpointLabel(ep EndpointElem) *groupingLabel {
+	elements := []EndpointElem{ep}
+	if grouped, ok := ep.(*groupedEndpointsElems); ok {
+		elements = *grouped
+	}
+	var res *groupingLabel
+	for i, element := range elements {
+		labeled, ok := element.(labeledResource)
+		if !ok || labeled.label() == nil || (i > 0 && labeled.label() != res) {
+			return nil
+		}
+		res = labeled.label()
+	}
+	return res
+}
+
+// scope returns the grouping scope of the labeled endpoints, replacing their subnet or vpc
+func (l *groupingLabel) scope() string {
+	return strings.Join([]string{"label", l.vpcUID, l.name}, semicolon)
+}
+
+// groupName returns the name of a group with the label: the label alone if the group has all the resources of
+// the label, the name of its member if it has one, otherwise the label followed by the names of the group members
+func (l *groupingLabel) groupName(groupSize int, membersNames string) string {
+	switch groupSize {
+	case l.members:
+		return l.name
+	case 1:
+		return membersNames
+	}
+	return l.name + "[" + membersNames + "]"
+}
@@ -93,7 +93,8 @@ func (g *GroupConnLines) relevantKeysToCompare(groupingSrcOrDst map[string][]*gr
 			continue
 		}
 		// if vsi then the subnets of src and dst must be equal; similarly if subnet then vpcs must be equal
-		if getSubnetOrVPCUID(lines[0].Src) != getSubnetOrVPCUID(lines[0].Dst) {
+		// (or the labels of src and dst, when these are given)
+		if getGroupingScope(lines[0].Src) != getGroupingScope(lines[0].Dst) {
 			continue
 		}
 		relevantKeys = append(relevantKeys, key)
@@ -122,8 +123,7 @@ func (g *GroupConnLines) findMergeCandidates(groupingSrcOrDst map[string][]*grou
 	for _, key := range relevantKeys {
 		lines := groupingSrcOrDst[key]
 		bucket := lines[0].CommonProperties.groupingStrKey
-		subnetIfVsiVPCIfSubnet := getSubnetOrVPCUID(lines[0].Src)
-		bucket += semicolon + subnetIfVsiVPCIfSubnet
+		bucket += semicolon + getGroupingScope(lines[0].Src)
 		if _, ok := bucketToKeys[bucket]; !ok {
 			bucketToKeys[bucket] = make(map[string]struct{})
 		}
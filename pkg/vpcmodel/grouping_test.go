@@ -518,3 +518,96 @@ func TestSubnetSelfLoop(t *testing.T) {
 	fmt.Println(groupingStr)
 	fmt.Println("done")
 }
+
+// mockLabeledNetIntf is a mockNetIntf with a user-defined grouping label
+type mockLabeledNetIntf struct {
+	mockNetIntf
+	groupingLabel *groupingLabel
+}
+
+func (m *mockLabeledNetIntf) label() *groupingLabel {
+	return m.groupingLabel
+}
+
+func (m *mockLabeledNetIntf) setLabel(label *groupingLabel) {
+	m.groupingLabel = label
+}
+
+// vsi1, vsi2 and vsi3 of label web, and vsi4 and vsi5 of label db, are in different subnets, and are grouped by their labels:
+// all the web vsis are connected to the public internet, and are named by the label;
+// vsi1 and vsi2 are also connected to the db vsis, and are named by the label and their names
+func configLabelsGrouping() (*VPCConfig, *VPCConnectivity) {
+	res := &VPCConfig{Nodes: []Node{}}
+	res.Nodes = append(res.Nodes,
+		&mockLabeledNetIntf{mockNetIntf: mockNetIntf{cidr: "10.0.20.5/32", name: "vsi1"}},
+		&mockLabeledNetIntf{mockNetIntf: mockNetIntf{cidr: "10.0.30.5/32", name: "vsi2"}},
+		&mockLabeledNetIntf{mockNetIntf: mockNetIntf{cidr: "10.0.30.6/32", name: "vsi3"}},
+		&mockLabeledNetIntf{mockNetIntf: mockNetIntf{cidr: "10.0.20.7/32", name: "vsi4"}},
+		&mockLabeledNetIntf{mockNetIntf: mockNetIntf{cidr: "10.0.30.7/32", name: "vsi5"}},
+		&ExternalNetwork{CidrStr: "1.2.3.4/22", isPublicInternet: true, ResourceType: publicInternetNodeName})
+	res.Subnets = append(res.Subnets,
+		&mockSubnet{nil, "10.0.20.0/24", "subnet1", []Node{res.Nodes[0], res.Nodes[3]}},
+		&mockSubnet{nil, "10.0.30.0/24", "subnet2", []Node{res.Nodes[1], res.Nodes[2], res.Nodes[4]}})
+	web := &groupingLabel{name: "web", vpcUID: "vpc", members: 3}
+	db := &groupingLabel{name: "db", vpcUID: "vpc", members: 2}
+	for _, node := range res.Nodes[:3] {
+		node.(*mockLabeledNetIntf).groupingLabel = web
+	}
+	res.Nodes[0].(*mockLabeledNetIntf).subnet = res.Subnets[0]
+	res.Nodes[1].(*mockLabeledNetIntf).subnet = res.Subnets[1]
+	res.Nodes[2].(*mockLabeledNetIntf).subnet = res.Subnets[1]
+	res.Nodes[3].(*mockLabeledNetIntf).subnet = res.Subnets[0]
+	res.Nodes[4].(*mockLabeledNetIntf).subnet = res.Subnets[1]
+	res.Nodes[3].(*mockLabeledNetIntf).groupingLabel = db
+	res.Nodes[4].(*mockLabeledNetIntf).groupingLabel = db
+
+	res1 := &VPCConnectivity{AllowedConnsCombinedResponsive: GeneralResponsiveConnectivityMap{}}
+	conn := detailedConnForAllRsp()
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[0], res.Nodes[5], conn)
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[1], res.Nodes[5], conn)
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[0], res.Nodes[3], conn)
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[1], res.Nodes[4], conn)
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[0], res.Nodes[4], conn)
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[1], res.Nodes[3], conn)
+	res1.AllowedConnsCombinedResponsive.updateAllowedResponsiveConnsMap(res.Nodes[2], res.Nodes[5], conn)
+	return res, res1
+}
+
+func TestLabelsGrouping(t *testing.T) {
+	c, v := configLabelsGrouping()
+	res := &GroupConnLines{config: c, nodesConn: v, srcToDst: newGroupingConnections(), dstToSrc: newGroupingConnections(),
+		cacheGrouped: newCacheGroupedElements()}
+	err := res.groupExternalAddresses(true, false)
+	require.Equal(t, err, nil)
+	res.groupInternalSrcOrDst(true, true)
+	res.groupInternalSrcOrDst(false, true)
+	groupingStr := res.String(c)
+	fmt.Println(groupingStr)
+	require.Equal(t, "web => Public Internet 1.2.0.0/22 : All Connections\n"+
+		"web[vsi1,vsi2] => db : All Connections\n", groupingStr)
+}
+
+func TestLabelNames(t *testing.T) {
+	vpc := &mockVPCIntf{VPCResource{ResourceName: "vpc", ResourceUID: "vpc"}}
+	web1 := &mockVPCIntf{VPCResource{ResourceName: "web-vsi1", ResourceUID: "uid1", VPCRef: vpc, ResourceTags: []string{"tier:web"}}}
+	web2 := &mockVPCIntf{VPCResource{ResourceName: "web-vsi2", ResourceUID: "uid2", VPCRef: vpc,
+		ResourceTags: []string{"env:prod", "tier:web"}}}
+	db := &mockVPCIntf{VPCResource{ResourceName: "db-vsi", ResourceUID: "uid3", VPCRef: vpc, ResourceTags: []string{"tier"}}}
+
+	byTag := NewGroupingLabelsByTag("tier")
+	require.Equal(t, "web", byTag.labelName(web1))
+	require.Equal(t, "web", byTag.labelName(web2))
+	require.Equal(t, "", byTag.labelName(db))
+
+	byRegex, err := NewGroupingLabelsByRegex("^([a-z]+)-")
+	require.Nil(t, err)
+	require.Equal(t, "web", byRegex.labelName(web1))
+	require.Equal(t, "db", byRegex.labelName(db))
+	_, err = NewGroupingLabelsByRegex("([a-z]+")
+	require.NotNil(t, err)
+
+	byMapping := &GroupingLabels{mapping: map[string]string{"web-vsi1": "front", "uid3": "back"}}
+	require.Equal(t, "front", byMapping.labelName(web1))
+	require.Equal(t, "", byMapping.labelName(web2))
+	require.Equal(t, "back", byMapping.labelName(db))
+}
@@ -8,7 +8,6 @@ package vpcmodel
 
 import (
 	"encoding/json"
-	"errors"
 	"sort"
 
 	"github.com/np-guard/models/pkg/netset"
@@ -28,13 +27,43 @@ func (j *JSONoutputFormatter) WriteOutput(c1, c2 *VPCConfig,
 	var all interface{}
 	switch uc {
 	case AllEndpoints:
-		all = allInfo{EndpointsConnectivity: getConnLines(conn)}
+		if grouping {
+			all = allGroupedInfo{EndpointsConnectivity: getGroupedConnLines(c1, conn.GroupedConnectivity)}
+		} else {
+			all = allInfo{EndpointsConnectivity: getConnLines(conn)}
+		}
 	case AllSubnets:
-		all = allSubnetsConnectivity{Connectivity: getConnLinesForSubnetsConnectivity(subnetsConn)}
+		if grouping {
+			all = allGroupedSubnetsConnectivity{Connectivity: getGroupedConnLines(c1, subnetsConn.GroupedConnectivity)}
+		} else {
+			all = allSubnetsConnectivity{Connectivity: getConnLinesForSubnetsConnectivity(subnetsConn)}
+		}
 	case SubnetsDiff, EndpointsDiff:
 		all = allSemanticDiff{SemanticDiff: getDiffLines(cfgsDiff)}
+	case Exposure:
+		exposure, err := newExposureAnalysis(c1, conn)
+		if err != nil {
+			return nil, err
+		}
+		all = exposure.toJSON()
+	case Filters:
+		filters, err := newFiltersAnalysis(c1)
+		if err != nil {
+			return nil, err
+		}
+		all = filters.toJSON()
+	case RuleUsage:
+		ruleUsage, err := newRuleUsageAnalysis(c1, conn)
+		if err != nil {
+			return nil, err
+		}
+		all = ruleUsage.toJSON()
 	case SingleSubnet:
-		return nil, errors.New("DebugSubnet use case not supported for JSON format currently ")
+		singleSubnet, err := newSingleSubnetAnalysis(c1, grouping)
+		if err != nil {
+			return nil, err
+		}
+		all = singleSubnet.toJSON()
 	}
 	outStr, err := writeJSON(all, outFile)
 	v2Name := ""
@@ -118,7 +147,6 @@ func getConnLinesForSubnetsConnectivity(conn *VPCsubnetConnectivity) []connLine
 			if extConns.isEmpty() {
 				continue
 			}
-			// currently, not supported with grouping
 			connLines = append(connLines, connLine{
 				Src:                src,
 				Dst:                dst,
@@ -132,6 +160,92 @@ func getConnLinesForSubnetsConnectivity(conn *VPCsubnetConnectivity) []connLine
 	return connLines
 }
 
+// groupedConnLineJSON is the json form of a grouped connectivity line: the connection between two groups,
+// each with the list of its members
+type groupedConnLineJSON struct {
+	Src                groupJSON      `json:"src"`
+	Dst                groupJSON      `json:"dst"`
+	Conn               netset.Details `json:"conn"`
+	UnidirectionalConn netset.Details `json:"unidirectional_conn,omitempty"`
+	connStr            string         // the connection as a string, to order lines of the same src and dst
+}
+
+type groupJSON struct {
+	Name    string            `json:"name"`
+	Members []groupMemberJSON `json:"members"`
+}
+
+// groupMemberJSON is a member of a group: an endpoint or a subnet, or a range of external addresses
+type groupMemberJSON struct {
+	Name    string `json:"name"`
+	UID     string `json:"uid,omitempty"`
+	Type    string `json:"type"`
+	VPC     string `json:"vpc,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+type allGroupedInfo struct {
+	EndpointsConnectivity []groupedConnLineJSON `json:"grouped_endpoints_connectivity"`
+}
+
+type allGroupedSubnetsConnectivity struct {
+	Connectivity []groupedConnLineJSON `json:"grouped_subnets_connectivity"`
+}
+
+func getGroupedConnLines(c *VPCConfig, groupedConnectivity *GroupConnLines) []groupedConnLineJSON {
+	connLines := []groupedConnLineJSON{}
+	for _, line := range groupedConnectivity.GroupedLines {
+		conn := line.CommonProperties.Conn
+		if conn.isEmpty() {
+			continue
+		}
+		connLine := groupedConnLineJSON{Src: getGroupJSON(c, line.Src), Dst: getGroupJSON(c, line.Dst),
+			Conn: netset.ToJSON(conn.nonTCPAndResponsiveTCPComponent()), connStr: conn.string()}
+		if !conn.TCPRspDisable.IsEmpty() {
+			connLine.UnidirectionalConn = netset.ToJSON(conn.TCPRspDisable)
+		}
+		connLines = append(connLines, connLine)
+	}
+	sort.Slice(connLines, func(i, j int) bool {
+		if connLines[i].Src.Name != connLines[j].Src.Name {
+			return connLines[i].Src.Name < connLines[j].Src.Name
+		}
+		if connLines[i].Dst.Name != connLines[j].Dst.Name {
+			return connLines[i].Dst.Name < connLines[j].Dst.Name
+		}
+		return connLines[i].connStr < connLines[j].connStr
+	})
+	return connLines
+}
+
+// getGroupJSON returns the json form of a group of endpoints, subnets or external addresses;
+// the external addresses of a group are listed as their merged cidrs and ranges
+func getGroupJSON(c *VPCConfig, ep EndpointElem) groupJSON {
+	res := groupJSON{Name: ep.NameForAnalyzerOut(c), Members: []groupMemberJSON{}}
+	if externals, ok := ep.(*groupedExternalNodes); ok {
+		for _, addresses := range externals.toIPBlock().ListToPrint() {
+			res.Members = append(res.Members, groupMemberJSON{Name: addresses, Type: externals.resourceType(), Address: addresses})
+		}
+		return res
+	}
+	for _, member := range endpointElemResources(ep) {
+		res.Members = append(res.Members, getMemberJSON(member))
+	}
+	sort.Slice(res.Members, func(i, j int) bool { return res.Members[i].Name < res.Members[j].Name })
+	return res
+}
+
+func getMemberJSON(member VPCResourceIntf) groupMemberJSON {
+	res := groupMemberJSON{Name: member.NameForAnalyzerOut(nil), UID: member.UID(), Type: member.Kind()}
+	if member.VPC() != nil {
+		res.VPC = member.VPC().Name()
+	}
+	if internal, ok := member.(InternalNodeIntf); ok {
+		res.Address = internal.Address()
+	}
+	return res
+}
+
 type allSemanticDiff struct {
 	SemanticDiff []diffLine `json:"semantic_diff"`
 }
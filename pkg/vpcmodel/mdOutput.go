@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package vpcmodel
 
 import (
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -70,8 +69,35 @@ func (m *MDoutputFormatter) WriteOutput(c1, c2 *VPCConfig,
 		lines = []string{mdTitle, mdHeader}
 		connLines = m.getGroupedDiffOutput(cfgsDiff)
 		hasStatelessConns = cfgsDiff.hasStatelessConns()
+	case Exposure:
+		exposure, err := newExposureAnalysis(c1, conn)
+		if err != nil {
+			return nil, err
+		}
+		lines = []string{mdExposureHeader}
+		connLines = exposure.mdLines()
+		hasStatelessConns = exposure.hasStatelessConns()
+	case Filters:
+		filters, err := newFiltersAnalysis(c1)
+		if err != nil {
+			return nil, err
+		}
+		lines = []string{mdFiltersHeader}
+		connLines = filters.mdLines()
+	case RuleUsage:
+		ruleUsage, err := newRuleUsageAnalysis(c1, conn)
+		if err != nil {
+			return nil, err
+		}
+		lines = []string{mdRuleUsageHeader}
+		connLines = ruleUsage.mdLines()
 	case SingleSubnet:
-		return nil, errors.New("DebugSubnet use case not supported for md format currently ")
+		singleSubnet, err := newSingleSubnetAnalysis(c1, grouping)
+		if err != nil {
+			return nil, err
+		}
+		lines = []string{mdSingleSubnetHeader}
+		connLines = singleSubnet.mdLines()
 	}
 	out += linesToOutput(connLines, lines)
 
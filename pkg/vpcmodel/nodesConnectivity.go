@@ -263,9 +263,9 @@ func (v *VPCConnectivity) computeAllowedResponsiveConnections(c *VPCConfig,
 			// check allowed conns per NACL-layer from dst to src (dst->src) (since SG is stateful)
 			var DstAllowedEgressToSrc, SrcAllowedIngressFromDst *netset.TransportSet
 			// can dst egress to src?
-			DstAllowedEgressToSrc = v.getPerLayerConnectivity(statelessLayerName, dstNode, srcNode, false)
+			DstAllowedEgressToSrc = v.GetPerLayerConnectivity(statelessLayerName, dstNode, srcNode, false)
 			// can src ingress from dst?
-			SrcAllowedIngressFromDst = v.getPerLayerConnectivity(statelessLayerName, dstNode, srcNode, true)
+			SrcAllowedIngressFromDst = v.GetPerLayerConnectivity(statelessLayerName, dstNode, srcNode, true)
 			combinedDstToSrc := DstAllowedEgressToSrc.Intersect(SrcAllowedIngressFromDst)
 			// in case the connection is multi-vpc: does the tgw enable respond?
 			if c.IsMultipleVPCsConfig {
@@ -282,8 +282,9 @@ func (v *VPCConnectivity) computeAllowedResponsiveConnections(c *VPCConfig,
 	return nil
 }
 
-// getPerLayerConnectivity currently used for "NaclLayer" - to compute stateful allowed conns
-func (v *VPCConnectivity) getPerLayerConnectivity(layer string, src, dst Node, isIngress bool) *netset.TransportSet {
+// GetPerLayerConnectivity returns the connectivity from src to dst in the given direction, allowed by the given layer alone;
+// used for "NaclLayer" to compute stateful allowed conns, and by linters comparing the filter layers
+func (v *VPCConnectivity) GetPerLayerConnectivity(layer string, src, dst Node, isIngress bool) *netset.TransportSet {
 	// if the analyzed input node is not internal- assume all conns allowed
 	if (isIngress && !dst.IsInternal()) || (!isIngress && !src.IsInternal()) {
 		return netset.AllTransports()
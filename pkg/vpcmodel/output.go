@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/np-guard/models/pkg/spec"
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/drawio"
 )
 
 type OutFormat int64
@@ -56,6 +57,11 @@ const (
 	SubnetsDiff                          // diff between subnets connectivity of two cfgs (consider nacl + pgw)
 	EndpointsDiff                        // diff between vsis connectivity of two cfgs
 	Explain                              // explain specified connectivity, given src,dst and connection
+	Exposure                             // connectivity between internal endpoints and external networks
+	BlastRadius                          // endpoints transitively reachable from a given src endpoint
+	Segmentation                         // zone x zone connectivity matrix of a user-defined zoning model
+	Filters                              // connectivity implied by each nacl and sg alone on its attached resources
+	RuleUsage                            // connections between endpoints each nacl and sg rule contributes to
 )
 
 // OutputGenerator captures one vpc config1 with its connectivity analysis results, and implements
@@ -70,6 +76,10 @@ type OutputGenerator struct {
 	cfgsDiff       *diffBetweenCfgs
 	explanation    *Explanation
 	detailExplain  bool
+	blastRadius    *BlastRadiusAnalysis
+	zoning         *ZoningModel
+	viewLevel      ViewLevel
+	layoutSeed     drawio.LayoutSeed
 }
 
 func NewOutputGenerator(cConfigs *MultipleVPCConfigs, groupingType int, uc OutputUseCase,
@@ -95,6 +105,20 @@ func NewOutputGenerator(cConfigs *MultipleVPCConfigs, groupingType int, uc Outpu
 				}
 				res.nodesConn[i] = nodesConn
 			}
+		case Exposure, RuleUsage:
+			// exposure and rule usage are computed per endpoint, thus load balancers are not abstracted;
+			// multi-vpc configs are skipped since they do not capture connectivity to external networks,
+			// and their rules are those of the single vpc configs
+			for i, vpcConfig := range cConfigs.Configs() {
+				if vpcConfig.IsMultipleVPCsConfig {
+					continue
+				}
+				nodesConn, err := vpcConfig.GetVPCNetworkConnectivity(false, NoGroupingNoConsistencyEdges)
+				if err != nil {
+					return nil, err
+				}
+				res.nodesConn[i] = nodesConn
+			}
 		case AllSubnets:
 			for i, vpcConfig := range cConfigs.Configs() {
 				subnetsConn, err := vpcConfig.GetSubnetsConnectivity(true, groupingType)
@@ -122,8 +146,19 @@ func NewOutputGenerator(cConfigs *MultipleVPCConfigs, groupingType int, uc Outpu
 			if err != nil {
 				return nil, err
 			}
+			if explanationArgs.Remediate {
+				if err := explanation.computeRemediation(); err != nil {
+					return nil, err
+				}
+			}
 			res.explanation = explanation
 			res.detailExplain = explanationArgs.Detail
+		case BlastRadius:
+			blastRadius, err := cConfigs.ComputeBlastRadius(explanationArgs.src, explanationArgs.GetConnectionSet())
+			if err != nil {
+				return nil, err
+			}
+			res.blastRadius = blastRadius
 		}
 	}
 	// only Graphic formats has a multi vpc common presentation
@@ -133,6 +168,22 @@ func NewOutputGenerator(cConfigs *MultipleVPCConfigs, groupingType int, uc Outpu
 	return res, nil
 }
 
+// SetViewLevel sets the lowest level of the network shown on drawio/svg/html maps, the levels below it are collapsed
+func (o *OutputGenerator) SetViewLevel(viewLevel ViewLevel) {
+	o.viewLevel = viewLevel
+}
+
+// SetLayoutSeed sets the locations of the elements of a previously generated drawio file,
+// so drawio/svg/html maps keep the layout of that file for the elements they share with it
+func (o *OutputGenerator) SetLayoutSeed(layoutSeed drawio.LayoutSeed) {
+	o.layoutSeed = layoutSeed
+}
+
+// SetZoningModel sets the zoning model whose segmentation matrix is reported by the segmentation use case
+func (o *OutputGenerator) SetZoningModel(zoning *ZoningModel) {
+	o.zoning = zoning
+}
+
 // SingleAnalysisOutput captures output per connectivity analysis of a single VPC,  or per semantic diff between 2 VPCs
 // in the former case VPC2Name will be empty
 type SingleAnalysisOutput struct {
@@ -145,18 +196,30 @@ type SingleAnalysisOutput struct {
 	hasStatelessConn bool
 	// hasStatelessConn indicates if the connectivity results contain an overApproximated conn
 	hasOverApproximatedConn bool
+	// labeledSegments holds the names of the synthesis segments named by a user-defined label
+	labeledSegments map[string]bool
 }
 
 // Generate returns a string representing the analysis output for all input VPCs
 func (o *OutputGenerator) Generate(f OutFormat, outFile string) (string, error) {
 	var formatter OutputFormatter
+	// the segmentation matrix has its own html output, rather than a drawio based one
+	if o.useCase == Segmentation {
+		formatter = &segmentationOutputFormatter{f, o.zoning}
+		return formatter.WriteOutput(o.configs, o.nodesConn, o.subnetsConn, o.cfgsDiff,
+			outFile, o.outputGrouping, o.useCase, o.explanation, o.detailExplain)
+	}
 	switch f {
 	case JSON, Text, MD, Synthesis:
-		formatter = &serialOutputFormatter{f}
+		if o.useCase == BlastRadius {
+			formatter = &blastRadiusOutputFormatter{f, o.blastRadius}
+		} else {
+			formatter = &serialOutputFormatter{f}
+		}
 	case DRAWIO, SVG, HTML:
-		formatter = newDrawioOutputFormatter(f, o.lbAbstraction)
+		formatter = newDrawioOutputFormatter(f, o.lbAbstraction, o.viewLevel, o.layoutSeed)
 	case ARCHDRAWIO, ARCHSVG, ARCHHTML:
-		formatter = newArchDrawioOutputFormatter(f, o.lbAbstraction)
+		formatter = newArchDrawioOutputFormatter(f, o.lbAbstraction, o.viewLevel, o.layoutSeed)
 	default:
 		return "", errors.New("unsupported output format")
 	}
@@ -212,6 +275,9 @@ func (of *serialOutputFormatter) WriteOutput(cConfigs *MultipleVPCConfigs, conns
 		outputPerVPC := make([]*SingleAnalysisOutput, len(cConfigs.Configs()))
 		i := 0
 		for uid, vpcConfig := range cConfigs.Configs() {
+			if (uc == Exposure || uc == Filters || uc == RuleUsage) && vpcConfig.IsMultipleVPCsConfig {
+				continue
+			}
 			vpcAnalysisOutput, err :=
 				of.createSingleVpcFormatter().WriteOutput(vpcConfig, nil, conns[uid], subnetsConns[uid],
 					configsDiff, "", grouping, uc, explainStruct, detailExplain)
@@ -221,7 +287,7 @@ func (of *serialOutputFormatter) WriteOutput(cConfigs *MultipleVPCConfigs, conns
 			outputPerVPC[i] = vpcAnalysisOutput
 			i++
 		}
-		return of.AggregateVPCsOutput(outputPerVPC, uc, outFile)
+		return of.AggregateVPCsOutput(outputPerVPC[:i], uc, outFile)
 	}
 	// singleVPCAnalysis: either diff or explain. In either case conn and subnet conn are non-relevant, thus passing nil
 	// diff compares between two single vpc configs
@@ -310,12 +376,17 @@ func (of *serialOutputFormatter) AggregateVPCsOutput(outputList []*SingleAnalysi
 		for _, o := range outputList {
 			// always true
 			if structObj, ok := o.jsonStruct.(*spec.Spec); ok {
-				connLines = append(connLines, renameExternalsAndSegments(structObj.RequiredConnections, externalsMap, segmentsMap)...)
+				connLines = append(connLines, renameExternalsAndSegments(structObj.RequiredConnections, externalsMap, segmentsMap,
+					o.labeledSegments)...)
 				for k, v := range structObj.Externals {
 					externals[externalsMap[k]] = v
 				}
 				for k, v := range structObj.Segments {
-					segments[segmentsMap[k]] = v
+					if o.labeledSegments[k] {
+						segments[k] = v
+					} else {
+						segments[segmentsMap[k]] = v
+					}
 				}
 			}
 		}
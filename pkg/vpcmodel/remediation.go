@@ -0,0 +1,410 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+	"github.com/np-guard/models/pkg/spec"
+)
+
+// Functions for proposing remediation to a blocked connection queried by explain: the minimal set of
+// SG and NACL rule additions that enables exactly the queried connection, including the response of TCP
+// connections in the stateless NACLs
+
+const (
+	remediationHeader      = "Remediation - rules to add for enabling the queried connection:\n"
+	remediationPatchHeader = "Remediation patch (can be applied with \"diff --patch\"):\n"
+	noRemediationRequired  = "Remediation: the queried connection is already enabled, no rule additions are required\n"
+	remediationNotesHeader = "Remediation notes:\n"
+	allowStr               = "allow"
+	protocolAllStr         = "all"
+)
+
+// remediationRule is a single rule addition to a filter (SG or NACL) proposed for enabling a blocked connection
+type remediationRule struct {
+	layer      string // NaclLayer or SecurityGroupLayer
+	table      string // name of the sg/nacl the rule is added to
+	isIngress  bool
+	isResponse bool // whether the rule enables the response of a TCP connection in a stateless filter
+	src        *netset.IPBlock
+	dst        *netset.IPBlock
+	conn       *netset.TransportSet
+}
+
+// remediation is the set of rule additions proposed for enabling the queried connection of an explanation,
+// and notes about blocking elements which can not be fixed by rule additions (e.g. a missing router)
+type remediation struct {
+	rules []*remediationRule
+	notes []string
+}
+
+// computeRemediation computes the remediation of the explanation's queried connection
+func (explanation *Explanation) computeRemediation() error {
+	res := &remediation{}
+	explanation.remediation = res
+	if explanation.c == nil {
+		res.notes = append(res.notes, fmt.Sprintf("%s and %s are in different VPCs with no cross-VPC router between them",
+			explanation.src, explanation.dst))
+		return nil
+	}
+	query := explanation.connQuery
+	if query == nil {
+		query = netset.AllTransports()
+	}
+	rulesByKey := map[string]*remediationRule{}
+	for _, details := range *explanation.rulesAndDetails {
+		srcDstRules, notes, err := details.remediationRules(explanation.c, query)
+		if err != nil {
+			return err
+		}
+		res.notes = append(res.notes, notes...)
+		for _, rule := range srcDstRules {
+			res.addRule(rulesByKey, rule)
+		}
+	}
+	res.notes = uniqueSortedStrings(res.notes)
+	return nil
+}
+
+// addRule adds rule to the remediation; rules that differ only by their remote (external) addresses are merged
+func (r *remediation) addRule(rulesByKey map[string]*remediationRule, rule *remediationRule) {
+	// the local side of an ingress rule is its dst, and of an egress rule its src
+	local := rule.src
+	if rule.isIngress {
+		local = rule.dst
+	}
+	key := strings.Join([]string{rule.layer, rule.table, fmt.Sprint(rule.isIngress), fmt.Sprint(rule.isResponse),
+		rule.conn.String(), local.String()}, semicolon)
+	existing, ok := rulesByKey[key]
+	if !ok {
+		rulesByKey[key] = rule
+		r.rules = append(r.rules, rule)
+		return
+	}
+	if rule.isIngress {
+		existing.src = existing.src.Union(rule.src)
+	} else {
+		existing.dst = existing.dst.Union(rule.dst)
+	}
+}
+
+// remediationRules returns the rules additions required for enabling query from src to dst, and notes regarding
+// elements blocking the connection which are not filters
+func (details *srcDstDetails) remediationRules(c *VPCConfig, query *netset.TransportSet) (
+	rules []*remediationRule, notes []string, err error) {
+	src, dst := details.src, details.dst
+	srcName, dstName := src.NameForAnalyzerOut(c), dst.NameForAnalyzerOut(c)
+	if (!src.IsInternal() || !dst.IsInternal()) && details.externalRouter == nil {
+		notes = append(notes, fmt.Sprintf("there is no router (floating IP or public gateway) between %s and %s", srcName, dstName))
+		return nil, notes, nil
+	}
+	if details.crossVpcRouter != nil {
+		routerConn, err := details.crossVpcRouter.AllowedConnectivity(src, dst)
+		if err != nil {
+			return nil, nil, err
+		}
+		if routerConn.IsEmpty() {
+			notes = append(notes, fmt.Sprintf("the prefix filters of %s deny the connection from %s to %s",
+				details.crossVpcRouter.NameForAnalyzerOut(c), srcName, dstName))
+		}
+	}
+	if details.loadBalancerRule != nil && (details.loadBalancerRule.Deny(true) || details.loadBalancerRule.Deny(false)) {
+		notes = append(notes, fmt.Sprintf("load balancer: %s", details.loadBalancerRule.String(false)))
+	}
+	if details.privateSubnetRule != nil && (details.privateSubnetRule.Deny(true) || details.privateSubnetRule.Deny(false)) {
+		notes = append(notes, fmt.Sprintf("private subnet: %s", details.privateSubnetRule.String(false)))
+	}
+	for _, layer := range FilterLayers {
+		if !details.filtersRelevant[layer] {
+			continue
+		}
+		for _, isIngress := range []bool{false, true} {
+			rulesOfDirection := details.actualAllowRules.egressRules
+			node := src
+			if isIngress {
+				rulesOfDirection, node = details.actualAllowRules.ingressRules, dst
+			}
+			if !node.IsInternal() {
+				continue
+			}
+			missing := query.Subtract(connOfLayer(rulesOfDirection[layer]))
+			rule, err := newRemediationRule(c, layer, node, isIngress, false, src, dst, missing)
+			if err != nil {
+				return nil, nil, err
+			}
+			rules = appendRuleOrNote(rules, &notes, rule, layer, node.NameForAnalyzerOut(c))
+		}
+	}
+	if !details.filtersRelevant[statelessLayerName] {
+		return rules, notes, nil
+	}
+	responseRules, err := responseRemediationRules(c, src, dst, query.Intersect(allTCPconn()).SwapPorts())
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(rules, responseRules...), notes, nil
+}
+
+// responseRemediationRules returns the stateless filters rules additions required for enabling the response
+// from dst to src; the response is ingress to src and egress from dst
+func responseRemediationRules(c *VPCConfig, src, dst Node, response *netset.TransportSet) (
+	rules []*remediationRule, err error) {
+	if response.IsEmpty() {
+		return nil, nil
+	}
+	for _, isIngress := range []bool{false, true} {
+		node := dst
+		if isIngress {
+			node = src
+		}
+		if !node.IsInternal() {
+			continue
+		}
+		allowRules, _, err := getFiltersRulesBetweenNodesPerDirectionAndLayer(c, dst, src, response, isIngress,
+			statelessLayerName)
+		if err != nil {
+			return nil, err
+		}
+		missing := response.Subtract(connOfLayer(*allowRules))
+		rule, err := newRemediationRule(c, statelessLayerName, node, isIngress, true, dst, src, missing)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil && rule.table != emptyString {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func appendRuleOrNote(rules []*remediationRule, notes *[]string, rule *remediationRule, layer,
+	nodeName string) []*remediationRule {
+	switch {
+	case rule == nil:
+		return rules
+	case rule.table == emptyString:
+		*notes = append(*notes, fmt.Sprintf("%s is not attached to any %s", nodeName, FilterKindName(layer)))
+		return rules
+	default:
+		return append(rules, rule)
+	}
+}
+
+// newRemediationRule returns the rule enabling conn from src to dst, to be added to the filter of the given layer
+// applied to node; nil if conn is empty. The returned rule's table is empty if no such filter is applied to node
+func newRemediationRule(c *VPCConfig, layer string, node Node, isIngress, isResponse bool, src, dst Node,
+	conn *netset.TransportSet) (*remediationRule, error) {
+	if conn.IsEmpty() {
+		return nil, nil
+	}
+	table, err := filterOfNode(c, layer, node)
+	if err != nil {
+		return nil, err
+	}
+	return &remediationRule{layer: layer, table: table, isIngress: isIngress, isResponse: isResponse,
+		src: src.IPBlock(), dst: dst.IPBlock(), conn: conn}, nil
+}
+
+// filterOfNode returns the name of the filter of the given layer that is applied to node; for SGs, of which more
+// than one may be applied, the first by name is returned. Returns an empty string if there is no such filter
+func filterOfNode(c *VPCConfig, layer string, node Node) (string, error) {
+	filterLayer := c.GetFilterTrafficResourceOfKind(layer)
+	if filterLayer == nil {
+		return emptyString, fmt.Errorf("layer %v not found in configuration", layer)
+	}
+	var member VPCResourceIntf = node
+	if layer == NaclLayer {
+		member = node.(InternalNodeIntf).Subnet()
+	}
+	names := []string{}
+	for filter, resources := range filterLayer.GetFiltersAttachedResources() {
+		for _, resource := range resources {
+			if resource.UID() == member.UID() {
+				names = append(names, filter.FilterName)
+				break
+			}
+		}
+	}
+	if len(names) == 0 {
+		return emptyString, nil
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
+
+// toPatchRules returns the rules in the format of ConfigPatch; a rule is translated to a rule per
+// protocol/ports cube of its connection and per cidr of its addresses
+func (rule *remediationRule) toPatchRules() []*PatchRule {
+	res := []*PatchRule{}
+	for _, srcCidr := range rule.src.ToCidrList() {
+		for _, dstCidr := range rule.dst.ToCidrList() {
+			for _, item := range netset.ToJSON(rule.conn) {
+				patchRule := connItemToPatchRule(item)
+				patchRule.Direction = directionStr(rule.isIngress)
+				if rule.layer == NaclLayer {
+					patchRule.Action, patchRule.Source, patchRule.Destination = allowStr, srcCidr, dstCidr
+				} else if rule.isIngress {
+					patchRule.Remote = srcCidr
+				} else {
+					patchRule.Remote = dstCidr
+				}
+				res = append(res, patchRule)
+			}
+		}
+	}
+	return res
+}
+
+func directionStr(isIngress bool) string {
+	if isIngress {
+		return inboundStr
+	}
+	return outboundStr
+}
+
+func optionalPort(port int) *int64 {
+	if port == 0 {
+		return nil
+	}
+	res := int64(port)
+	return &res
+}
+
+func optionalInt(val *int) *int64 {
+	if val == nil {
+		return nil
+	}
+	res := int64(*val)
+	return &res
+}
+
+// connItemToPatchRule translates a single item of a connection's json representation to a PatchRule
+func connItemToPatchRule(item interface{}) *PatchRule {
+	switch conn := item.(type) {
+	case spec.TcpUdp:
+		return &PatchRule{Protocol: strings.ToLower(string(conn.Protocol)),
+			SrcPortMin: optionalPort(conn.MinSourcePort), SrcPortMax: optionalPort(conn.MaxSourcePort),
+			DstPortMin: optionalPort(conn.MinDestinationPort), DstPortMax: optionalPort(conn.MaxDestinationPort)}
+	case spec.Icmp:
+		return &PatchRule{Protocol: strings.ToLower(string(conn.Protocol)), ICMPType: optionalInt(conn.Type),
+			ICMPCode: optionalInt(conn.Code)}
+	default:
+		return &PatchRule{Protocol: protocolAllStr}
+	}
+}
+
+// String returns a one-line description of the rule
+func (r *PatchRule) String() string {
+	res := []string{}
+	if r.Action != emptyString {
+		res = append(res, r.Action)
+	}
+	res = append(res, "protocol: "+r.Protocol)
+	for _, field := range []struct {
+		name string
+		min  *int64
+		max  *int64
+	}{{"src-ports", r.SrcPortMin, r.SrcPortMax}, {"dst-ports", r.DstPortMin, r.DstPortMax},
+		{"icmp-type", r.ICMPType, r.ICMPType}, {"icmp-code", r.ICMPCode, r.ICMPCode}} {
+		switch {
+		case field.min == nil:
+		case *field.min == *field.max:
+			res = append(res, fmt.Sprintf("%s: %d", field.name, *field.min))
+		default:
+			res = append(res, fmt.Sprintf("%s: %d-%d", field.name, *field.min, *field.max))
+		}
+	}
+	for _, field := range []struct{ name, val string }{{"remote", r.Remote}, {"source", r.Source},
+		{"destination", r.Destination}} {
+		if field.val != emptyString {
+			res = append(res, field.name+": "+field.val)
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+// patch returns the remediation as a ConfigPatch; NACL rules are added as the first rules of their NACL,
+// so that they take precedence over existing deny rules
+func (r *remediation) patch() *ConfigPatch {
+	res := &ConfigPatch{Changes: []*ConfigChange{}}
+	for _, rule := range r.sortedRules() {
+		for _, patchRule := range rule.toPatchRules() {
+			change := &ConfigChange{Op: AddSGRule, SG: rule.table, NewRule: patchRule}
+			if rule.layer == NaclLayer {
+				change = &ConfigChange{Op: AddNACLRule, NACL: rule.table, NewRule: patchRule}
+			}
+			res.Changes = append(res.Changes, change)
+		}
+	}
+	return res
+}
+
+func (r *remediation) sortedRules() []*remediationRule {
+	res := make([]*remediationRule, len(r.rules))
+	copy(res, r.rules)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].sortKey() < res[j].sortKey()
+	})
+	return res
+}
+
+func (rule *remediationRule) sortKey() string {
+	return strings.Join([]string{rule.layer, rule.table, fmt.Sprint(rule.isResponse), directionStr(rule.isIngress),
+		rule.src.String(), rule.dst.String()}, semicolon)
+}
+
+func (rule *remediationRule) descriptions() []string {
+	res := []string{}
+	suffix := emptyString
+	if rule.isResponse {
+		suffix = " (enables the TCP response)"
+	}
+	for _, patchRule := range rule.toPatchRules() {
+		res = append(res, fmt.Sprintf("\t%s %s: add %s rule: %s%s\n", FilterKindName(rule.layer), rule.table,
+			patchRule.Direction, patchRule.String(), suffix))
+	}
+	return res
+}
+
+func (r *remediation) String() string {
+	res := emptyString
+	if len(r.notes) > 0 {
+		res += remediationNotesHeader + "\tthe connection is blocked by the following, which can not be fixed by rule additions:\n"
+		for _, note := range r.notes {
+			res += "\t" + note + newLine
+		}
+	}
+	if len(r.rules) == 0 {
+		if len(r.notes) == 0 {
+			return noRemediationRequired
+		}
+		return res
+	}
+	res += remediationHeader
+	for _, rule := range r.sortedRules() {
+		res += strings.Join(rule.descriptions(), emptyString)
+	}
+	// marshaling a ConfigPatch does not fail: it contains only strings and numbers
+	patchJSON, _ := json.MarshalIndent(r.patch(), emptyString, "    ")
+	return res + remediationPatchHeader + string(patchJSON) + newLine
+}
+
+func uniqueSortedStrings(strs []string) []string {
+	sort.Strings(strs)
+	res := []string{}
+	for i, str := range strs {
+		if i == 0 || str != strs[i-1] {
+			res = append(res, str)
+		}
+	}
+	return res
+}
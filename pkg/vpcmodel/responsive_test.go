@@ -130,3 +130,65 @@ func TestAll(t *testing.T) {
 		})
 	}
 }
+
+type nonTCPResponsiveTest struct {
+	name                            string
+	srcToDst                        *netset.TransportSet
+	dstToSrc                        *netset.TransportSet
+	expectedNonTCPNonResponsiveConn *netset.TransportSet
+}
+
+func (tt nonTCPResponsiveTest) runTest(t *testing.T) {
+	t.Helper()
+	detailedConn := computeDetailedConn(tt.srcToDst, tt.dstToSrc)
+	require.True(t, tt.expectedNonTCPNonResponsiveConn.Equal(detailedConn.NonTCPRspDisable()))
+}
+
+func TestNonTCPResponsive(t *testing.T) {
+	var testCasesNonTCPResponsive = []nonTCPResponsiveTest{
+		{
+			name:                            "udp_all_ports_on_both_directions",
+			srcToDst:                        newTCPUDPSet(netp.ProtocolStringUDP),
+			dstToSrc:                        newTCPUDPSet(netp.ProtocolStringUDP),
+			expectedNonTCPNonResponsiveConn: netset.NoTransports(),
+		},
+		{
+			name:                            "udp_dns_query_response_blocked",
+			srcToDst:                        newUDPConn(netp.MinPort, netp.MaxPort, 53, 53),
+			dstToSrc:                        newTCPUDPSet(netp.ProtocolStringTCP),
+			expectedNonTCPNonResponsiveConn: newUDPConn(netp.MinPort, netp.MaxPort, 53, 53),
+		},
+		{
+			name:                            "udp_with_ports_both_directions_partial_match",
+			srcToDst:                        newUDPConn(80, 100, 53, 53),
+			dstToSrc:                        newUDPConn(53, 53, 80, 80),
+			expectedNonTCPNonResponsiveConn: newUDPConn(81, 100, 53, 53),
+		},
+		{
+			name:                            "icmp_echo_with_echo_reply",
+			srcToDst:                        newICMPconn(),
+			dstToSrc:                        icmpEchoReplyConn(),
+			expectedNonTCPNonResponsiveConn: netset.NoTransports(),
+		},
+		{
+			name:                            "icmp_echo_reply_blocked",
+			srcToDst:                        netset.AllTransports(),
+			dstToSrc:                        newTCPUDPSet(netp.ProtocolStringUDP).Union(icmpEchoConn()),
+			expectedNonTCPNonResponsiveConn: icmpEchoConn(),
+		},
+		{
+			name:                            "no_echo_in_first_direction",
+			srcToDst:                        newICMPconn().Subtract(icmpEchoConn()),
+			dstToSrc:                        netset.NoTransports(),
+			expectedNonTCPNonResponsiveConn: netset.NoTransports(),
+		},
+	}
+	t.Parallel()
+	for testIdx := range testCasesNonTCPResponsive {
+		tt := testCasesNonTCPResponsive[testIdx]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.runTest(t)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/drawio"
+)
+
+const droppedPathLabel = "dropped"
+
+// RoutingPathInfo is the routing path computed by the routing analysis for a pair of src and dst endpoints
+type RoutingPathInfo struct {
+	Src  Node
+	Dst  Node
+	Path Path
+}
+
+// routingSegment is a single segment of a routing path drawn on the map, between two consecutive hops of the path
+type routingSegment struct {
+	src     drawio.TreeNodeInterface
+	dst     drawio.TreeNodeInterface
+	dropped bool
+}
+
+// RoutingPathsDrawio draws the given routing paths on the drawio/svg/html map of the configs:
+// each path is drawn as a multi-segment line from its src, through the routers and next-hop appliances, to its dst.
+// a path that does not reach its dst ends with a red dashed segment, from its last hop to its dst, marking the dropped traffic
+// the map is drawn at the endpoint view level, keeping the layout of the given layout seed, if any
+func RoutingPathsDrawio(cConfigs *MultipleVPCConfigs, paths []*RoutingPathInfo, outFormat OutFormat, outFile string,
+	lbAbstraction bool, layoutSeed drawio.LayoutSeed) (string, error) {
+	d := newDrawioOutputFormatter(outFormat, lbAbstraction, EndpointViewLevel, layoutSeed)
+	d.init(cConfigs, nil, nil, AllEndpoints)
+	d.createDrawioTree()
+	d.createRoutingPathsLines(paths)
+	res, err := drawio.CreateDrawioConnectivityMap(d.gen.Network(), false, d.drawioFormat(), nil, cConfigs.Provider())
+	if err != nil {
+		return "", err
+	}
+	return WriteToFile(res, outFile)
+}
+
+// createRoutingPathsLines() creates the lines of the segments of all the paths, a segment shared by several paths is drawn once
+func (d *DrawioOutputFormatter) createRoutingPathsLines(paths []*RoutingPathInfo) {
+	segments := map[routingSegment]bool{}
+	segmentsOrder := []routingSegment{}
+	for _, p := range paths {
+		for _, segment := range d.routingPathSegments(p) {
+			if !segments[segment] {
+				segments[segment] = true
+				segmentsOrder = append(segmentsOrder, segment)
+			}
+		}
+	}
+	for _, segment := range segmentsOrder {
+		label := ""
+		if segment.dropped {
+			label = droppedPathLabel
+		}
+		tn := drawio.NewConnectivityLineTreeNode(d.gen.Network(), segment.src, segment.dst, true, label)
+		if segment.dropped {
+			tn.SetDropped()
+		}
+	}
+}
+
+// routingPathSegments() returns the segments drawing a routing path
+func (d *DrawioOutputFormatter) routingPathSegments(p *RoutingPathInfo) []routingSegment {
+	srcTn, dstTn := d.gen.TreeNode(p.Src), d.gen.TreeNode(p.Dst)
+	if srcTn == nil || dstTn == nil {
+		return nil
+	}
+	hops := []drawio.TreeNodeInterface{srcTn}
+	for _, endpoint := range p.Path {
+		hopTn := d.routingHopTreeNode(endpoint, p.Dst)
+		// a hop without a tree node (e.g. a service gateway) is not drawn,
+		// and a fip is drawn on the icon of its source, which is already the previous hop
+		if hopTn != nil && hopTn != hops[len(hops)-1] {
+			hops = append(hops, hopTn)
+		}
+	}
+	reached := hops[len(hops)-1] == dstTn
+	if !reached && len(p.Path) > 0 && p.Path[len(p.Path)-1].NextHop != nil {
+		// the next-hop appliance forwards the traffic to the original dst
+		hops = append(hops, dstTn)
+		reached = true
+	}
+	segments := []routingSegment{}
+	for i := 1; i < len(hops); i++ {
+		segments = append(segments, routingSegment{src: hops[i-1], dst: hops[i]})
+	}
+	if !reached {
+		segments = append(segments, routingSegment{src: hops[len(hops)-1], dst: dstTn, dropped: true})
+	}
+	return segments
+}
+
+// routingHopTreeNode() returns the tree node of an endpoint of a routing path, or nil if it has none
+func (d *DrawioOutputFormatter) routingHopTreeNode(endpoint *Endpoint, dst Node) drawio.TreeNodeInterface {
+	switch {
+	case endpoint.VpcResource != nil:
+		return d.gen.TreeNode(endpoint.VpcResource)
+	case endpoint.NextHop != nil:
+		nextHopNode, err := d.cConfigs.GetInternalNodeFromAddress(endpoint.NextHop.NextHop.ToIPAddressString())
+		if err != nil {
+			return nil
+		}
+		return d.gen.TreeNode(nextHopNode.(Node))
+	case endpoint.IPBlock != nil && endpoint.IPBlock.Equal(dst.IPBlock()):
+		return d.gen.TreeNode(dst)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+)
+
+// Functions for the computation of the usage of each nacl and sg rule: the pairs of endpoints and the connections
+// between them that the rule contributes to
+
+const (
+	unusedMarker       = "[unused]"
+	deniedStr          = "denied"
+	notAttachedSuffix  = " (not attached to any resource)"
+	mdRuleUsageHeader  = "| filter | rule | used by |\n|--------|------|---------|"
+	allRulesUsedHeader = "All rules contribute to connections between endpoints\n"
+	unusedRulesHeader  = "%d of %d rules do not contribute to any connection between endpoints, marked with " + unusedMarker + "\n"
+)
+
+// ruleUsageLine is a connection between an internal endpoint and a group of nodes to which a rule contributes;
+// the group is either a single internal endpoint or the external nodes of the same kind sharing the connection.
+// conn is the connection allowed between the endpoints; it is nil for the traffic between them denied by the rule
+type ruleUsageLine struct {
+	src  EndpointElem
+	dst  EndpointElem
+	conn *netset.TransportSet
+}
+
+// ruleUsage is the usage of a single rule of a filter
+type ruleUsage struct {
+	layer       string // the layer kind, e.g. NaclLayer
+	filter      Filter
+	ruleIndex   int
+	description string
+	lines       []*ruleUsageLine
+}
+
+// ruleUsageAnalysis holds the usage of all nacl and sg rules of a single VPCConfig
+type ruleUsageAnalysis struct {
+	c           *VPCConfig
+	rules       []*ruleUsage
+	notAttached map[Filter]bool
+}
+
+// ruleKey identifies a rule within a VPCConfig
+type ruleKey struct {
+	layer       string
+	filterIndex int
+	ruleIndex   int
+}
+
+// newRuleUsageAnalysis computes, for each nacl and sg rule of c, the pairs of endpoints it contributes to.
+// An allow rule contributes to the connection between two endpoints if it is among the rules enabling it.
+// A deny rule contributes to each pair of endpoints whose traffic it denies, whether or not they are connected.
+func newRuleUsageAnalysis(c *VPCConfig, conn *VPCConnectivity) (*ruleUsageAnalysis, error) {
+	res := &ruleUsageAnalysis{c: c, notAttached: map[Filter]bool{}}
+	rulesByKey := map[ruleKey]*ruleUsage{}
+	for _, layer := range []string{NaclLayer, SecurityGroupLayer} {
+		filterLayer := c.GetFilterTrafficResourceOfKind(layer)
+		if filterLayer == nil {
+			continue
+		}
+		layerRules, err := filterLayer.GetRules()
+		if err != nil {
+			return nil, err
+		}
+		for i := range layerRules {
+			rule := &layerRules[i]
+			usage := &ruleUsage{layer: layer, filter: rule.Filter, ruleIndex: rule.RuleIndex,
+				description: strings.TrimSpace(rule.RuleDesc)}
+			rulesByKey[ruleKey{layer, rule.Filter.FilterIndex, rule.RuleIndex}] = usage
+			res.rules = append(res.rules, usage)
+		}
+		for filter, attached := range filterLayer.GetFiltersAttachedResources() {
+			if len(attached) == 0 {
+				res.notAttached[filter] = true
+			}
+		}
+	}
+	linesByKey := map[string]*ruleUsageLine{}
+	for _, src := range c.Nodes {
+		for _, dst := range c.Nodes {
+			if err := res.addPairUsage(src, dst, conn, rulesByKey, linesByKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, usage := range res.rules {
+		sort.Slice(usage.lines, func(i, j int) bool {
+			return res.lineStr(usage.lines[i]) < res.lineStr(usage.lines[j])
+		})
+	}
+	sort.Slice(res.rules, func(i, j int) bool {
+		if res.rules[i].layer != res.rules[j].layer {
+			return res.rules[i].layer == NaclLayer
+		}
+		if res.rules[i].filter.FilterName != res.rules[j].filter.FilterName {
+			return res.rules[i].filter.FilterName < res.rules[j].filter.FilterName
+		}
+		return res.rules[i].ruleIndex < res.rules[j].ruleIndex
+	})
+	return res, nil
+}
+
+// addPairUsage adds the pair src, dst to the usage of the rules contributing to the connection between them:
+// the allow rules enabling the allowed connection, and the deny rules blocking (part of) the traffic
+func (r *ruleUsageAnalysis) addPairUsage(src, dst Node, conn *VPCConnectivity,
+	rulesByKey map[ruleKey]*ruleUsage, linesByKey map[string]*ruleUsageLine) error {
+	if !src.IsInternal() && !dst.IsInternal() {
+		return nil
+	}
+	considerPair, err := r.c.shouldConsiderPairForConnectivity(src, dst)
+	if err != nil || !considerPair {
+		return err
+	}
+	pairConn, ok := conn.AllowedConnsCombinedResponsive[src][dst]
+	if ok && !pairConn.allConn.IsEmpty() {
+		allowRules, _, errAllow := getRulesOfConnection(r.c, src, dst, pairConn.allConn)
+		if errAllow != nil {
+			return errAllow
+		}
+		r.addRulesUsage(src, dst, pairConn.allConn, allowRules, rulesByKey, linesByKey)
+	} else if !src.IsInternal() || !dst.IsInternal() {
+		// a pair of an internal and an external node is relevant only if there is a router between them
+		router, _, errRouter := r.c.getRoutingResource(src, dst)
+		if errRouter != nil || router == nil {
+			return errRouter
+		}
+	}
+	_, denyRules, err := getRulesOfConnection(r.c, src, dst, nil)
+	if err != nil {
+		return err
+	}
+	r.addRulesUsage(src, dst, nil, denyRules, rulesByKey, linesByKey)
+	return nil
+}
+
+// addRulesUsage adds the pair src, dst with the given connection to the usage of each of the given rules;
+// a nil connection stands for traffic denied by the rules
+func (r *ruleUsageAnalysis) addRulesUsage(src, dst Node, conn *netset.TransportSet, rules *rulesConnection,
+	rulesByKey map[ruleKey]*ruleUsage, linesByKey map[string]*ruleUsageLine) {
+	for _, rulesPerLayer := range []rulesInLayers{rules.ingressRules, rules.egressRules} {
+		for layer, rulesInTables := range rulesPerLayer {
+			for _, rulesInTable := range rulesInTables {
+				for _, ruleIndex := range rulesInTable.Rules {
+					if usage, ok := rulesByKey[ruleKey{layer, rulesInTable.TableIndex, ruleIndex}]; ok {
+						usage.addLine(src, dst, conn, linesByKey)
+					}
+				}
+			}
+		}
+	}
+}
+
+// addLine adds the pair src, dst to the rule's usage lines; the external nodes of a pair are merged
+// with those of an existing line of the same rule, internal endpoint, direction and connection
+func (u *ruleUsage) addLine(src, dst Node, conn *netset.TransportSet, linesByKey map[string]*ruleUsageLine) {
+	if src.IsInternal() && dst.IsInternal() {
+		u.lines = append(u.lines, &ruleUsageLine{src: src, dst: dst, conn: conn})
+		return
+	}
+	endpoint, external, direction := src, dst, outboundStr
+	if dst.IsInternal() {
+		endpoint, external, direction = dst, src, inboundStr
+	}
+	externalNode, ok := external.(*ExternalNetwork)
+	if !ok {
+		return
+	}
+	key := strings.Join([]string{u.layer, fmt.Sprint(u.filter.FilterIndex), fmt.Sprint(u.ruleIndex), endpoint.UID(),
+		direction, externalNode.ResourceType, lineConnStr(conn)}, semicolon)
+	if line, ok := linesByKey[key]; ok {
+		groupedElem := line.src
+		if direction == outboundStr {
+			groupedElem = line.dst
+		}
+		grouped := groupedElem.(*groupedExternalNodes)
+		*grouped = append(*grouped, externalNode)
+		return
+	}
+	line := &ruleUsageLine{src: endpoint, dst: &groupedExternalNodes{externalNode}, conn: conn}
+	if direction == inboundStr {
+		line.src, line.dst = line.dst, endpoint
+	}
+	linesByKey[key] = line
+	u.lines = append(u.lines, line)
+}
+
+func (u *ruleUsage) isUsed() bool {
+	return len(u.lines) > 0
+}
+
+func (r *ruleUsageAnalysis) filterStr(filter Filter) string {
+	res := filter.LayerName + space + filter.FilterName
+	if r.notAttached[filter] {
+		res += notAttachedSuffix
+	}
+	return res
+}
+
+func (r *ruleUsageAnalysis) numUnused() int {
+	res := 0
+	for _, usage := range r.rules {
+		if !usage.isUsed() {
+			res++
+		}
+	}
+	return res
+}
+
+func lineConnStr(conn *netset.TransportSet) string {
+	if conn == nil {
+		return deniedStr
+	}
+	return common.LongString(conn)
+}
+
+func (r *ruleUsageAnalysis) lineStr(line *ruleUsageLine) string {
+	return getConnectionStr(line.src.NameForAnalyzerOut(r.c), line.dst.NameForAnalyzerOut(r.c), lineConnStr(line.conn), "")
+}
+
+func (r *ruleUsageAnalysis) linesStr(usage *ruleUsage) []string {
+	res := make([]string, len(usage.lines))
+	for i, line := range usage.lines {
+		res[i] = r.lineStr(line)
+	}
+	return res
+}
+
+// String returns a header with the number of unused rules, and per filter its rules, each with the
+// connections it contributes to; unused rules are marked
+func (r *ruleUsageAnalysis) String() string {
+	res := allRulesUsedHeader
+	if unused := r.numUnused(); unused > 0 {
+		res = fmt.Sprintf(unusedRulesHeader, unused, len(r.rules))
+	}
+	for i, usage := range r.rules {
+		if i == 0 || usage.filter != r.rules[i-1].filter {
+			res += newLine + r.filterStr(usage.filter) + ":\n"
+		}
+		if !usage.isUsed() {
+			res += "\t" + unusedMarker + space + usage.description + newLine
+			continue
+		}
+		res += "\t" + usage.description + newLine
+		for _, line := range r.linesStr(usage) {
+			res += "\t\t" + line
+		}
+	}
+	return res
+}
+
+func (r *ruleUsageAnalysis) mdLines() []string {
+	lines := make([]string, len(r.rules))
+	for i, usage := range r.rules {
+		usedBy := "**unused**"
+		if usage.isUsed() {
+			usedBy = strings.Join(r.linesStr(usage), "<br>")
+			usedBy = strings.ReplaceAll(usedBy, newLine, emptyString)
+		}
+		lines[i] = fmt.Sprintf("| %s | %s | %s |", r.filterStr(usage.filter), usage.description, usedBy)
+	}
+	return lines
+}
+
+type ruleUsageLineJSON struct {
+	Src    EndpointElem   `json:"src"`
+	Dst    EndpointElem   `json:"dst"`
+	Conn   netset.Details `json:"conn,omitempty"`
+	Denied bool           `json:"denied"`
+}
+
+type ruleUsageJSON struct {
+	Layer       string              `json:"layer"`
+	Table       string              `json:"table"`
+	RuleIndex   int                 `json:"rule_index"`
+	Description string              `json:"rule_description"`
+	Used        bool                `json:"used"`
+	UsedBy      []ruleUsageLineJSON `json:"used_by"`
+}
+
+type allRulesUsage struct {
+	RulesUsage []ruleUsageJSON `json:"rules_usage"`
+}
+
+// endpointElemJSON returns e for json output; grouped external nodes are represented by a single external
+// network whose cidr is the list of the merged cidrs of the group
+func endpointElemJSON(e EndpointElem) EndpointElem {
+	if external, ok := e.(*groupedExternalNodes); ok {
+		return &ExternalNetwork{ResourceType: external.resourceType(), CidrStr: external.String()}
+	}
+	return e
+}
+
+func (r *ruleUsageAnalysis) toJSON() allRulesUsage {
+	res := make([]ruleUsageJSON, len(r.rules))
+	for i, usage := range r.rules {
+		usedBy := make([]ruleUsageLineJSON, len(usage.lines))
+		for j, line := range usage.lines {
+			usedBy[j] = ruleUsageLineJSON{Src: endpointElemJSON(line.src), Dst: endpointElemJSON(line.dst),
+				Denied: line.conn == nil}
+			if line.conn != nil {
+				usedBy[j].Conn = netset.ToJSON(line.conn)
+			}
+		}
+		res[i] = ruleUsageJSON{Layer: usage.filter.LayerName, Table: usage.filter.FilterName, RuleIndex: usage.ruleIndex,
+			Description: usage.description, Used: usage.isUsed(), UsedBy: usedBy}
+	}
+	return allRulesUsage{RulesUsage: res}
+}
@@ -93,3 +93,25 @@ func getHeaderRulesType(filter string, tableEffect TableEffect, rType RulesType)
 		return ""
 	}
 }
+
+// ruleJSON is the json representation of a single rule of a filter (sg/nacl)
+type ruleJSON struct {
+	Layer       string `json:"layer"`
+	Table       string `json:"table"`
+	RuleIndex   int    `json:"rule_index"`
+	Description string `json:"rule_description"`
+}
+
+// rulesJSON returns the json representation of the specified rules of a filter, sorted by rules indexes
+func (rules *rulesDetails) rulesJSON(filterLayer string, filterIndex int, rulesIndexes []int) []ruleJSON {
+	filterDetails := (*rules)[filterLayer][filterIndex]
+	res := make([]ruleJSON, len(rulesIndexes))
+	for i, ruleIndex := range rulesIndexes {
+		res[i] = ruleJSON{Layer: FilterKindName(filterLayer), Table: filterDetails.tableName, RuleIndex: ruleIndex,
+			Description: strings.TrimSpace(filterDetails.rulesDesc[ruleIndex])}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].RuleIndex < res[j].RuleIndex
+	})
+	return res
+}
@@ -0,0 +1,540 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netp"
+	"github.com/np-guard/models/pkg/netset"
+)
+
+// Functions for the segmentation analysis against a user-defined zoning model:
+// endpoints and subnets are assigned to security zones (e.g. dmz, app, data), the connectivity is
+// aggregated into a zone x zone matrix, and connections not allowed by the zone-to-zone policy are flagged
+
+const (
+	// externalZone is the zone of all external addresses
+	externalZone = "external"
+	// unassignedZone is the zone of the internal endpoints not assigned to any zone
+	unassignedZone = "unassigned"
+
+	anyProtocol      = "ANY"
+	noConnCell       = "-"
+	violationStr     = "VIOLATION"
+	segmentationName = "Segmentation matrix"
+)
+
+// ZoningModel assigns endpoints and subnets to security zones, and defines the connections allowed between zones.
+// an endpoint belongs to the zone it is assigned to, otherwise to the zone of its subnet;
+// connections within a zone are always allowed
+type ZoningModel struct {
+	name           string // base name of the zoning file
+	zones          []string
+	resourceToZone map[string]string                          // from resource name or uid to its zone
+	policy         map[string]map[string]*netset.TransportSet // allowed connections from src zone to dst zone
+}
+
+// zonePolicyRule allows a connection from src zone to dst zone; protocol is one of TCP, UDP, ICMP or ANY (default);
+// with TCP or UDP, the connection may be restricted to the destination ports range [min_port, max_port]
+type zonePolicyRule struct {
+	Src      string `json:"src"`
+	Dst      string `json:"dst"`
+	Protocol string `json:"protocol,omitempty"`
+	MinPort  int64  `json:"min_port,omitempty"`
+	MaxPort  int64  `json:"max_port,omitempty"`
+}
+
+type zoningFile struct {
+	Zones  map[string][]string `json:"zones"`
+	Policy []zonePolicyRule    `json:"policy"`
+}
+
+// NewZoningModelFromFile reads a json zoning file, mapping each zone to the names or uids of its endpoints
+// and subnets, with the allowed zone-to-zone policy, e.g.:
+// {"zones": {"dmz": ["subnet1"], "app": ["vsi1", "vsi2"]},
+// "policy": [{"src": "external", "dst": "dmz", "protocol": "TCP", "min_port": 443, "max_port": 443}]}
+func NewZoningModelFromFile(fileName string) (*ZoningModel, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	zoning := zoningFile{}
+	if errParse := json.Unmarshal(content, &zoning); errParse != nil {
+		return nil, fmt.Errorf("failed to parse zoning file %s: %w", fileName, errParse)
+	}
+	if len(zoning.Zones) == 0 {
+		return nil, fmt.Errorf("zoning file %s has no zones", fileName)
+	}
+	res := &ZoningModel{name: filepath.Base(fileName), resourceToZone: map[string]string{},
+		policy: map[string]map[string]*netset.TransportSet{}}
+	for zone, resources := range zoning.Zones {
+		if zone == externalZone || zone == unassignedZone {
+			return nil, fmt.Errorf("zoning file %s: zone name %s is reserved", fileName, zone)
+		}
+		res.zones = append(res.zones, zone)
+		for _, resource := range resources {
+			if otherZone, ok := res.resourceToZone[resource]; ok && otherZone != zone {
+				return nil, fmt.Errorf("zoning file %s assigns %s to both %s and %s", fileName, resource, otherZone, zone)
+			}
+			res.resourceToZone[resource] = zone
+		}
+	}
+	sort.Strings(res.zones)
+	for i := range zoning.Policy {
+		if errRule := res.addPolicyRule(&zoning.Policy[i]); errRule != nil {
+			return nil, fmt.Errorf("zoning file %s: %w", fileName, errRule)
+		}
+	}
+	return res, nil
+}
+
+func (z *ZoningModel) isZone(zone string) bool {
+	return zone == externalZone || zone == unassignedZone || slices.Contains(z.zones, zone)
+}
+
+func (z *ZoningModel) addPolicyRule(rule *zonePolicyRule) error {
+	for _, zone := range []string{rule.Src, rule.Dst} {
+		if !z.isZone(zone) {
+			return fmt.Errorf("policy refers to an unknown zone %s", zone)
+		}
+	}
+	conn, err := rule.connection()
+	if err != nil {
+		return err
+	}
+	if _, ok := z.policy[rule.Src]; !ok {
+		z.policy[rule.Src] = map[string]*netset.TransportSet{}
+	}
+	if existing, ok := z.policy[rule.Src][rule.Dst]; ok {
+		conn = existing.Union(conn)
+	}
+	z.policy[rule.Src][rule.Dst] = conn
+	return nil
+}
+
+func (r *zonePolicyRule) connection() (*netset.TransportSet, error) {
+	protocol := strings.ToUpper(r.Protocol)
+	hasPorts := r.MinPort != 0 || r.MaxPort != 0
+	switch protocol {
+	case "", anyProtocol, string(netp.ProtocolStringICMP):
+		if hasPorts {
+			return nil, fmt.Errorf("policy rule %s => %s: ports are supported only with TCP or UDP", r.Src, r.Dst)
+		}
+		if protocol == string(netp.ProtocolStringICMP) {
+			return netset.AllICMPTransport(), nil
+		}
+		return netset.AllTransports(), nil
+	case string(netp.ProtocolStringTCP), string(netp.ProtocolStringUDP):
+		minPort, maxPort := int64(netp.MinPort), int64(netp.MaxPort)
+		if hasPorts {
+			minPort, maxPort = r.MinPort, r.MaxPort
+		}
+		if minPort < netp.MinPort || maxPort > netp.MaxPort || minPort > maxPort {
+			return nil, fmt.Errorf("policy rule %s => %s: illegal ports range %d-%d", r.Src, r.Dst, minPort, maxPort)
+		}
+		return netset.NewTCPorUDPTransport(netp.ProtocolString(protocol), netp.MinPort, netp.MaxPort, minPort, maxPort), nil
+	}
+	return nil, fmt.Errorf("policy rule %s => %s: illegal protocol %s", r.Src, r.Dst, r.Protocol)
+}
+
+// allowed returns the connections allowed from src zone to dst zone
+func (z *ZoningModel) allowed(src, dst string) *netset.TransportSet {
+	if src == dst {
+		return netset.AllTransports()
+	}
+	if allowed, ok := z.policy[src][dst]; ok {
+		return allowed
+	}
+	return NoConns()
+}
+
+// nodeZone returns the zone of an endpoint: the zone assigned to it, otherwise the zone of its subnet
+func (z *ZoningModel) nodeZone(node Node) string {
+	if !node.IsInternal() {
+		return externalZone
+	}
+	resources := []VPCResourceIntf{node}
+	if internal, ok := node.(InternalNodeIntf); ok && internal.Subnet() != nil {
+		resources = append(resources, internal.Subnet())
+	}
+	for _, resource := range resources {
+		for _, name := range resourceNames(resource) {
+			if zone, ok := z.resourceToZone[name]; ok {
+				return zone
+			}
+		}
+	}
+	return unassignedZone
+}
+
+// segmentationPair is the connection between two endpoints, along with its part not allowed by the policy
+type segmentationPair struct {
+	src       Node
+	dst       Node
+	conn      *detailedConn
+	violation *detailedConn
+}
+
+// segmentationCell is a cell of the zone x zone matrix: the connections from endpoints of src zone
+// to endpoints of dst zone
+type segmentationCell struct {
+	srcZone   string
+	dstZone   string
+	conn      *detailedConn
+	violation *detailedConn
+	pairs     []*segmentationPair
+}
+
+// SegmentationAnalysis holds the zone x zone connectivity matrix of a zoning model
+type SegmentationAnalysis struct {
+	zoning *ZoningModel
+	// zones of the matrix: the zones of the model, followed by the built-in zones that have endpoints
+	zones []string
+	cells map[string]map[string]*segmentationCell
+	// configs of the endpoints, used for printing their names
+	nodeToConfig map[string]*VPCConfig
+}
+
+// ComputeSegmentation computes the zone x zone connectivity matrix of the given zoning model,
+// from the connectivity between endpoints of all configs
+func (c *MultipleVPCConfigs) ComputeSegmentation(zoning *ZoningModel) (*SegmentationAnalysis, error) {
+	if zoning == nil {
+		return nil, errors.New("missing zoning model for segmentation analysis")
+	}
+	res := &SegmentationAnalysis{zoning: zoning, cells: map[string]map[string]*segmentationCell{},
+		nodeToConfig: map[string]*VPCConfig{}}
+	pairs := map[string]*segmentationPair{}
+	for _, vpcConfig := range c.Configs() {
+		vpcConn, err := vpcConfig.GetVPCNetworkConnectivity(false, NoGroupingNoConsistencyEdges)
+		if err != nil {
+			return nil, err
+		}
+		for src, srcMap := range vpcConn.AllowedConnsCombinedResponsive {
+			for dst, conn := range srcMap {
+				srcNode, srcIsNode := src.(Node)
+				dstNode, dstIsNode := dst.(Node)
+				// multi-vpc configs do not capture connectivity to external networks
+				if !srcIsNode || !dstIsNode || conn.isEmpty() ||
+					(vpcConfig.IsMultipleVPCsConfig && (!srcNode.IsInternal() || !dstNode.IsInternal())) {
+					continue
+				}
+				res.addPair(pairs, vpcConfig, srcNode, dstNode, conn)
+			}
+		}
+	}
+	res.computeCells(pairs)
+	return res, nil
+}
+
+func (s *SegmentationAnalysis) addPair(pairs map[string]*segmentationPair, c *VPCConfig, src, dst Node, conn *detailedConn) {
+	// a node's name is printed w.r.t. its single vpc config, if there is one
+	for _, node := range []Node{src, dst} {
+		if existing, ok := s.nodeToConfig[node.UID()]; !ok || (existing.IsMultipleVPCsConfig && !c.IsMultipleVPCsConfig) {
+			s.nodeToConfig[node.UID()] = c
+		}
+	}
+	key := src.UID() + semicolon + dst.UID()
+	if existing, ok := pairs[key]; ok {
+		existing.conn = existing.conn.union(conn)
+		return
+	}
+	pairs[key] = &segmentationPair{src: src, dst: dst, conn: conn}
+}
+
+func (s *SegmentationAnalysis) computeCells(pairs map[string]*segmentationPair) {
+	usedZones := map[string]bool{}
+	for _, pair := range pairs {
+		srcZone, dstZone := s.zoning.nodeZone(pair.src), s.zoning.nodeZone(pair.dst)
+		usedZones[srcZone], usedZones[dstZone] = true, true
+		allowed := s.zoning.allowed(srcZone, dstZone)
+		pair.violation = pair.conn.subtract(pair.conn.intersect(allowed))
+		if _, ok := s.cells[srcZone]; !ok {
+			s.cells[srcZone] = map[string]*segmentationCell{}
+		}
+		cell, ok := s.cells[srcZone][dstZone]
+		if !ok {
+			cell = &segmentationCell{srcZone: srcZone, dstZone: dstZone, conn: emptyDetailedConn(), violation: emptyDetailedConn()}
+			s.cells[srcZone][dstZone] = cell
+		}
+		cell.conn = cell.conn.union(pair.conn)
+		cell.violation = cell.violation.union(pair.violation)
+		cell.pairs = append(cell.pairs, pair)
+	}
+	s.zones = append([]string{}, s.zoning.zones...)
+	for _, builtIn := range []string{unassignedZone, externalZone} {
+		if usedZones[builtIn] {
+			s.zones = append(s.zones, builtIn)
+		}
+	}
+	for _, cell := range s.sortedCells() {
+		sort.Slice(cell.pairs, func(i, j int) bool {
+			if s.nodeName(cell.pairs[i].src) != s.nodeName(cell.pairs[j].src) {
+				return s.nodeName(cell.pairs[i].src) < s.nodeName(cell.pairs[j].src)
+			}
+			return s.nodeName(cell.pairs[i].dst) < s.nodeName(cell.pairs[j].dst)
+		})
+	}
+}
+
+func (s *SegmentationAnalysis) nodeName(n Node) string {
+	return n.NameForAnalyzerOut(s.nodeToConfig[n.UID()])
+}
+
+// cell returns the cell of src zone and dst zone, or nil if there are no connections between them
+func (s *SegmentationAnalysis) cell(srcZone, dstZone string) *segmentationCell {
+	return s.cells[srcZone][dstZone]
+}
+
+// sortedCells returns the non-empty cells of the matrix, row by row
+func (s *SegmentationAnalysis) sortedCells() []*segmentationCell {
+	res := []*segmentationCell{}
+	for _, srcZone := range s.zones {
+		for _, dstZone := range s.zones {
+			if cell := s.cell(srcZone, dstZone); cell != nil {
+				res = append(res, cell)
+			}
+		}
+	}
+	return res
+}
+
+func (s *SegmentationAnalysis) violatingCells() []*segmentationCell {
+	res := []*segmentationCell{}
+	for _, cell := range s.sortedCells() {
+		if !cell.violation.isEmpty() {
+			res = append(res, cell)
+		}
+	}
+	return res
+}
+
+func (s *SegmentationAnalysis) hasStatelessConns() bool {
+	for _, cell := range s.sortedCells() {
+		if !cell.conn.TCPRspDisable.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SegmentationAnalysis) header() string {
+	return fmt.Sprintf("%s for zoning model %s", segmentationName, s.zoning.name)
+}
+
+func (c *segmentationCell) title() string {
+	return c.srcZone + " => " + c.dstZone
+}
+
+// matrixEntry returns the text of the matrix entry of src zone and dst zone
+func (s *SegmentationAnalysis) matrixEntry(srcZone, dstZone string) string {
+	cell := s.cell(srcZone, dstZone)
+	if cell == nil {
+		return noConnCell
+	}
+	if !cell.violation.isEmpty() {
+		return violationStr + ": " + cell.conn.string()
+	}
+	return cell.conn.string()
+}
+
+func (s *SegmentationAnalysis) String() string {
+	lines := []string{s.header()}
+	for _, cell := range s.sortedCells() {
+		line := getConnectionStr(cell.srcZone, cell.dstZone, cell.conn.string(), "")
+		if !cell.violation.isEmpty() {
+			line = strings.TrimSuffix(line, newLine) + fmt.Sprintf(" (%s: %s)\n", violationStr, cell.violation.string())
+		}
+		for _, pair := range cell.pairs {
+			line += "\t" + getConnectionStr(s.nodeName(pair.src), s.nodeName(pair.dst), pair.conn.string(), "")
+		}
+		lines = append(lines, strings.TrimSuffix(line, newLine))
+	}
+	if len(s.cells) == 0 {
+		lines = append(lines, "No connections between zones")
+	}
+	return strings.Join(lines, newLine) + newLine
+}
+
+func (s *SegmentationAnalysis) mdString() string {
+	lines := []string{"# " + s.header(), "", "| src \\ dst | " + strings.Join(s.zones, " | ") + " |",
+		"|" + strings.Repeat("---|", len(s.zones)+1)}
+	for _, srcZone := range s.zones {
+		entries := make([]string, len(s.zones))
+		for i, dstZone := range s.zones {
+			entries[i] = s.matrixEntry(srcZone, dstZone)
+			if strings.HasPrefix(entries[i], violationStr) {
+				entries[i] = "**" + entries[i] + "**"
+			}
+		}
+		lines = append(lines, fmt.Sprintf("| **%s** | %s |", srcZone, strings.Join(entries, " | ")))
+	}
+	lines = append(lines, "", "## Policy violations")
+	violations := s.violatingCells()
+	if len(violations) == 0 {
+		lines = append(lines, "No policy violations")
+	} else {
+		lines = append(lines, "| src zone | dst zone | violating connection |", "|----------|----------|----------------------|")
+		for _, cell := range violations {
+			lines = append(lines, fmt.Sprintf("| %s | %s | %s |", cell.srcZone, cell.dstZone, cell.violation.string()))
+		}
+	}
+	for _, cell := range s.sortedCells() {
+		lines = append(lines, "", "## "+cell.title(), "| src | dst | connection | violating connection |",
+			"|-----|-----|------------|----------------------|")
+		for _, pair := range cell.pairs {
+			violation := noConnCell
+			if !pair.violation.isEmpty() {
+				violation = pair.violation.string()
+			}
+			lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s |", s.nodeName(pair.src), s.nodeName(pair.dst),
+				pair.conn.string(), violation))
+		}
+	}
+	return strings.Join(lines, newLine) + newLine
+}
+
+const segmentationHTMLStyle = `<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 20px; }
+th, td { border: 1px solid #999; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background-color: #eee; }
+td.violation { background-color: #f8c8c8; font-weight: bold; }
+td.allowed { background-color: #d8f0d8; }
+</style>`
+
+func (s *SegmentationAnalysis) htmlString() string {
+	lines := []string{"<!DOCTYPE html>", "<html>", "<head>", "<meta charset=\"utf-8\">",
+		"<title>" + html.EscapeString(s.header()) + "</title>", segmentationHTMLStyle, "</head>", "<body>",
+		"<h1>" + html.EscapeString(s.header()) + "</h1>", "<table>", "<tr><th>src \\ dst</th>"}
+	for _, zone := range s.zones {
+		lines[len(lines)-1] += "<th>" + html.EscapeString(zone) + "</th>"
+	}
+	lines[len(lines)-1] += "</tr>"
+	for _, srcZone := range s.zones {
+		row := "<tr><th>" + html.EscapeString(srcZone) + "</th>"
+		for _, dstZone := range s.zones {
+			class := "allowed"
+			if cell := s.cell(srcZone, dstZone); cell == nil {
+				class = "empty"
+			} else if !cell.violation.isEmpty() {
+				class = "violation"
+			}
+			row += fmt.Sprintf("<td class=%q>%s</td>", class, html.EscapeString(s.matrixEntry(srcZone, dstZone)))
+		}
+		lines = append(lines, row+"</tr>")
+	}
+	lines = append(lines, "</table>")
+	for _, cell := range s.sortedCells() {
+		lines = append(lines, "<h2>"+html.EscapeString(cell.title())+"</h2>", "<table>",
+			"<tr><th>src</th><th>dst</th><th>connection</th><th>violating connection</th></tr>")
+		for _, pair := range cell.pairs {
+			violation, class := noConnCell, "allowed"
+			if !pair.violation.isEmpty() {
+				violation, class = pair.violation.string(), "violation"
+			}
+			lines = append(lines, fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td><td class=%q>%s</td></tr>",
+				html.EscapeString(s.nodeName(pair.src)), html.EscapeString(s.nodeName(pair.dst)),
+				html.EscapeString(pair.conn.string()), class, html.EscapeString(violation)))
+		}
+		lines = append(lines, "</table>")
+	}
+	lines = append(lines, "</body>", "</html>")
+	return strings.Join(lines, newLine) + newLine
+}
+
+type segmentationPairJSON struct {
+	Src                Node           `json:"src"`
+	Dst                Node           `json:"dst"`
+	Conn               netset.Details `json:"conn"`
+	UnidirectionalConn netset.Details `json:"unidirectional_conn,omitempty"`
+	ViolatingConn      netset.Details `json:"violating_conn,omitempty"`
+}
+
+type segmentationCellJSON struct {
+	SrcZone            string                 `json:"src_zone"`
+	DstZone            string                 `json:"dst_zone"`
+	Conn               netset.Details         `json:"conn"`
+	UnidirectionalConn netset.Details         `json:"unidirectional_conn,omitempty"`
+	Violation          bool                   `json:"violation"`
+	ViolatingConn      netset.Details         `json:"violating_conn,omitempty"`
+	Pairs              []segmentationPairJSON `json:"pairs"`
+}
+
+type segmentationJSON struct {
+	Zones []string               `json:"zones"`
+	Cells []segmentationCellJSON `json:"cells"`
+}
+
+// connDetails returns the json details of a detailedConn: its responsive (or non-TCP) component,
+// and its non-responsive TCP component if not empty
+func connDetails(conn *detailedConn) (responsive, unidirectional netset.Details) {
+	responsive = netset.ToJSON(conn.nonTCPAndResponsiveTCPComponent())
+	if !conn.TCPRspDisable.IsEmpty() {
+		unidirectional = netset.ToJSON(conn.TCPRspDisable)
+	}
+	return responsive, unidirectional
+}
+
+func (s *SegmentationAnalysis) toJSON() segmentationJSON {
+	cells := []segmentationCellJSON{}
+	for _, cell := range s.sortedCells() {
+		cellJSON := segmentationCellJSON{SrcZone: cell.srcZone, DstZone: cell.dstZone, Violation: !cell.violation.isEmpty()}
+		cellJSON.Conn, cellJSON.UnidirectionalConn = connDetails(cell.conn)
+		if cellJSON.Violation {
+			cellJSON.ViolatingConn = netset.ToJSON(cell.violation.allConn)
+		}
+		for _, pair := range cell.pairs {
+			pairJSON := segmentationPairJSON{Src: pair.src, Dst: pair.dst}
+			pairJSON.Conn, pairJSON.UnidirectionalConn = connDetails(pair.conn)
+			if !pair.violation.isEmpty() {
+				pairJSON.ViolatingConn = netset.ToJSON(pair.violation.allConn)
+			}
+			cellJSON.Pairs = append(cellJSON.Pairs, pairJSON)
+		}
+		cells = append(cells, cellJSON)
+	}
+	return segmentationJSON{Zones: s.zones, Cells: cells}
+}
+
+// segmentationOutputFormatter is the formatter of the segmentation analysis, for json, md, html and txt formats.
+// segmentationOutputFormatter implements the interface OutputFormatter; since the matrix is computed
+// over all configs, its output is not split per vpc
+type segmentationOutputFormatter struct {
+	outFormat OutFormat
+	zoning    *ZoningModel
+}
+
+func (sf *segmentationOutputFormatter) WriteOutput(cConfigs *MultipleVPCConfigs, _ map[string]*VPCConnectivity,
+	_ map[string]*VPCsubnetConnectivity, _ *diffBetweenCfgs,
+	outFile string, _ bool, uc OutputUseCase, _ *Explanation, _ bool) (string, error) {
+	segmentation, err := cConfigs.ComputeSegmentation(sf.zoning)
+	if err != nil {
+		return "", err
+	}
+	switch sf.outFormat {
+	case Text:
+		return WriteToFile(segmentation.String()+
+			getAsteriskDetails(uc, segmentation.hasStatelessConns(), false, sf.outFormat), outFile)
+	case MD:
+		return WriteToFile(segmentation.mdString()+
+			getAsteriskDetails(uc, segmentation.hasStatelessConns(), false, sf.outFormat), outFile)
+	case HTML:
+		return WriteToFile(segmentation.htmlString(), outFile)
+	case JSON:
+		return writeJSON(segmentation.toJSON(), outFile)
+	}
+	return "", errors.New("unsupported output format for segmentation")
+}
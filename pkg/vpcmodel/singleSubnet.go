@@ -0,0 +1,231 @@
+/*
+Copyright 2023- IBM Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vpcmodel
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/np-guard/models/pkg/netset"
+
+	"github.com/np-guard/vpc-network-config-analyzer/pkg/common"
+)
+
+// Functions for the analysis of the connectivity implied by the nacl applied to each subnet, separately per subnet
+
+const (
+	ingressConnectivityHeader = "Ingress Connectivity:\n"
+	egressConnectivityHeader  = "Egress Connectivity:\n"
+	ingressStr                = "ingress"
+	egressStr                 = "egress"
+	mdSingleSubnetHeader      = "| subnet | range | nacl | direction | remote | conn | allow rules | deny rules |\n" +
+		"|--------|-------|------|-----------|--------|------|-------------|------------|"
+)
+
+// RemoteConnectivity is the connection allowed by a filter (e.g. a subnet's nacl) between a resource attached to it
+// (or a local range within it) and a remote ip-block, in a single direction, along with the indexes of the rules
+// contributing to it
+type RemoteConnectivity struct {
+	Remote     *netset.IPBlock
+	Conn       *netset.TransportSet
+	AllowRules []int
+	DenyRules  []int
+}
+
+// SingleSubnetConnectivity is the ingress and egress connectivity implied by the nacl applied to a subnet.
+// If the nacl's rules split the subnet into local ranges of different connectivity, there is an element per range
+type SingleSubnetConnectivity struct {
+	SubnetName string
+	SubnetCidr string
+	NaclName   string
+	NaclIndex  int    // index of the nacl within its layer, as in Filter.FilterIndex
+	LocalRange string // empty if the connectivity is of the entire subnet
+	Ingress    []*RemoteConnectivity
+	Egress     []*RemoteConnectivity
+}
+
+// singleSubnetAnalysis holds the per-subnet nacl connectivity of a single VPCConfig
+type singleSubnetAnalysis struct {
+	subnets      []*SingleSubnetConnectivity
+	rulesDetails *rulesDetails
+}
+
+// newSingleSubnetAnalysis computes the connectivity of each of c's subnets implied by its nacl;
+// if grouping is true, remotes of the same subnet range and direction sharing the same connection are merged
+func newSingleSubnetAnalysis(c *VPCConfig, grouping bool) (*singleSubnetAnalysis, error) {
+	subnets, err := c.GetConnectivityPerEachSubnetSeparately()
+	if err != nil {
+		return nil, err
+	}
+	allRulesDetails, err := newRulesDetails(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, subnet := range subnets {
+		if grouping {
+			subnet.Ingress = groupRemotes(subnet.Ingress)
+			subnet.Egress = groupRemotes(subnet.Egress)
+		}
+		sortRemotes(subnet.Ingress)
+		sortRemotes(subnet.Egress)
+	}
+	sort.Slice(subnets, func(i, j int) bool {
+		if subnets[i].SubnetCidr != subnets[j].SubnetCidr {
+			return subnets[i].SubnetCidr < subnets[j].SubnetCidr
+		}
+		return subnets[i].LocalRange < subnets[j].LocalRange
+	})
+	return &singleSubnetAnalysis{subnets: subnets, rulesDetails: allRulesDetails}, nil
+}
+
+// groupRemotes merges remotes with the same connection into a single remote whose ip-block is their union,
+// and whose contributing rules are the union of their rules
+func groupRemotes(remotes []*RemoteConnectivity) []*RemoteConnectivity {
+	if remotes == nil {
+		return nil
+	}
+	res := []*RemoteConnectivity{}
+	for _, remote := range remotes {
+		i := slices.IndexFunc(res, func(grouped *RemoteConnectivity) bool { return grouped.Conn.Equal(remote.Conn) })
+		if i == -1 {
+			res = append(res, &RemoteConnectivity{Remote: remote.Remote, Conn: remote.Conn,
+				AllowRules: remote.AllowRules, DenyRules: remote.DenyRules})
+			continue
+		}
+		res[i].Remote = res[i].Remote.Union(remote.Remote)
+		res[i].AllowRules = unionRulesIndexes(res[i].AllowRules, remote.AllowRules)
+		res[i].DenyRules = unionRulesIndexes(res[i].DenyRules, remote.DenyRules)
+	}
+	return res
+}
+
+func unionRulesIndexes(rules1, rules2 []int) []int {
+	res := slices.Concat(rules1, rules2)
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+func sortRemotes(remotes []*RemoteConnectivity) {
+	slices.SortFunc(remotes, func(r1, r2 *RemoteConnectivity) int {
+		return r1.Remote.Compare(r2.Remote)
+	})
+}
+
+func remoteConnectivityStr(remote *RemoteConnectivity) string {
+	return fmt.Sprintf("remote: %s, conn: %s", remote.Remote.ToIPRanges(), common.LongString(remote.Conn))
+}
+
+func remotesConnectivityStr(header string, remotes []*RemoteConnectivity) string {
+	lines := make([]string, len(remotes))
+	for i, remote := range remotes {
+		lines[i] = remoteConnectivityStr(remote)
+	}
+	sort.Strings(lines)
+	return header + strings.Join(lines, newLine)
+}
+
+// String returns, per subnet, the ingress and egress connectivity of each of its local ranges
+func (s *singleSubnetAnalysis) String() string {
+	subnetsStr := []string{}
+	for i, subnet := range s.subnets {
+		if i == 0 || subnet.SubnetCidr != s.subnets[i-1].SubnetCidr {
+			subnetsStr = append(subnetsStr, "Subnet: "+subnet.SubnetCidr+newLine)
+		}
+		subnetStr := emptyString
+		if subnet.LocalRange != emptyString {
+			subnetStr += "\nlocal range within subnet: " + subnet.LocalRange + newLine
+		}
+		if subnet.Ingress != nil {
+			subnetStr += remotesConnectivityStr(ingressConnectivityHeader, subnet.Ingress)
+		}
+		if subnet.Egress != nil {
+			subnetStr += newLine + remotesConnectivityStr(egressConnectivityHeader, subnet.Egress)
+		}
+		subnetsStr[len(subnetsStr)-1] += subnetStr + newLine
+	}
+	return strings.Join(subnetsStr, newLine)
+}
+
+// rulesDescriptions returns the descriptions of the given rules of the subnet's nacl, sorted by rules indexes
+func (s *singleSubnetAnalysis) rulesDescriptions(subnet *SingleSubnetConnectivity, rules []int) []string {
+	rulesJSON := s.rulesDetails.rulesJSON(NaclLayer, subnet.NaclIndex, rules)
+	res := make([]string, len(rulesJSON))
+	for i := range rulesJSON {
+		res[i] = rulesJSON[i].Description
+	}
+	return res
+}
+
+func (s *singleSubnetAnalysis) mdLines() []string {
+	lines := []string{}
+	for _, subnet := range s.subnets {
+		localRange := subnet.SubnetCidr
+		if subnet.LocalRange != emptyString {
+			localRange = subnet.LocalRange
+		}
+		for _, direction := range []string{ingressStr, egressStr} {
+			remotes := subnet.Ingress
+			if direction == egressStr {
+				remotes = subnet.Egress
+			}
+			for _, remote := range remotes {
+				lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s |", subnet.SubnetName, localRange,
+					subnet.NaclName, direction, remote.Remote.ToIPRanges(), common.LongString(remote.Conn),
+					strings.Join(s.rulesDescriptions(subnet, remote.AllowRules), "<br>"),
+					strings.Join(s.rulesDescriptions(subnet, remote.DenyRules), "<br>")))
+			}
+		}
+	}
+	return lines
+}
+
+type remoteConnectivityJSON struct {
+	Remote     []string       `json:"remote"`
+	Conn       netset.Details `json:"conn"`
+	AllowRules []ruleJSON     `json:"allow_rules"`
+	DenyRules  []ruleJSON     `json:"deny_rules"`
+}
+
+type singleSubnetConnectivityJSON struct {
+	Subnet     string                   `json:"subnet"`
+	Cidr       string                   `json:"cidr"`
+	Nacl       string                   `json:"nacl"`
+	LocalRange string                   `json:"local_range,omitempty"`
+	Ingress    []remoteConnectivityJSON `json:"ingress"`
+	Egress     []remoteConnectivityJSON `json:"egress"`
+}
+
+type allSingleSubnetConnectivity struct {
+	Subnets []singleSubnetConnectivityJSON `json:"subnets_connectivity"`
+}
+
+func (s *singleSubnetAnalysis) remotesJSON(subnet *SingleSubnetConnectivity,
+	remotes []*RemoteConnectivity) []remoteConnectivityJSON {
+	res := make([]remoteConnectivityJSON, len(remotes))
+	for i, remote := range remotes {
+		res[i] = newRemoteConnectivityJSON(remote, s.rulesDetails.rulesJSON(NaclLayer, subnet.NaclIndex, remote.AllowRules),
+			s.rulesDetails.rulesJSON(NaclLayer, subnet.NaclIndex, remote.DenyRules))
+	}
+	return res
+}
+
+func newRemoteConnectivityJSON(remote *RemoteConnectivity, allowRules, denyRules []ruleJSON) remoteConnectivityJSON {
+	return remoteConnectivityJSON{Remote: remote.Remote.ListToPrint(), Conn: netset.ToJSON(remote.Conn),
+		AllowRules: allowRules, DenyRules: denyRules}
+}
+
+func (s *singleSubnetAnalysis) toJSON() allSingleSubnetConnectivity {
+	res := make([]singleSubnetConnectivityJSON, len(s.subnets))
+	for i, subnet := range s.subnets {
+		res[i] = singleSubnetConnectivityJSON{Subnet: subnet.SubnetName, Cidr: subnet.SubnetCidr, Nacl: subnet.NaclName,
+			LocalRange: subnet.LocalRange, Ingress: s.remotesJSON(subnet, subnet.Ingress),
+			Egress: s.remotesJSON(subnet, subnet.Egress)}
+	}
+	return allSingleSubnetConnectivity{Subnets: res}
+}
@@ -347,15 +347,14 @@ func (v *VPCsubnetConnectivity) computeResponsiveConnections(allowedConnsCombine
 	return nil
 }
 
-// GetConnectivityOutputPerEachSubnetSeparately returns string results of connectivity analysis per
+// GetConnectivityPerEachSubnetSeparately returns the results of connectivity analysis per
 // single subnet with its attached nacl, separately per subnet - useful to get understanding of the
 // connectivity implied from nacl configuration applied on a certain subnet in the vpc
-func (c *VPCConfig) GetConnectivityOutputPerEachSubnetSeparately() string {
-	// iterate over all subnets, collect all outputs per subnet connectivity
+func (c *VPCConfig) GetConnectivityPerEachSubnetSeparately() ([]*SingleSubnetConnectivity, error) {
 	for _, r := range c.FilterResources {
 		if r.Kind() == NaclLayer {
-			return r.GetConnectivityOutputPerEachElemSeparately()
+			return r.ConnectivityPerEachElemSeparately()
 		}
 	}
-	return ""
+	return nil, nil
 }
@@ -8,8 +8,8 @@ package vpcmodel
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/np-guard/models/pkg/netset"
@@ -29,14 +29,12 @@ func (j *SynthesisOutputFormatter) WriteOutput(c1, c2 *VPCConfig,
 	uc OutputUseCase,
 	explanation *Explanation, detailExplain bool) (*SingleAnalysisOutput, error) {
 	var all interface{}
+	var labeledSegments map[string]bool
 	switch uc {
 	case AllEndpoints:
-		if grouping {
-			return nil, errors.New("report endpoints in synthesis format with grouping is not supported")
-		}
-		all = getSynthesisSpec(conn.GroupedConnectivity.GroupedLines, grouping)
+		all, labeledSegments = getSynthesisSpec(conn.GroupedConnectivity.GroupedLines, grouping)
 	case AllSubnets:
-		all = getSynthesisSpec(subnetsConn.GroupedConnectivity.GroupedLines, grouping)
+		all, labeledSegments = getSynthesisSpec(subnetsConn.GroupedConnectivity.GroupedLines, grouping)
 	}
 	outStr, err := writeJSON(all, outFile)
 	v2Name := ""
@@ -44,11 +42,11 @@ func (j *SynthesisOutputFormatter) WriteOutput(c1, c2 *VPCConfig,
 		v2Name = c2.VPC.Name()
 	}
 	return &SingleAnalysisOutput{Output: outStr, VPC1Name: c1.VPC.Name(),
-		VPC2Name: v2Name, format: Synthesis, jsonStruct: all}, err
+		VPC2Name: v2Name, format: Synthesis, jsonStruct: all, labeledSegments: labeledSegments}, err
 }
 
-func handleNameAndType(resource EndpointElem, externals spec.SpecExternals, segments spec.SpecSegments, grouping bool) (
-	resourceName string, resourceType spec.ResourceType) {
+func handleNameAndType(resource EndpointElem, externals spec.SpecExternals, segments spec.SpecSegments,
+	labeledSegments map[string]bool, grouping bool) (resourceName string, resourceType spec.ResourceType) {
 	resourceName = resource.SynthesisResourceName()
 	resourceType = resource.SynthesisKind()
 	if resource.IsExternal() {
@@ -62,30 +60,48 @@ func handleNameAndType(resource EndpointElem, externals spec.SpecExternals, segm
 		if groupObj, ok := resource.(*groupedEndpointsElems); ok {
 			// should be always true if src is internal"
 			// later in aggregate we change the name with other vpc configs
-			if len(*groupObj) > 1 {
-				segments[resourceName] = spec.Segment{Items: groupObj.AsNamesList(), Type: spec.SegmentTypeSubnet}
-			} else {
-				// grouping in synthesis only allowed with subnets
+			member := (*groupObj)[0]
+			switch {
+			case len(*groupObj) > 1:
+				// groups of members of different kinds are split by splitMixedGroups()
+				segmentType := segmentTypes[member.SynthesisKind()]
+				items := groupObj.AsNamesList()
+				if segmentType != spec.SegmentTypeSubnet {
+					items = groupObj.synthesisNamesList()
+				}
+				segments[resourceName] = spec.Segment{Items: items, Type: segmentType}
+				if groupObj.isNamedByLabel() {
+					labeledSegments[resourceName] = true
+				}
+			case member.SynthesisKind() == spec.ResourceTypeSubnet:
 				resourceType = spec.ResourceTypeSubnet
+			default:
+				resourceName, resourceType = member.SynthesisResourceName(), member.SynthesisKind()
 			}
 		}
 	}
 	return
 }
 
-func getSynthesisSpec(groupedLines []*groupedConnLine, grouping bool) *spec.Spec {
+// getSynthesisSpec returns the synthesis spec of the grouped lines, and the names of its segments of groups named by
+// a user-defined label, which are kept in the output
+func getSynthesisSpec(groupedLines []*groupedConnLine, grouping bool) (*spec.Spec, map[string]bool) {
 	s := spec.Spec{}
 	connLines := []spec.SpecRequiredConnectionsElem{}
 	externals := spec.SpecExternals{}
 	segments := spec.SpecSegments{}
+	labeledSegments := map[string]bool{}
+	if grouping {
+		groupedLines = splitMixedGroups(groupedLines)
+	}
 	sortGroupedLines(groupedLines)
 	bidirectionalMap := makeBidirectionalMap(groupedLines)
 	for _, groupedLine := range groupedLines {
 		if groupedLine.CommonProperties.Conn.isEmpty() {
 			continue
 		}
-		srcName, srcType := handleNameAndType(groupedLine.Src, externals, segments, grouping)
-		dstName, dstType := handleNameAndType(groupedLine.Dst, externals, segments, grouping)
+		srcName, srcType := handleNameAndType(groupedLine.Src, externals, segments, labeledSegments, grouping)
+		dstName, dstType := handleNameAndType(groupedLine.Dst, externals, segments, labeledSegments, grouping)
 		bidirectional, ok := bidirectionalMap[getBidirectionalMapKeyByConnLine(groupedLine, false)]
 
 		if !ok {
@@ -102,7 +118,50 @@ func getSynthesisSpec(groupedLines []*groupedConnLine, grouping bool) *spec.Spec
 	s.Externals = externals
 	s.RequiredConnections = connLines
 	s.Segments = segments
-	return &s
+	return &s, labeledSegments
+}
+
+// segmentTypes maps the kinds of the resources that can be grouped to a synthesis segment to the types of the segment
+var segmentTypes = map[spec.ResourceType]spec.SegmentType{
+	spec.ResourceTypeSubnet:   spec.SegmentTypeSubnet,
+	spec.ResourceTypeInstance: spec.SegmentTypeInstance,
+	spec.ResourceTypeNif:      spec.SegmentTypeNif,
+	spec.ResourceTypeVpe:      spec.SegmentTypeVpe,
+}
+
+// isMixedGroup returns true if the element is a group of members that can not form a synthesis segment,
+// i.e. members of different kinds, or of a kind with no segment type
+func isMixedGroup(ep EndpointElem) bool {
+	group, ok := ep.(*groupedEndpointsElems)
+	if !ok || len(*group) < 2 {
+		return false
+	}
+	kind := (*group)[0].SynthesisKind()
+	if _, ok := segmentTypes[kind]; !ok {
+		return true
+	}
+	return slices.ContainsFunc(*group, func(member EndpointElem) bool { return member.SynthesisKind() != kind })
+}
+
+// splitMixedGroups replaces each line from or to a group of endpoints of different kinds (e.g. instances and vpes),
+// which is not expressible as a synthesis segment, with the lines from or to each of its members
+func splitMixedGroups(groupedLines []*groupedConnLine) []*groupedConnLine {
+	res := []*groupedConnLine{}
+	for _, line := range groupedLines {
+		srcs, dsts := []EndpointElem{line.Src}, []EndpointElem{line.Dst}
+		if isMixedGroup(line.Src) {
+			srcs = *line.Src.(*groupedEndpointsElems)
+		}
+		if isMixedGroup(line.Dst) {
+			dsts = *line.Dst.(*groupedEndpointsElems)
+		}
+		for _, src := range srcs {
+			for _, dst := range dsts {
+				res = append(res, &groupedConnLine{Src: src, Dst: dst, CommonProperties: line.CommonProperties})
+			}
+		}
+	}
+	return res
 }
 
 func getBidirectionalMapKeyByNames(firstName, secName, conn string) string {
@@ -144,20 +203,22 @@ func getNewExternalOrSegmentName(externalSegmentName, prefixString string, names
 	return name
 }
 
+// renameExternalsAndSegments renames the externals and the segments to short unique names;
+// segments named by a user-defined label keep their names
 func renameExternalsAndSegments(requiredConnections []spec.SpecRequiredConnectionsElem,
-	externalsMap map[string]string, segmentsMap map[string]string) []spec.SpecRequiredConnectionsElem {
+	externalsMap, segmentsMap map[string]string, labeledSegments map[string]bool) []spec.SpecRequiredConnectionsElem {
 	connLines := []spec.SpecRequiredConnectionsElem{}
 	for _, conn := range requiredConnections {
-		switch conn.Src.Type {
-		case spec.ResourceTypeExternal:
+		switch {
+		case conn.Src.Type == spec.ResourceTypeExternal:
 			conn.Src.Name = getNewExternalOrSegmentName(conn.Src.Name, externalString, externalsMap)
-		case spec.ResourceTypeSegment:
+		case conn.Src.Type == spec.ResourceTypeSegment && !labeledSegments[conn.Src.Name]:
 			conn.Src.Name = getNewExternalOrSegmentName(conn.Src.Name, segmentString, segmentsMap)
 		}
-		switch conn.Dst.Type {
-		case spec.ResourceTypeExternal:
+		switch {
+		case conn.Dst.Type == spec.ResourceTypeExternal:
 			conn.Dst.Name = getNewExternalOrSegmentName(conn.Dst.Name, externalString, externalsMap)
-		case spec.ResourceTypeSegment:
+		case conn.Dst.Type == spec.ResourceTypeSegment && !labeledSegments[conn.Dst.Name]:
 			conn.Dst.Name = getNewExternalOrSegmentName(conn.Dst.Name, segmentString, segmentsMap)
 		}
 		connLines = append(connLines, conn)
@@ -43,6 +43,12 @@ func headerOfAnalyzedVPC(uc OutputUseCase, vpcName, vpc2Name string, c1 *VPCConf
 		return fmt.Sprintf("Connectivity diff between VPC %s and VPC %s\n", vpcName, vpc2Name), nil
 	case Explain:
 		return explainHeader(explanation), nil
+	case Exposure:
+		return fmt.Sprintf("External networks exposure for VPC %s\n", vpcName), nil
+	case Filters:
+		return fmt.Sprintf("Connectivity per filter for VPC %s\n", vpcName), nil
+	case RuleUsage:
+		return fmt.Sprintf("Rules usage for VPC %s\n", vpcName), nil
 	}
 	return "", nil // should never get here
 }
@@ -77,7 +83,11 @@ func (t *TextOutputFormatter) WriteOutput(c1, c2 *VPCConfig,
 		out += subnetsConn.GroupedConnectivity.String(c1)
 		hasStatelessConns = subnetsConn.GroupedConnectivity.hasStatelessConns()
 	case SingleSubnet:
-		out += c1.GetConnectivityOutputPerEachSubnetSeparately()
+		singleSubnet, err := newSingleSubnetAnalysis(c1, grouping)
+		if err != nil {
+			return nil, err
+		}
+		out += singleSubnet.String()
 	case SubnetsDiff, EndpointsDiff:
 		diffOut := cfgsDiff.String()
 		if diffOut != "" {
@@ -88,6 +98,25 @@ func (t *TextOutputFormatter) WriteOutput(c1, c2 *VPCConfig,
 		hasStatelessConns = cfgsDiff.hasStatelessConns()
 	case Explain:
 		out += explanation.String(detailExplain)
+	case Exposure:
+		exposure, err := newExposureAnalysis(c1, conn)
+		if err != nil {
+			return nil, err
+		}
+		out += exposure.String()
+		hasStatelessConns = exposure.hasStatelessConns()
+	case Filters:
+		filters, err := newFiltersAnalysis(c1)
+		if err != nil {
+			return nil, err
+		}
+		out += filters.String()
+	case RuleUsage:
+		ruleUsage, err := newRuleUsageAnalysis(c1, conn)
+		if err != nil {
+			return nil, err
+		}
+		out += ruleUsage.String()
 	}
 	// write output to file and return the output string
 	_, err = WriteToFile(out, outFile)